@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+var EditID string
+
+var abandonCmd = &cobra.Command{
+	Use:   "abandon",
+	Short: "Delete edit left behind by a crashed or aborted publish",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return abandon(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(abandonCmd)
+
+	abandonCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	abandonCmd.Flags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	abandonCmd.Flags().StringVar(&EditID, "edit", "", "ID of the edit to delete, as reported by failed publish")
+
+	abandonCmd.MarkFlagRequired("appId")
+	abandonCmd.MarkFlagRequired("edit")
+}
+
+func abandon(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	if err := playstore.DeleteEditByID(ctx, gs, AppID, EditID); err != nil {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(struct {
+			EditID  string `json:"editId"`
+			Deleted bool   `json:"deleted"`
+		}{EditID, true})
+	}
+	fmt.Printf("Edit '%s' deleted\n", EditID)
+	return nil
+}
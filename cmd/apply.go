@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	MetadataDir string
+	Prune       bool
+	DryRun      bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Make store listings match metadata directory",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return apply(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	applyCmd.Flags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	applyCmd.Flags().StringVar(&MetadataDir, "metadata", "", "Metadata directory with <locale>/title.txt, short_description.txt, full_description.txt, video.txt and images/")
+	applyCmd.Flags().BoolVar(&Prune, "prune", false, "Delete listings and images for locales not present in metadata directory")
+	applyCmd.Flags().BoolVar(&DryRun, "dry-run", false, "Only print differences without changing anything")
+
+	applyCmd.MarkFlagRequired("appId")
+	applyCmd.MarkFlagRequired("metadata")
+}
+
+func apply(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	r, err := playstore.Apply(ctx, gs, afero.NewOsFs(), AppID, MetadataDir, Prune, DryRun)
+	if err != nil {
+		return fmt.Errorf("failed applying metadata: %w", err)
+	}
+
+	if JSONOutput {
+		return printJSON(r)
+	}
+	if r.DryRun {
+		fmt.Println("Dry run, no changes made:")
+	}
+	for _, l := range r.Added {
+		fmt.Printf("  + %s\n", l)
+	}
+	for _, l := range r.Updated {
+		fmt.Printf("  ~ %s\n", l)
+	}
+	for _, l := range r.Deleted {
+		fmt.Printf("  - %s\n", l)
+	}
+	for _, l := range r.Unmanaged {
+		fmt.Printf("  ? %s (not present locally, use --prune to delete)\n", l)
+	}
+	fmt.Printf("%d added, %d updated, %d deleted, %d unchanged\n", len(r.Added), len(r.Updated), len(r.Deleted), len(r.Unchanged))
+	if r.Images != nil {
+		fmt.Printf("%d images uploaded, %d unchanged\n", imageCount(r.Images.Uploaded), imageCount(r.Images.Unchanged))
+	}
+	return nil
+}
+
+// imageCount sums image counts of every locale and image type
+func imageCount(counts map[string]map[string]int) int {
+	n := 0
+	for _, types := range counts {
+		for _, c := range types {
+			n += c
+		}
+	}
+	return n
+}
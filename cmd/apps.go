@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+var AppIDs []string
+
+var appsCmd = &cobra.Command{
+	Use:   "apps [appId...]",
+	Short: "Check which applications service account can access",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return probeApps(cmd.Context(), append(AppIDs, args...))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(appsCmd)
+
+	appsCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	appsCmd.Flags().StringArrayVar(&AppIDs, "appId", []string{}, "Application ID to probe e.g. --appId com.sample.app, can be repeated")
+	appsCmd.Flags().BoolVar(&JSONOutput, "json", false, "Print results as JSON")
+	appsCmd.Flags().MarkDeprecated("json", "use --output json")
+
+}
+
+func probeApps(ctx context.Context, ids []string) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	apps, err := playstore.ProbeApps(ctx, gs, ids)
+	if err != nil {
+		return fmt.Errorf("failed probing apps: %w", err)
+	}
+
+	if JSONOutput {
+		return printJSON(apps)
+	}
+	for _, a := range apps {
+		if a.Accessible {
+			fmt.Printf("%-40s ok\n", a.PackageName)
+			continue
+		}
+		fmt.Printf("%-40s %s\n", a.PackageName, a.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+// details set changes, flags left out keep details as they are
+var DetailsChanges playstore.AppDetails
+
+var detailsCmd = &cobra.Command{
+	Use:   "details",
+	Short: "Read and update app contact details and default language",
+}
+
+var detailsGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Print app contact details and default language",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return getDetails(cmd.Context())
+	},
+}
+
+var detailsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set app contact details or default language, keeping the ones not given",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if DetailsChanges == (playstore.AppDetails{}) {
+			return usageError{fmt.Errorf("at least one of --default-language, --contact-email, --contact-phone or --contact-website is required")}
+		}
+		return setDetails(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(detailsCmd)
+	detailsCmd.AddCommand(detailsGetCmd, detailsSetCmd)
+
+	detailsCmd.PersistentFlags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	detailsCmd.PersistentFlags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	detailsCmd.MarkPersistentFlagRequired("appId")
+
+	detailsSetCmd.Flags().StringVar(&DetailsChanges.DefaultLanguage, "default-language", "", "Default language of store listings e.g. en-US")
+	detailsSetCmd.Flags().StringVar(&DetailsChanges.ContactEmail, "contact-email", "", "Contact email shown to users e.g. dev@sample.com")
+	detailsSetCmd.Flags().StringVar(&DetailsChanges.ContactPhone, "contact-phone", "", "Contact phone shown to users e.g. +31201234567")
+	detailsSetCmd.Flags().StringVar(&DetailsChanges.ContactWebsite, "contact-website", "", "Contact website shown to users e.g. https://sample.com")
+	detailsSetCmd.Flags().BoolVar(&DryRunOnly, "dry-run", false, "Only print changes without making them")
+	detailsSetCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept --appId not following Android application ID rules, e.g. of legacy apps")
+	detailsSetCmd.Flags().BoolVar(&NoReviewFallback, "noReviewFallback", false, "Retry commit with changes not sent for review when Play can't send them for review automatically")
+	detailsSetCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
+}
+
+func getDetails(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	d, err := playstore.GetAppDetails(ctx, gs, AppID)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(d)
+	}
+	printDetails(*d)
+	return nil
+}
+
+func setDetails(ctx context.Context) error {
+	opts := []playstore.Option{}
+	if DryRunOnly {
+		opts = append(opts, playstore.DryRun())
+	}
+	if NoReviewFallback {
+		opts = append(opts, playstore.WithNotSentForReviewFallback())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	r, err := playstore.UpdateAppDetails(ctx, gs, AppID, DetailsChanges, opts...)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(r)
+	}
+	switch {
+	case len(r.Changed) == 0:
+		fmt.Println("App details are as given already, nothing changed")
+	case r.DryRun:
+		fmt.Printf("Dry run, would change %s:\n", strings.Join(r.Changed, ", "))
+	default:
+		fmt.Printf("Changed %s:\n", strings.Join(r.Changed, ", "))
+	}
+	printDetails(r.Details)
+	return nil
+}
+
+func printDetails(d playstore.AppDetails) {
+	fmt.Printf("%-17s %s\n", "Default language:", d.DefaultLanguage)
+	fmt.Printf("%-17s %s\n", "Contact email:", d.ContactEmail)
+	fmt.Printf("%-17s %s\n", "Contact phone:", d.ContactPhone)
+	fmt.Printf("%-17s %s\n", "Contact website:", d.ContactWebsite)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+// tracks diff-tracks compares, kept apart from Track so its default doesn't leak between commands
+var DiffTrackA, DiffTrackB string
+
+var diffTracksCmd = &cobra.Command{
+	Use:   "diff-tracks",
+	Short: "Compare latest releases of two tracks e.g. to see what's pending promotion",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return diffTracks(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffTracksCmd)
+
+	diffTracksCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	diffTracksCmd.Flags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	diffTracksCmd.Flags().StringVar(&DiffTrackA, "a", playstore.TrackBeta, "Track changes are promoted from e.g. beta")
+	diffTracksCmd.Flags().StringVar(&DiffTrackB, "b", playstore.TrackProduction, "Track changes are promoted to e.g. production")
+	diffTracksCmd.MarkFlagRequired("appId")
+}
+
+func diffTracks(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	d, err := playstore.DiffTracks(ctx, gs, AppID, DiffTrackA, DiffTrackB)
+	if err != nil {
+		return fmt.Errorf("failed comparing tracks: %w", err)
+	}
+
+	if JSONOutput {
+		return printJSON(d)
+	}
+	for _, tr := range []playstore.TrackRelease{d.A, d.B} {
+		if tr.Release == nil {
+			fmt.Printf("%-12s no releases\n", tr.Track)
+			continue
+		}
+		fraction := ""
+		if tr.Release.UserFraction > 0 {
+			fraction = fmt.Sprintf(" %.1f%% of users", tr.Release.UserFraction*100)
+		}
+		fmt.Printf("%-12s %-12s versionCodes %v%s\n", tr.Track, tr.Release.Status, tr.Release.VersionCodes, fraction)
+	}
+	if d.Same() {
+		fmt.Printf("'%s' and '%s' tracks have the same latest release\n", d.A.Track, d.B.Track)
+		return nil
+	}
+	if len(d.OnlyInA) > 0 {
+		fmt.Printf("only on '%s': versionCodes %v\n", d.A.Track, d.OnlyInA)
+	}
+	if len(d.OnlyInB) > 0 {
+		fmt.Printf("only on '%s': versionCodes %v\n", d.B.Track, d.OnlyInB)
+	}
+	if d.RolloutDiffers {
+		fmt.Println("rollout differs")
+	}
+	for _, n := range d.ReleaseNotes {
+		fmt.Printf("release notes %s:\n  %s: %q\n  %s: %q\n", n.Locale, d.A.Track, n.A, d.B.Track, n.B)
+	}
+	return nil
+}
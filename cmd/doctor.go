@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check credentials can manage the app, printing steps fixing common setup problems",
+	Long: `Check credentials can manage the app by opening an edit and deleting it right away. Common setup problems,
+Play Developer API not enabled for the project, access token missing androidpublisher scope or service account
+not invited to Play Console, are printed with steps fixing them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return doctor(cmd.Context())
+	},
+}
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Print service account credentials authorize as, to invite it in Play Console",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if JSONOutput {
+			return printJSON(struct {
+				Credentials string `json:"credentials"`
+			}{credentialsIdentity()})
+		}
+		fmt.Println(credentialsIdentity())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(whoamiCmd)
+
+	doctorCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	doctorCmd.Flags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+
+	doctorCmd.MarkFlagRequired("appId")
+
+	whoamiCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+}
+
+func doctor(ctx context.Context) error {
+	identity := credentialsIdentity()
+	if !JSONOutput {
+		fmt.Printf("Credentials: %s\n", identity)
+	}
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	// setup problem of the error is printed by Execute
+	if err := playstore.CheckSetup(ctx, gs, AppID); err != nil {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(struct {
+			Credentials string `json:"credentials"`
+			AppID       string `json:"appId"`
+			OK          bool   `json:"ok"`
+		}{identity, AppID, true})
+	}
+	fmt.Printf("App '%s' can be managed with these credentials, setup is complete\n", AppID)
+	return nil
+}
+
+// credentialsIdentity describes credentials newService authorizes with, service account email when known
+func credentialsIdentity() string {
+	if UseADC {
+		return "Application Default Credentials"
+	}
+	from := "environment"
+	credentials, err := playstore.CredentialsFromEnv()
+	if SecretFile != "" {
+		from = fmt.Sprintf("'%s'", SecretFile)
+		credentials, err = os.ReadFile(SecretFile)
+	}
+	if err != nil {
+		return fmt.Sprintf("unreadable from %s: %v", from, err)
+	}
+	email, err := playstore.ServiceAccountEmail(credentials)
+	if err != nil {
+		return fmt.Sprintf("unknown service account from %s: %v", from, err)
+	}
+	return fmt.Sprintf("service account %s from %s", email, from)
+}
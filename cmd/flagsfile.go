@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// FlagsFile file with flags, expanded ahead of cobra and registered for help only
+var FlagsFile string
+
+const flagsFileFlag = "--flags-file"
+
+// expandFlagsFiles replaces @file and --flags-file arguments with flags read from file, so long
+// commands fit CI systems limiting command line length
+func expandFlagsFiles(fs afero.Fs, args []string) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var path string
+		switch {
+		case arg == "--":
+			return append(expanded, args[i:]...), nil
+		case strings.HasPrefix(arg, flagsFileFlag+"="):
+			path = strings.TrimPrefix(arg, flagsFileFlag+"=")
+		case arg == flagsFileFlag:
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("flag needs an argument: %s", flagsFileFlag)
+			}
+			i++
+			path = args[i]
+		case strings.HasPrefix(arg, "@") && len(arg) > 1:
+			path = arg[1:]
+		default:
+			expanded = append(expanded, arg)
+			continue
+		}
+		flags, err := readFlagsFile(fs, path)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, flags...)
+	}
+	return expanded, nil
+}
+
+// readFlagsFile reads one flag per line, e.g. '--track beta' or '--appBin=app.aab=mapping.txt', boolean
+// flags given value with '=' only. Blank lines and lines starting with '#' are skipped, value wrapped in
+// quotes is unquoted
+func readFlagsFile(fs afero.Fs, path string) ([]string, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open flags file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	var flags []string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, "-") {
+			return nil, fmt.Errorf("flags file '%s' line %d: '%s' is not a flag", path, n, line)
+		}
+		if line == flagsFileFlag || strings.HasPrefix(line, flagsFileFlag+"=") || strings.HasPrefix(line, flagsFileFlag+" ") {
+			return nil, fmt.Errorf("flags file '%s' line %d: flags files can't be nested", path, n)
+		}
+		i := strings.IndexAny(line, "= \t")
+		switch {
+		case i < 0:
+			flags = append(flags, line)
+		case line[i] == '=':
+			flags = append(flags, line[:i]+"="+unquote(line[i+1:]))
+		default:
+			flags = append(flags, line[:i], unquote(strings.TrimSpace(line[i+1:])))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read flags file '%s': %w", path, err)
+	}
+	return flags, nil
+}
+
+// unquote strips quotes value is wrapped in
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var ImagesDir string
+
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Manage store listing images",
+}
+
+var imagesPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Upload images from <locale>/<imageType>/ directories, replacing images of those types on Play",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pushImages(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(imagesCmd)
+	imagesCmd.AddCommand(imagesPushCmd)
+
+	imagesCmd.PersistentFlags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	imagesCmd.PersistentFlags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	imagesCmd.MarkPersistentFlagRequired("appId")
+
+	imagesPushCmd.Flags().StringVar(&ImagesDir, "images", "", "Images directory e.g. images/en-US/phoneScreenshots/1.png")
+	imagesPushCmd.Flags().BoolVar(&JSONOutput, "json", false, "Print report as JSON")
+	imagesPushCmd.Flags().MarkDeprecated("json", "use --output json")
+	imagesPushCmd.MarkFlagRequired("images")
+}
+
+func pushImages(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	r, err := playstore.PushImages(ctx, gs, afero.NewOsFs(), AppID, ImagesDir)
+	if err != nil {
+		return fmt.Errorf("failed pushing images: %w", err)
+	}
+
+	if JSONOutput {
+		return printJSON(r)
+	}
+	locales := make([]string, 0, len(r.Uploaded))
+	for l := range r.Uploaded {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	for _, l := range locales {
+		types := make([]string, 0, len(r.Uploaded[l]))
+		for t := range r.Uploaded[l] {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		for _, t := range types {
+			fmt.Printf("%-10s %-22s %d uploaded, %d replaced\n", l, t, r.Uploaded[l][t], r.Deleted[l][t])
+		}
+	}
+	return nil
+}
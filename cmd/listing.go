@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var listingCmd = &cobra.Command{
+	Use:   "listing",
+	Short: "Sync store listings with fastlane style metadata directory",
+}
+
+var listingPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Update store listings from metadata directory, same as apply",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return apply(cmd.Context())
+	},
+}
+
+var listingPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Download store listings into metadata directory",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pullListings(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listingCmd)
+	listingCmd.AddCommand(listingPushCmd)
+	listingCmd.AddCommand(listingPullCmd)
+
+	listingCmd.PersistentFlags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	listingCmd.PersistentFlags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	listingCmd.PersistentFlags().StringVar(&MetadataDir, "metadata", "", "Metadata directory with <locale>/title.txt, short_description.txt, full_description.txt, video.txt and images/")
+	listingCmd.MarkPersistentFlagRequired("appId")
+	listingCmd.MarkPersistentFlagRequired("metadata")
+
+	listingPushCmd.Flags().BoolVar(&Prune, "prune", false, "Delete listings and images for locales not present in metadata directory")
+	listingPushCmd.Flags().BoolVar(&DryRun, "dry-run", false, "Only print differences without changing anything")
+}
+
+func pullListings(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	listings, err := playstore.PullListings(ctx, gs, afero.NewOsFs(), AppID, MetadataDir)
+	if err != nil {
+		return fmt.Errorf("failed pulling listings: %w", err)
+	}
+	if JSONOutput {
+		return printJSON(listings)
+	}
+	for _, l := range listings {
+		fmt.Printf("  %s %s\n", l.Locale, l.Title)
+	}
+	fmt.Printf("%d listings written to '%s'\n", len(listings), MetadataDir)
+	return nil
+}
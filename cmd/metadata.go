@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// track to set release notes on, kept apart from Track so its default doesn't leak between commands
+var MetadataTrack string
+
+var metadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Manage store metadata without uploading binaries",
+}
+
+var metadataPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Update listings, images and release notes in a single edit without uploading binaries",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pushMetadata(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(metadataCmd)
+	metadataCmd.AddCommand(metadataPushCmd)
+
+	metadataCmd.PersistentFlags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	metadataCmd.PersistentFlags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	metadataCmd.MarkPersistentFlagRequired("appId")
+
+	metadataPushCmd.Flags().StringVar(&MetadataDir, "metadata", "", "Metadata directory with <locale>/title.txt, short_description.txt, full_description.txt, video.txt and images/")
+	metadataPushCmd.Flags().StringVar(&MetadataTrack, "track", "", "Track whose latest release gets --releaseNotes or --changelogs e.g. beta")
+	metadataPushCmd.Flags().StringToStringVar(&ReleaseNotes, "releaseNotes", map[string]string{}, "Release notes by locale e.g. --releaseNotes en-US='Bug fixes'")
+	metadataPushCmd.Flags().StringVar(&Changelogs, "changelogs", "", "Directory with release notes as <versionCode>/<locale>.txt, default/<locale>.txt used when no versionCode matches")
+	metadataPushCmd.Flags().BoolVar(&DryRunOnly, "dry-run", false, "Only print changes without making them")
+	metadataPushCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept --appId not following Android application ID rules, e.g. of legacy apps")
+	metadataPushCmd.Flags().BoolVar(&NoReviewFallback, "noReviewFallback", false, "Retry commit with changes not sent for review when Play can't send them for review automatically")
+	metadataPushCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
+}
+
+func pushMetadata(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	opts := []playstore.Option{playstore.ReleaseNotes(ReleaseNotes), playstore.WithChangelogs(Changelogs)}
+	if DryRunOnly {
+		opts = append(opts, playstore.DryRun())
+	}
+	if NoReviewFallback {
+		opts = append(opts, playstore.WithNotSentForReviewFallback())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	r, err := playstore.PushMetadata(ctx, gs, afero.NewOsFs(), AppID, MetadataDir, MetadataTrack, opts...)
+	if err != nil {
+		return fmt.Errorf("failed pushing metadata: %w", err)
+	}
+
+	if JSONOutput {
+		return printJSON(r)
+	}
+	if r.DryRun {
+		fmt.Println("Dry run, no changes made:")
+	}
+	if l := r.Listings; l != nil {
+		fmt.Printf("listings: %d added, %d updated, %d unchanged\n", len(l.Added), len(l.Updated), len(l.Unchanged))
+		if l.Images != nil {
+			fmt.Printf("images: %d uploaded, %d unchanged\n", imageCount(l.Images.Uploaded), imageCount(l.Images.Unchanged))
+		}
+	}
+	if r.Track != "" {
+		fmt.Printf("release notes: %s on '%s' track appVersions %v\n", strings.Join(r.NoteLocales, ", "), r.Track, r.VersionCodes)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	PromoteFrom string
+	PromoteTo   string
+	// countries release is narrowed to, shared by promote, publish and rollout countries
+	Countries []string
+	// country targeted release also available in countries Play adds later
+	RestOfWorld bool
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Promote latest release from one track to another without uploading binaries",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return promote(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promoteCmd)
+
+	promoteCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	promoteCmd.Flags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	promoteCmd.Flags().StringVar(&PromoteFrom, "from", playstore.TrackInternal, "Track to take release from")
+	promoteCmd.Flags().StringVar(&PromoteTo, "to", "", "Track to release to e.g. beta")
+	promoteCmd.Flags().BoolVar(&ConfirmProduction, "confirm-production", false, "Acknowledge promoting to production track")
+	promoteCmd.Flags().BoolVar(&CustomTracks, "custom-tracks", false, "Allow closed testing tracks created in Play Console e.g. qa-team as --to")
+	promoteCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept --appId not following Android application ID rules, e.g. of legacy apps")
+	promoteCmd.Flags().Float64Var(&RolloutFraction, "rolloutFraction", 0, "Release as staged rollout to given share of users e.g. 0.05, draft release if not set")
+	promoteCmd.Flags().IntVar(&UpdatePriority, "updatePriority", 0, "In-app update priority of the release from 0 to 5, 5 for immediate updates e.g. of critical fixes")
+	promoteCmd.Flags().StringVar(&ReleaseStatus, "status", "", "Release status: draft, completed, inProgress or halted, draft or inProgress with --rolloutFraction if not set")
+	promoteCmd.Flags().StringToStringVar(&ReleaseNotes, "releaseNotes", map[string]string{}, "Fresh release notes by locale instead of source release ones e.g. --releaseNotes en-US='Bug fixes'")
+	promoteCmd.Flags().StringVar(&Changelogs, "changelogs", "", "Directory with fresh release notes as <versionCode>/<locale>.txt, default/<locale>.txt used when no versionCode matches")
+	promoteCmd.Flags().StringVar(&ReleaseName, "releaseName", "", "Fresh name of the release instead of source release one e.g. '2.3.0'")
+	promoteCmd.Flags().StringVar(&ManagedPublishing, "managedPublishing", "", "App managed publishing setting, on or off, to report whether committed changes are live")
+	promoteCmd.Flags().BoolVar(&StrictManagedPublishing, "strictManagedPublishing", false, "Fail when Play reports managed publishing differing from --managedPublishing")
+	promoteCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
+	promoteCmd.Flags().StringSliceVar(&Countries, "countries", []string{}, "Only release in given countries e.g. NL,BE, expand later with rollout countries")
+	promoteCmd.Flags().BoolVar(&RestOfWorld, "include-rest-of-world", false, "Also release in countries Play adds later, with --countries")
+	promoteCmd.Flags().StringVar(&PinFile, "pin", "", "Promote exactly binaries in pin file written by upload --pin, instead of latest --from release")
+	promoteCmd.Flags().StringVar(&Profile, "profile", "", "Form factor preset e.g. 'wear' for Wear OS tracks")
+
+	promoteCmd.MarkFlagRequired("appId")
+	promoteCmd.MarkFlagRequired("to")
+}
+
+func promote(ctx context.Context) error {
+	pr, err := playstore.ProfileByName(Profile)
+	if err != nil {
+		return err
+	}
+	opts := []playstore.Option{
+		playstore.WithProfile(pr),
+		playstore.WithRolloutFraction(RolloutFraction),
+		playstore.WithUpdatePriority(UpdatePriority),
+		playstore.WithReleaseStatus(ReleaseStatus),
+		playstore.WithManagedPublishing(ManagedPublishing, StrictManagedPublishing),
+		playstore.ReleaseNotes(ReleaseNotes),
+		playstore.WithChangelogs(Changelogs),
+		playstore.WithReleaseName(ReleaseName),
+	}
+	if ConfirmProduction {
+		opts = append(opts, playstore.AllowProduction())
+	}
+	if CustomTracks {
+		opts = append(opts, playstore.WithCustomTracks())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	if len(Countries) > 0 {
+		opts = append(opts, playstore.WithCountries(Countries...))
+	}
+	if RestOfWorld {
+		opts = append(opts, playstore.WithRestOfWorld())
+	}
+	if PinFile != "" {
+		pin, err := playstore.ReadPin(afero.NewOsFs(), PinFile)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, playstore.WithPin(pin))
+	}
+
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	versions, err := playstore.Promote(ctx, gs, afero.NewOsFs(), AppID, PromoteFrom, PromoteTo, opts...)
+	if err != nil {
+		return fmt.Errorf("failed promoting release: %w", err)
+	}
+	if JSONOutput {
+		return printJSON(struct {
+			From         string  `json:"from"`
+			To           string  `json:"to"`
+			VersionCodes []int64 `json:"versionCodes"`
+		}{PromoteFrom, PromoteTo, versions})
+	}
+	fmt.Printf("Promoted appVersions %v from '%s' to '%s'\n", versions, PromoteFrom, PromoteTo)
+	return nil
+}
@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/sigitas-plk/playstore/playstore"
 	"github.com/spf13/afero"
@@ -14,37 +21,156 @@ var (
 	AppID      string
 	AppBinOnly []string
 	AppBin     map[string]string
-	Track      string
-	IsApk      bool
-	Verbose    bool
+	// binaries in Cloud Storage as gs://bucket/app.aab, optionally =gs://bucket/mapping.txt
+	GCSBin  []string
+	Track   string
+	IsApk   bool
+	Verbose bool
+	Profile string
+	Mapping string
+	// retry commit with changes not sent for review if Play can't send them automatically
+	NoReviewFallback bool
+	// commit with changes not sent for review, to be sent for review from Play Console
+	NoReview          bool
+	ConfirmProduction bool
+	UploadOrder       string
+	Priority          map[string]int
+	RolloutFraction   float64
+	UpdatePriority    int
+	ReleaseStatus     string
+	DraftOnlyRelease  bool
+	RunID             string
+	ResumeState       string
+	KeepDownloads     bool
+	// stream binaries given as URLs straight to Play instead of downloading them first
+	Stream       bool
+	SkipExisting bool
+	// app managed publishing setting, on or off, and whether to fail when Play contradicts it
+	ManagedPublishing       string
+	StrictManagedPublishing bool
+	ReleaseNotes            map[string]string
+	BinaryNotes             []string
+	Changelogs              string
+	ReleaseName             string
+	// release notes lint rules and whether to fail publish on violations
+	NotesMarkers       []string
+	NotesTicketPattern string
+	RequiredLocales    []string
+	Strict             bool
+	UploadTimeout      time.Duration
+	ValidateTimeout    time.Duration
+	CommitTimeout      time.Duration
+	ChunkRetryDeadline time.Duration
+	ChunkSize          int
+	MaxSize            string
+	Receipt            string
+	Retries            int
+	DryRunOnly         bool
+	Parallel           int
+	PinFile            string
+	IntegrityRetry     bool
+	MainObb            map[string]string
+	PatchObb           map[string]string
+	ProgressInterval   time.Duration
+	ProgressMinBytes   string
+	// JSON file upload progress is kept in for dashboards, see playstore.UploadStatus
+	StatusFile string
+	// webhook told publish outcome, e.g. Slack incoming webhook
+	NotifyURL string
+	// accept closed testing track names, checked against app tracks on Play
+	CustomTracks bool
+	// accept package names breaking Android application ID rules
+	AnyPackageName bool
+	// record digests of mappings and expansion files in receipt
+	FileDigests bool
+	// read app details before uploading to fail early without access to the app
+	CheckAccess bool
+	// upload again in new edit when edit expires during long uploads
+	RecreateEdit bool
+	// receipts, pins and state files are written to it, embedding applications may replace it
+	OutputFs afero.Fs = afero.NewOsFs()
 )
 
 var pstoreCmd = &cobra.Command{
 	Use:   "pstore",
 	Short: "Test CLI for appstore upload",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(AppBinOnly) == 0 && len(AppBin) == 0 {
-			return errors.New("at leat one binary file to upload is required")
+		if len(AppBinOnly) == 0 && len(AppBin) == 0 && len(GCSBin) == 0 {
+			return usageError{errors.New("at leat one binary file to upload is required")}
 		}
-		return upload()
+		return upload(cmd.Context())
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(pstoreCmd)
 
-	pstoreCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file")
+	pstoreCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
 	pstoreCmd.Flags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
 	pstoreCmd.Flags().StringArrayVar(&AppBinOnly, "appBinOnly", []string{}, "Path to binary file to submit e.g. --appBinOnly my/app/path.aab")
 	pstoreCmd.Flags().StringToStringVar(&AppBin, "appBin", map[string]string{}, "Key value pair with path to binary as key and its mappings as value. e.g. --appBin my/app/path.aab=may/mappings/mapth.txt")
+	pstoreCmd.Flags().StringArrayVar(&GCSBin, "gcs", []string{}, "Binary in Cloud Storage read with the same credentials, mappings optional, can be repeated e.g. --gcs gs://ci/app.aab=gs://ci/mapping.txt")
+	pstoreCmd.Flags().StringVar(&Mapping, "mapping", "", "Path to mappings shared by every binary without its own e.g. multi-apk ABI splits")
+	pstoreCmd.Flags().StringVar(&Track, "track", playstore.TrackInternal, "Track to publish binaries to e.g. internal, alpha, beta, production")
+	pstoreCmd.Flags().BoolVar(&ConfirmProduction, "confirm-production", false, "Acknowledge publishing to production track")
+	pstoreCmd.Flags().BoolVar(&CustomTracks, "custom-tracks", false, "Allow closed testing tracks created in Play Console e.g. qa-team as --track")
+	pstoreCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept --appId not following Android application ID rules, e.g. of legacy apps")
+	pstoreCmd.Flags().StringVar(&UploadOrder, "uploadOrder", playstore.UploadOrderGiven, "Order binaries are uploaded in: given, smallest or priority")
+	pstoreCmd.Flags().StringToIntVar(&Priority, "priority", map[string]int{}, "Upload priority per binary, higher uploads first with --uploadOrder priority e.g. --priority my/app/path.aab=10")
+	pstoreCmd.Flags().StringSliceVar(&Countries, "countries", []string{}, "Only release in given countries e.g. US,DE,LT, expand later with rollout countries")
+	pstoreCmd.Flags().BoolVar(&RestOfWorld, "include-rest-of-world", false, "Also release in countries Play adds later, with --countries")
+	pstoreCmd.Flags().Float64Var(&RolloutFraction, "rolloutFraction", 0, "Release as staged rollout to given share of users e.g. 0.05, draft release if not set")
+	pstoreCmd.Flags().IntVar(&UpdatePriority, "updatePriority", 0, "In-app update priority of the release from 0 to 5, 5 for immediate updates e.g. of critical fixes")
+	pstoreCmd.Flags().StringVar(&ReleaseStatus, "status", "", "Release status: draft, completed, inProgress or halted, draft or inProgress with --rolloutFraction if not set")
+	pstoreCmd.Flags().BoolVar(&DraftOnlyRelease, "draft-only", false, "Only create a draft release to complete in Play Console, refusing --rolloutFraction and --status")
+	pstoreCmd.Flags().StringVar(&ManagedPublishing, "managedPublishing", "", "App managed publishing setting, on or off, to report whether committed changes are live")
+	pstoreCmd.Flags().BoolVar(&StrictManagedPublishing, "strictManagedPublishing", false, "Fail when Play reports managed publishing differing from --managedPublishing")
+	pstoreCmd.Flags().StringToStringVar(&ReleaseNotes, "releaseNotes", map[string]string{}, "Release notes by locale e.g. --releaseNotes en-US='Bug fixes'")
+	pstoreCmd.Flags().StringArrayVar(&BinaryNotes, "binaryNotes", []string{}, "Release notes line of a binary appended to shared notes, can be repeated e.g. --binaryNotes my/wear.apk=en-US:'Wear: fixes crash'")
+	pstoreCmd.Flags().StringVar(&ReleaseName, "releaseName", "", "Name of the release e.g. '2.3.0 (231)', Play names it after the highest appVersion if not set")
+	pstoreCmd.Flags().StringVar(&Changelogs, "changelogs", "", "Directory with release notes as <versionCode>/<locale>.txt, default/<locale>.txt used when no versionCode matches")
+	pstoreCmd.Flags().StringSliceVar(&NotesMarkers, "notesMarkers", playstore.DefaultNotesLint.Markers, "Words release notes must not contain, matched ignoring case")
+	pstoreCmd.Flags().StringVar(&NotesTicketPattern, "notesTicketPattern", playstore.DefaultNotesLint.TicketPattern.String(), "Regular expression of internal ticket IDs release notes must not mention, empty to allow any")
+	pstoreCmd.Flags().StringSliceVar(&RequiredLocales, "requiredLocales", []string{}, "Locales release notes must be given for e.g. en-US,de-DE")
+	pstoreCmd.Flags().BoolVar(&Strict, "strict", false, "Fail publish when release notes break lint rules instead of warning")
+	pstoreCmd.Flags().DurationVar(&UploadTimeout, "uploadTimeout", 0, "Maximum time a single file upload may take e.g. 30m, no limit if not set")
+	pstoreCmd.Flags().DurationVar(&ValidateTimeout, "validateTimeout", 0, "Maximum time validating edit may take, made once more when it runs out e.g. 5m, no limit if not set")
+	pstoreCmd.Flags().DurationVar(&CommitTimeout, "commitTimeout", 0, "Maximum time committing edit may take, made once more when it runs out e.g. 10m, no limit if not set")
+	pstoreCmd.Flags().DurationVar(&ChunkRetryDeadline, "chunkRetryDeadline", 60*time.Second, "How long a failed upload chunk is retried before upload resumes from bytes Play acknowledged, raise for flaky networks")
+	pstoreCmd.Flags().IntVar(&ChunkSize, "chunkSize", -1, "Upload chunk size in bytes, rounded up to 256KiB. 0 uploads file in a single request")
+	pstoreCmd.Flags().IntVar(&Retries, "retries", playstore.DefaultRetryPolicy.Retries, "Times to retry Google API calls failing with 5xx or 429 responses or exceeded per minute quota, 0 to disable")
+	pstoreCmd.Flags().StringVar(&MaxSize, "max-size", "", "Fail before upload if any binary is larger than given size e.g. 200MB")
+	pstoreCmd.Flags().StringVar(&Receipt, "receipt", "", "Write uploaded binaries with their appVersionCode, sha256 and sha1 as JSON to given file")
+	pstoreCmd.Flags().StringVar(&RunID, "runId", os.Getenv("PSTORE_RUN_ID"), "ID tagging this run in logs and receipt, e.g. CI job ID so retries share it. PSTORE_RUN_ID or random UUID if not set")
+	pstoreCmd.Flags().StringVar(&ResumeState, "resume", "", "Record upload progress to given state file and continue interrupted upload from it on the next run, e.g. for very large bundles")
+	pstoreCmd.Flags().BoolVar(&Stream, "stream", false, "Stream binaries given as https:// or gs:// URLs straight to Play without local copies, skipping signing, manifest and duplicate checks")
+	pstoreCmd.Flags().BoolVar(&KeepDownloads, "keep-downloads", false, "Keep local copies of binaries given as https:// or gs:// URLs once upload is done")
+	pstoreCmd.Flags().BoolVar(&DryRunOnly, "dry-run", false, "Upload and validate binaries, then discard the edit instead of committing")
+	pstoreCmd.Flags().IntVar(&Parallel, "parallel", 1, "Number of binaries and mappings uploaded at once")
+	pstoreCmd.Flags().BoolVar(&JSONOutput, "json", false, "Print upload results as JSON")
+	pstoreCmd.Flags().MarkDeprecated("json", "use --output json")
+	pstoreCmd.Flags().StringVar(&PinFile, "pin", "", "Write released appVersions with their digests to given pin file, for promote --pin")
+	pstoreCmd.Flags().BoolVar(&SkipExisting, "skip-existing", false, "Release binaries Play has already under their existing appVersionCode instead of failing. Re-running publish of binaries all live on target track is only a no-op with this flag")
+	pstoreCmd.Flags().BoolVar(&IntegrityRetry, "integrityRetry", false, "Upload binary once more when sha256 on Play doesn't match local file")
+	pstoreCmd.Flags().BoolVar(&RecreateEdit, "recreate-expired-edit", false, "Upload everything again in new edit once when edit expires before publish finishes, e.g. during long uploads")
+	pstoreCmd.Flags().BoolVar(&CheckAccess, "check-access", false, "Read app details before uploading, failing early when credentials can't access --appId")
+	pstoreCmd.Flags().BoolVar(&FileDigests, "file-digests", false, "Log and add sha256 and sha1 of uploaded mappings and expansion files to results and receipt")
+	pstoreCmd.Flags().StringToStringVar(&MainObb, "mainObb", map[string]string{}, "Main expansion file per apk e.g. --mainObb my/app/path.apk=main.obb")
+	pstoreCmd.Flags().StringToStringVar(&PatchObb, "patchObb", map[string]string{}, "Patch expansion file per apk e.g. --patchObb my/app/path.apk=patch.obb")
+	pstoreCmd.Flags().DurationVar(&ProgressInterval, "progressInterval", 3*time.Second, "How often upload progress is drawn e.g. 30s for CI logs")
+	pstoreCmd.Flags().StringVar(&ProgressMinBytes, "progressMinBytes", "", "Only draw upload progress once given amount more was sent e.g. 10MB")
+	pstoreCmd.Flags().StringVar(&StatusFile, "status-file", "", "Keep current upload file, bytes, percentage and ETA as JSON in given file for external systems to display")
+	pstoreCmd.Flags().StringVar(&NotifyURL, "notify-url", "", "POST publish outcome as JSON to given webhook once done, e.g. Slack incoming webhook URL")
 	pstoreCmd.Flags().BoolVar(&IsApk, "apk", false, "Is apk (as opposed to app bundles .aab)")
+	pstoreCmd.Flags().BoolVar(&NoReviewFallback, "noReviewFallback", false, "Retry commit with changes not sent for review when Play can't send them for review automatically")
+	pstoreCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
 	pstoreCmd.Flags().BoolVar(&Verbose, "verbose", false, "Verbose logging")
+	pstoreCmd.Flags().StringVar(&Profile, "profile", "", "Form factor preset e.g. 'wear' for Wear OS standalone apk")
 
-	pstoreCmd.MarkFlagRequired("authFile")
 	pstoreCmd.MarkFlagRequired("appId")
 }
 
-func upload() error {
+func upload(ctx context.Context) error {
 
 	if len(AppBinOnly) != 0 {
 		for _, v := range AppBinOnly {
@@ -55,18 +181,268 @@ func upload() error {
 		}
 	}
 
+	for _, v := range GCSBin {
+		path, mapping, _ := strings.Cut(v, "=")
+		if !isGCS(path) || (mapping != "" && !isGCS(mapping)) {
+			return usageError{fmt.Errorf("gcs binary '%s' is not gs://<bucket>/<path>, optionally =gs://<bucket>/<mapping path>", v)}
+		}
+		AppBin[path] = mapping
+	}
+
+	notes, err := binaryNotes(BinaryNotes)
+	if err != nil {
+		return err
+	}
+
 	files := playstore.Binaries(AppBin)
+	if len(Priority) != 0 || len(MainObb) != 0 || len(PatchObb) != 0 || len(notes) != 0 {
+		files = files[:0]
+		paths := make([]string, 0, len(AppBin))
+		for path := range AppBin {
+			paths = append(paths, path)
+		}
+		// same path order Binaries uses
+		sort.Strings(paths)
+		for _, path := range paths {
+			b := playstore.Prioritized(playstore.BinaryWithMapping(path, AppBin[path]), Priority[path])
+			b = playstore.Noted(b, notes[path])
+			files = append(files, playstore.Expanded(b, MainObb[path], PatchObb[path]))
+		}
+	}
 
-	p, err := playstore.Publish(afero.NewOsFs(), AppID, playstore.TrackInternal, SecretFile, files, IsApk, Verbose)
+	if Stream {
+		for i := range files {
+			files[i] = playstore.Streamed(files[i])
+		}
+	}
+
+	pr, err := playstore.ProfileByName(Profile)
+	if err != nil {
+		return err
+	}
+	lint, err := notesLint()
+	if err != nil {
+		return err
+	}
+
+	opts := []playstore.Option{
+		playstore.WithProfile(pr),
+		playstore.WithUploadOrder(UploadOrder),
+		playstore.WithRolloutFraction(RolloutFraction),
+		playstore.WithUpdatePriority(UpdatePriority),
+		playstore.WithReleaseStatus(ReleaseStatus),
+		playstore.WithManagedPublishing(ManagedPublishing, StrictManagedPublishing),
+		playstore.ReleaseNotes(ReleaseNotes),
+		playstore.WithChangelogs(Changelogs),
+		playstore.WithReleaseName(ReleaseName),
+		playstore.WithNotesLint(lint, Strict),
+		playstore.WithConcurrency(playstore.Concurrency{Uploads: Parallel, Mappings: Parallel, Downloads: Parallel}),
+		playstore.WithOutputFs(OutputFs),
+		playstore.SharedMapping(Mapping),
+	}
+	if usesGCS() {
+		gcs, err := newGCSClient(ctx)
+		if err != nil {
+			return fmt.Errorf("failed creating Cloud Storage client: %w", err)
+		}
+		opts = append(opts, playstore.WithDownloadClient(gcs))
+	}
+	if MaxSize != "" {
+		size, err := playstore.ParseSize(MaxSize)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, playstore.WithMaxSize(size))
+	}
+	var minBytes int64
+	if ProgressMinBytes != "" {
+		if minBytes, err = playstore.ParseSize(ProgressMinBytes); err != nil {
+			return err
+		}
+	}
+	opts = append(opts, playstore.WithProgressInterval(ProgressInterval, minBytes))
+	if StatusFile != "" {
+		opts = append(opts, playstore.WithStatusFile(StatusFile))
+	}
+	if NotifyURL != "" {
+		n, err := webhookNotifier()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, playstore.WithNotifier(n))
+	}
+	if SkipExisting {
+		opts = append(opts, playstore.WithSkipExisting())
+	}
+	if IntegrityRetry {
+		opts = append(opts, playstore.WithIntegrityRetry())
+	}
+	if DryRunOnly {
+		opts = append(opts, playstore.DryRun())
+	}
+	if KeepDownloads {
+		opts = append(opts, playstore.WithKeepDownloads())
+	}
+	if ResumeState != "" {
+		opts = append(opts, playstore.WithResume(ResumeState))
+	}
+	if RunID != "" {
+		opts = append(opts, playstore.WithRunID(RunID))
+	}
+	if DraftOnlyRelease {
+		opts = append(opts, playstore.DraftOnly())
+	}
+	if NoReviewFallback {
+		opts = append(opts, playstore.WithNotSentForReviewFallback())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	if ConfirmProduction {
+		opts = append(opts, playstore.AllowProduction())
+	}
+	if CustomTracks {
+		opts = append(opts, playstore.WithCustomTracks())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	if FileDigests {
+		opts = append(opts, playstore.WithFileDigests())
+	}
+	if len(Countries) > 0 {
+		opts = append(opts, playstore.WithCountries(Countries...))
+	}
+	if RestOfWorld {
+		opts = append(opts, playstore.WithRestOfWorld())
+	}
+	if RecreateEdit {
+		opts = append(opts, playstore.WithEditRecreation())
+	}
+	if CheckAccess {
+		opts = append(opts, playstore.WithAccessCheck())
+	}
+
+	p, err := playstore.Publish(ctx, afero.NewOsFs(), AppID, Track, SecretFile, files, IsApk, Verbose, opts...)
 	if err != nil {
 		return fmt.Errorf("failed validating inputs: %w", err)
 	}
-	gs, err := playstore.NewGEditsService(SecretFile)
+	gs, err := newService(serviceOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	results, err := p.UploadFiles(ctx, gs)
 	if err != nil {
-		return fmt.Errorf("failed creating new playstore service instance: %v", err)
+		var ae *playstore.AbortError
+		if errors.As(err, &ae) {
+			fmt.Fprint(os.Stderr, ae.Report())
+		}
+		return fmt.Errorf("failed uploading files: %w", err)
+	}
+	if PinFile != "" && !DryRunOnly {
+		if err := playstore.WritePin(OutputFs, PinFile, p.Pin(results)); err != nil {
+			return err
+		}
+	}
+	receipt := p.Receipt(results)
+	if Receipt != "" {
+		if err := playstore.WriteReceipt(OutputFs, Receipt, receipt); err != nil {
+			return err
+		}
 	}
-	if err := p.UploadFiles(gs); err != nil {
-		return fmt.Errorf("failed uploading files: %v", err)
+	return printResults(receipt)
+}
+
+// printResults prints uploaded binaries as table, or whole receipt as JSON with --output json
+func printResults(receipt *playstore.Receipt) error {
+	if JSONOutput {
+		return printJSON(receipt)
+	}
+	fmt.Printf("%-12s %-10s %-64s %s\n", "VERSIONCODE", "DURATION", "SHA256", "FILE")
+	for _, r := range receipt.Artifacts {
+		duration := r.Duration.Round(time.Second).String()
+		if r.Skipped {
+			duration = "existing"
+		}
+		fmt.Printf("%-12d %-10s %-64s %s\n", r.VersionCode, duration, r.Sha256, r.Path)
 	}
 	return nil
 }
+
+// binaryNotes parses <path>=<locale>:<text> release notes lines into lines by locale of every binary path
+func binaryNotes(lines []string) (map[string]map[string]string, error) {
+	notes := make(map[string]map[string]string)
+	for _, l := range lines {
+		path, note, ok := strings.Cut(l, "=")
+		locale, text, ok2 := strings.Cut(note, ":")
+		if !ok || !ok2 || path == "" || locale == "" {
+			return nil, usageError{fmt.Errorf("binary notes '%s' are not <path>=<locale>:<text>", l)}
+		}
+		if _, ok := AppBin[path]; !ok {
+			return nil, usageError{fmt.Errorf("binary notes '%s' are for '%s' which isn't uploaded", l, path)}
+		}
+		if notes[path] == nil {
+			notes[path] = make(map[string]string)
+		}
+		notes[path][locale] = text
+	}
+	return notes, nil
+}
+
+// isGCS checks if artifact path is in Cloud Storage
+func isGCS(path string) bool {
+	return strings.HasPrefix(strings.ToLower(path), "gs://")
+}
+
+// usesGCS checks if any binary or mapping is in Cloud Storage
+func usesGCS() bool {
+	if isGCS(Mapping) {
+		return true
+	}
+	for path, mapping := range AppBin {
+		if isGCS(path) || isGCS(mapping) {
+			return true
+		}
+	}
+	return false
+}
+
+// notesLint release notes lint rules set with flags
+func notesLint() (playstore.NotesLint, error) {
+	lint := playstore.NotesLint{Markers: NotesMarkers, RequiredLocales: RequiredLocales}
+	if NotesTicketPattern != "" {
+		re, err := regexp.Compile(NotesTicketPattern)
+		if err != nil {
+			return lint, usageError{fmt.Errorf("notes ticket pattern '%s' is not a regular expression: %w", NotesTicketPattern, err)}
+		}
+		lint.TicketPattern = re
+	}
+	return lint, nil
+}
+
+// serviceOptions Google API service options set with flags
+func serviceOptions() []playstore.ServiceOption {
+	opts := []playstore.ServiceOption{
+		playstore.WithChunkRetryDeadline(ChunkRetryDeadline),
+		playstore.WithUploadTimeout(UploadTimeout),
+		playstore.WithEditCallTimeouts(ValidateTimeout, CommitTimeout),
+	}
+	if ChunkSize >= 0 {
+		opts = append(opts, playstore.WithChunkSize(ChunkSize))
+	}
+	if Retries > 0 {
+		rp := playstore.DefaultRetryPolicy
+		rp.Retries = Retries
+		opts = append(opts, playstore.WithRetryPolicy(rp))
+	}
+	return opts
+}
+
+// webhookNotifier notifier posting to --notify-url
+func webhookNotifier() (playstore.Notifier, error) {
+	u, err := url.Parse(NotifyURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, usageError{fmt.Errorf("notify url '%s' is not http(s)://<host>/<path>", NotifyURL)}
+	}
+	return &playstore.WebhookNotifier{URL: NotifyURL}, nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// batch config file with apps to publish, see playstore.BatchConfig
+	BatchConfig string
+	FailFast    bool
+	// environment block of batch config to publish, e.g. staging or prod
+	BatchEnv string
+	// package names template of batch config is published to, instead of apps of config
+	TemplateAppIDs []string
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish binaries of multiple apps listed in a config file, e.g. white-label variants",
+	Long: `Publish binaries of multiple apps listed in a config file, each with its own edit. Config is YAML for .yaml
+and .yml files, JSON otherwise, with the same keys in both e.g.
+
+  {
+    "failFast": false,
+    "apps": [
+      {"appId": "com.sample.red", "track": "beta", "binaries": [{"path": "red.aab", "mapping": "red.txt"}]},
+      {"appId": "com.sample.blue", "binaries": [{"path": "blue.aab"}], "rolloutFraction": 0.1}
+    ],
+    "environments": {
+      "staging": {"track": "internal"},
+      "prod": {"authFile": "prod.json", "apps": [{"appId": "com.sample.red.prod", "binaries": [{"path": "red.aab"}]}]}
+    }
+  }
+
+Environment selected with --env replaces authFile, failFast and apps it sets, its track applies to apps without one.
+
+Apps sharing binaries layout and metadata, e.g. white-label flavors, can be given as template published to
+every appId, '{appId}' in paths replaced with it and overrides applied per appId:
+
+  {
+    "template": {"track": "beta", "binaries": [{"path": "build/{appId}.aab"}], "releaseNotes": {"en-US": "Bug fixes"}},
+    "appIds": ["com.sample.red", "com.sample.blue"],
+    "overrides": {"com.sample.blue": {"rolloutFraction": 0.1, "releaseNotes": {"de-DE": "Fehlerbehebungen"}}}
+  }
+
+--appIds publishes template to the given appIds instead of apps of config.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return publishBatch(cmd.Context(), cmd.Flags().Changed("fail-fast"), cmd.Flags().Changed("authFile"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+
+	publishCmd.Flags().StringVar(&BatchConfig, "config", "", "Batch config file listing apps and their binaries, YAML (.yaml, .yml) or JSON")
+	publishCmd.Flags().StringSliceVar(&TemplateAppIDs, "appIds", []string{}, "Publish template of config to these appIds instead of apps of config e.g. com.sample.red,com.sample.blue")
+	publishCmd.Flags().StringVar(&BatchEnv, "env", "", "Environment of config to publish e.g. staging or prod")
+	publishCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, overrides authFile of config. PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if neither set")
+	publishCmd.Flags().BoolVar(&FailFast, "fail-fast", false, "Stop at first app failing to publish, overrides failFast of config")
+	publishCmd.Flags().BoolVar(&ConfirmProduction, "confirm-production", false, "Acknowledge publishing to production track")
+	publishCmd.Flags().BoolVar(&CustomTracks, "custom-tracks", false, "Allow closed testing tracks created in Play Console e.g. qa-team as track")
+	publishCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept appId of config apps not following Android application ID rules, e.g. of legacy apps")
+	publishCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
+	publishCmd.Flags().BoolVar(&DryRunOnly, "dry-run", false, "Upload and validate binaries, then discard the edits instead of committing")
+	publishCmd.Flags().StringVar(&NotifyURL, "notify-url", "", "POST publish outcome of every app as JSON to given webhook once done, e.g. Slack incoming webhook URL")
+	publishCmd.Flags().StringVar(&RunID, "runId", os.Getenv("PSTORE_RUN_ID"), "ID tagging this run in logs and receipts, PSTORE_RUN_ID or random UUID if not set")
+	publishCmd.Flags().IntVar(&Parallel, "parallel", 1, "Number of binaries and mappings of an app uploaded at once")
+	publishCmd.Flags().BoolVar(&Verbose, "verbose", false, "Verbose logging")
+
+	publishCmd.MarkFlagRequired("config")
+}
+
+func publishBatch(ctx context.Context, failFastSet, authFileSet bool) error {
+	config, err := playstore.LoadBatchConfig(afero.NewOsFs(), BatchConfig)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(TemplateAppIDs) > 0 {
+		if config.Template == nil {
+			return usageError{fmt.Errorf("batch config '%s' has no template to publish to --appIds", BatchConfig)}
+		}
+		if config.Apps, err = playstore.TemplateApps(*config.Template, TemplateAppIDs, config.Overrides); err != nil {
+			return usageError{err}
+		}
+	}
+	if BatchEnv != "" {
+		if config, err = config.Environment(BatchEnv); err != nil {
+			return usageError{err}
+		}
+	} else if len(config.Apps) == 0 {
+		return usageError{fmt.Errorf("batch config '%s' has apps in environments only, select one with --env", BatchConfig)}
+	}
+	if failFastSet {
+		config.FailFast = FailFast
+	}
+	if !authFileSet && config.AuthFile != "" {
+		SecretFile = config.AuthFile
+	}
+
+	opts := []playstore.Option{
+		playstore.WithConcurrency(playstore.Concurrency{Uploads: Parallel, Mappings: Parallel, Downloads: Parallel}),
+		playstore.WithOutputFs(OutputFs),
+	}
+	if DryRunOnly {
+		opts = append(opts, playstore.DryRun())
+	}
+	if RunID != "" {
+		opts = append(opts, playstore.WithRunID(RunID))
+	}
+	if ConfirmProduction {
+		opts = append(opts, playstore.AllowProduction())
+	}
+	if CustomTracks {
+		opts = append(opts, playstore.WithCustomTracks())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	if NotifyURL != "" {
+		n, err := webhookNotifier()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, playstore.WithNotifier(n))
+	}
+
+	gs, err := newService(serviceOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	report := playstore.PublishBatch(ctx, afero.NewOsFs(), gs, SecretFile, config.Apps, config.FailFast, Verbose, opts...)
+	// with json output failed batch is printed as error, see errorOutput
+	if err := report.Err(); err != nil && JSONOutput {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(report)
+	}
+	printBatchReport(report)
+	return report.Err()
+}
+
+// printBatchReport prints outcome of every app as table
+func printBatchReport(report *playstore.BatchReport) {
+	fmt.Printf("%-32s %-12s %-10s %s\n", "APP", "TRACK", "STATUS", "DETAILS")
+	for _, a := range report.Apps {
+		status, details := "published", ""
+		switch {
+		case a.Skipped:
+			status = "skipped"
+		case a.Err != nil:
+			status, details = "failed", a.Error
+		default:
+			for _, r := range a.Receipt.Artifacts {
+				details += fmt.Sprintf("%d ", r.VersionCode)
+			}
+		}
+		fmt.Printf("%-32s %-12s %-10s %s\n", a.PackageName, a.Track, status, details)
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ReviewsWindow time.Duration
+	// reviews listed are modified since, e.g. 7d, 36h or 2024-05-01
+	ReviewsSince string
+	ReviewID     string
+	ReplyText    string
+)
+
+var reviewsCmd = &cobra.Command{
+	Use:   "reviews",
+	Short: "Read and reply to user reviews",
+}
+
+var reviewsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print recent reviews, most recently modified first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := parseSince(ReviewsSince, time.Now())
+		if err != nil {
+			return usageError{err}
+		}
+		return listReviews(cmd.Context(), since)
+	},
+}
+
+var reviewsGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Print review by ID",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return getReview(cmd.Context())
+	},
+}
+
+var reviewsReplyCmd = &cobra.Command{
+	Use:   "reply",
+	Short: "Reply to review, replacing previous reply",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return replyToReview(cmd.Context())
+	},
+}
+
+var reviewsStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Print review count per star rating and average rating of recent reviews",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return reviewStats(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reviewsCmd)
+	reviewsCmd.AddCommand(reviewsStatsCmd, reviewsListCmd, reviewsGetCmd, reviewsReplyCmd)
+
+	reviewsCmd.PersistentFlags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	reviewsCmd.PersistentFlags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	reviewsCmd.MarkPersistentFlagRequired("appId")
+
+	reviewsStatsCmd.Flags().DurationVar(&ReviewsWindow, "window", 7*24*time.Hour, "Only count reviews modified within window, Play lists last week at most")
+	reviewsStatsCmd.Flags().BoolVar(&JSONOutput, "json", false, "Print stats as JSON")
+	reviewsStatsCmd.Flags().MarkDeprecated("json", "use --output json")
+
+	reviewsListCmd.Flags().StringVar(&ReviewsSince, "since", "7d", "Only list reviews modified since duration ago or date e.g. 7d, 36h, 2024-05-01. Play lists last week at most")
+
+	reviewsGetCmd.Flags().StringVar(&ReviewID, "id", "", "Review ID")
+	reviewsGetCmd.MarkFlagRequired("id")
+
+	reviewsReplyCmd.Flags().StringVar(&ReviewID, "id", "", "Review ID")
+	reviewsReplyCmd.Flags().StringVar(&ReplyText, "text", "", "Reply text, at most 350 characters")
+	reviewsReplyCmd.MarkFlagRequired("id")
+	reviewsReplyCmd.MarkFlagRequired("text")
+}
+
+func reviewStats(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	s, err := playstore.ReviewStatsSince(ctx, gs, AppID, time.Now().Add(-ReviewsWindow))
+	if err != nil {
+		return err
+	}
+
+	if JSONOutput {
+		return printJSON(s)
+	}
+	for stars := int64(5); stars >= 1; stars-- {
+		fmt.Printf("%-5s %d\n", strings.Repeat("*", int(stars)), s.ByStars[stars])
+	}
+	fmt.Printf("%d reviews since %s, %.2f average\n", s.Total, s.Since.Format(time.RFC3339), s.Average)
+	return nil
+}
+
+func listReviews(ctx context.Context, since time.Time) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	reviews, err := playstore.ListReviews(ctx, gs, AppID, since)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(reviews)
+	}
+	for _, r := range reviews {
+		printReview(r)
+	}
+	fmt.Printf("%d reviews since %s\n", len(reviews), since.Format(time.RFC3339))
+	return nil
+}
+
+func getReview(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	r, err := playstore.GetReview(ctx, gs, AppID, ReviewID)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(r)
+	}
+	printReview(*r)
+	return nil
+}
+
+func replyToReview(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	if err := playstore.ReplyToReview(ctx, gs, AppID, ReviewID, ReplyText); err != nil {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(struct {
+			ID      string `json:"id"`
+			Replied bool   `json:"replied"`
+		}{ReviewID, true})
+	}
+	fmt.Printf("Replied to review '%s'\n", ReviewID)
+	return nil
+}
+
+// printReview prints review with its rating, author and reply
+func printReview(r playstore.Review) {
+	fmt.Printf("%s %-5s %s %s (appVersion %d)\n", r.ID, strings.Repeat("*", int(r.StarRating)), r.LastModified.Format(time.RFC3339), r.Author, r.AppVersionCode)
+	fmt.Printf("  %s\n", r.Text)
+	if r.Reply != "" {
+		fmt.Printf("  reply: %s\n", r.Reply)
+	}
+}
+
+// parseSince reads time reviews are listed since, as date or duration before now with days allowed e.g. 7d
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, ok := strings.CutSuffix(s, "d"); ok {
+		if days, err := strconv.Atoi(d); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("since '%s' is neither duration e.g. 7d, 36h nor date e.g. 2024-05-01", s)
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// track to change rollout on, kept apart from Track so its default doesn't leak between commands
+	RolloutTrack string
+	// release country targeting is removed instead of expanded
+	AllCountries bool
+)
+
+var rolloutCmd = &cobra.Command{
+	Use:   "rollout",
+	Short: "Manage staged rollouts",
+}
+
+var rolloutSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Raise user fraction of the in progress release without uploading anything",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setRollout(cmd.Context())
+	},
+}
+
+var rolloutCountriesCmd = &cobra.Command{
+	Use:   "countries",
+	Short: "Add countries to the country targeted release without uploading anything",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return expandCountries(cmd.Context())
+	},
+}
+
+var rolloutCompleteCmd = &cobra.Command{
+	Use:   "complete",
+	Short: "Release the in progress release to every user without uploading anything",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return completeRollout(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rolloutCmd)
+	rolloutCmd.AddCommand(rolloutSetCmd)
+	rolloutCmd.AddCommand(rolloutCountriesCmd)
+	rolloutCmd.AddCommand(rolloutCompleteCmd)
+
+	rolloutCmd.PersistentFlags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	rolloutCmd.PersistentFlags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	rolloutCmd.MarkPersistentFlagRequired("appId")
+
+	rolloutSetCmd.Flags().StringVar(&RolloutTrack, "track", playstore.TrackProduction, "Track with in progress release e.g. beta")
+	rolloutSetCmd.Flags().Float64Var(&RolloutFraction, "fraction", 0, "New share of users e.g. 0.25")
+	rolloutSetCmd.Flags().BoolVar(&ConfirmProduction, "confirm-production", false, "Acknowledge changing production track rollout")
+	rolloutSetCmd.Flags().BoolVar(&CustomTracks, "custom-tracks", false, "Allow closed testing tracks created in Play Console e.g. qa-team as --track")
+	rolloutSetCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept --appId not following Android application ID rules, e.g. of legacy apps")
+	rolloutSetCmd.Flags().BoolVar(&NoReviewFallback, "noReviewFallback", false, "Retry commit with changes not sent for review when Play can't send them for review automatically")
+	rolloutSetCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
+	rolloutSetCmd.MarkFlagRequired("fraction")
+
+	rolloutCompleteCmd.Flags().StringVar(&RolloutTrack, "track", playstore.TrackProduction, "Track with in progress release e.g. beta")
+	rolloutCompleteCmd.Flags().BoolVar(&ConfirmProduction, "confirm-production", false, "Acknowledge changing production track rollout")
+	rolloutCompleteCmd.Flags().BoolVar(&CustomTracks, "custom-tracks", false, "Allow closed testing tracks created in Play Console e.g. qa-team as --track")
+	rolloutCompleteCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept --appId not following Android application ID rules, e.g. of legacy apps")
+	rolloutCompleteCmd.Flags().BoolVar(&NoReviewFallback, "noReviewFallback", false, "Retry commit with changes not sent for review when Play can't send them for review automatically")
+	rolloutCompleteCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
+
+	rolloutCountriesCmd.Flags().StringVar(&RolloutTrack, "track", playstore.TrackProduction, "Track with country targeted release e.g. beta")
+	rolloutCountriesCmd.Flags().StringSliceVar(&Countries, "countries", []string{}, "New country set including current ones e.g. NL,BE,DE")
+	rolloutCountriesCmd.Flags().BoolVar(&RestOfWorld, "include-rest-of-world", false, "Also release in countries Play adds later, kept if release has them already")
+	rolloutCountriesCmd.Flags().BoolVar(&AllCountries, "all", false, "Release in every country, removing country targeting")
+	rolloutCountriesCmd.Flags().BoolVar(&ConfirmProduction, "confirm-production", false, "Acknowledge changing production track rollout")
+	rolloutCountriesCmd.Flags().BoolVar(&CustomTracks, "custom-tracks", false, "Allow closed testing tracks created in Play Console e.g. qa-team as --track")
+	rolloutCountriesCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept --appId not following Android application ID rules, e.g. of legacy apps")
+	rolloutCountriesCmd.Flags().BoolVar(&NoReviewFallback, "noReviewFallback", false, "Retry commit with changes not sent for review when Play can't send them for review automatically")
+	rolloutCountriesCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
+	rolloutCountriesCmd.MarkFlagsMutuallyExclusive("countries", "all")
+	rolloutCountriesCmd.MarkFlagsMutuallyExclusive("include-rest-of-world", "all")
+}
+
+func setRollout(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	opts := []playstore.Option{}
+	if ConfirmProduction {
+		opts = append(opts, playstore.AllowProduction())
+	}
+	if CustomTracks {
+		opts = append(opts, playstore.WithCustomTracks())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	if NoReviewFallback {
+		opts = append(opts, playstore.WithNotSentForReviewFallback())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	r, err := playstore.UpdateRolloutFraction(ctx, gs, AppID, RolloutTrack, RolloutFraction, opts...)
+	if err != nil {
+		return fmt.Errorf("failed updating rollout: %w", err)
+	}
+	if JSONOutput {
+		return printJSON(r)
+	}
+	fmt.Printf("'%s' track appVersions %v rollout raised from %.1f%% to %.1f%% of users\n", r.Track, r.VersionCodes, r.From*100, r.To*100)
+	return nil
+}
+
+func completeRollout(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	opts := []playstore.Option{}
+	if ConfirmProduction {
+		opts = append(opts, playstore.AllowProduction())
+	}
+	if CustomTracks {
+		opts = append(opts, playstore.WithCustomTracks())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	if NoReviewFallback {
+		opts = append(opts, playstore.WithNotSentForReviewFallback())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	r, err := playstore.CompleteRollout(ctx, gs, AppID, RolloutTrack, opts...)
+	if err != nil {
+		return fmt.Errorf("failed completing rollout: %w", err)
+	}
+	if JSONOutput {
+		return printJSON(r)
+	}
+	fmt.Printf("'%s' track appVersions %v rollout completed from %.1f%% to every user\n", r.Track, r.VersionCodes, r.From*100)
+	return nil
+}
+
+func expandCountries(ctx context.Context) error {
+	if len(Countries) == 0 && !AllCountries {
+		return usageError{fmt.Errorf("either --countries or --all is required")}
+	}
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	opts := []playstore.Option{}
+	if ConfirmProduction {
+		opts = append(opts, playstore.AllowProduction())
+	}
+	if CustomTracks {
+		opts = append(opts, playstore.WithCustomTracks())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	if NoReviewFallback {
+		opts = append(opts, playstore.WithNotSentForReviewFallback())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	if RestOfWorld {
+		opts = append(opts, playstore.WithRestOfWorld())
+	}
+	countries := Countries
+	if AllCountries {
+		countries = nil
+	}
+	r, err := playstore.ExpandCountries(ctx, gs, AppID, RolloutTrack, countries, opts...)
+	if err != nil {
+		return fmt.Errorf("failed updating rollout: %w", err)
+	}
+	if JSONOutput {
+		return printJSON(r)
+	}
+	if len(r.To) == 0 {
+		fmt.Printf("'%s' track appVersions %v released in every country, was %v\n", r.Track, r.VersionCodes, r.From)
+		return nil
+	}
+	restOfWorld := ""
+	if r.IncludeRestOfWorld {
+		restOfWorld = " and rest of world"
+	}
+	fmt.Printf("'%s' track appVersions %v countries expanded from %v to %v%s\n", r.Track, r.VersionCodes, r.From, r.To, restOfWorld)
+	return nil
+}
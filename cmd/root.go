@@ -1,20 +1,258 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// exit codes, kept stable for pipelines to branch on
+const (
+	exitFailure   = 1 // any failure without a code of its own
+	exitUsage     = 2 // invalid flags or inputs, nothing was sent to Play
+	exitAPI       = 3 // Play refused a request
+	exitConflict  = 4 // edit conflicts with another edit of the app, running again usually helps
+	exitExists    = 5 // binary is on Play already
+	exitIntegrity = 6 // Play has different content of uploaded binary than the local file
+)
+
 var rootCmd = &cobra.Command{
 	Use:     "pstore",
 	Short:   "Test CLI for playstore uplaod",
 	Version: "1.0",
+	Long: `Test CLI for playstore uplaod
+
+Exit codes:
+  1  failure
+  2  invalid flags or inputs
+  3  Play refused a request
+  4  edit conflicts with another edit of the app
+  5  binary is on Play already
+  6  uploaded binary doesn't match local file`,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		switch Output {
+		case OutputText:
+		case OutputJSON:
+			JSONOutput = true
+		default:
+			return usageError{fmt.Errorf("output format '%s' not supported, use '%s' or '%s'", Output, OutputText, OutputJSON)}
+		}
+		// validated ahead of cobra, so missing flags exit as usage errors
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return usageError{err}
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return usageError{err}
+		}
+		if Timeout < 0 {
+			return usageError{fmt.Errorf("timeout '%s' must not be negative", Timeout)}
+		}
+		if QPS < 0 {
+			return usageError{fmt.Errorf("qps '%g' must not be negative", QPS)}
+		}
+		if Timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			cancelTimeout = cancel
+			cmd.SetContext(ctx)
+		}
+		return nil
+	},
+}
+
+var (
+	// authorize with Application Default Credentials instead of service account key
+	UseADC bool
+	// maximum time the whole command may take, Google API retries stop once another attempt wouldn't fit
+	Timeout time.Duration
+	// log every Google API request URL, status and latency, see playstore.TracingTransport
+	TraceHTTP bool
+	// proxy Google API requests go through, HTTPS_PROXY if not set
+	Proxy string
+	// Google API requests per second limit shared by all apps of the command, none if 0
+	QPS float64
+	// releases timer of Timeout once command is done
+	cancelTimeout context.CancelFunc = func() {}
+	// format results and errors are printed in, text or json
+	Output string
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&UseADC, "adc", false, "Authorize with Application Default Credentials e.g. workload identity or gcloud auth, instead of --authFile")
+	rootCmd.PersistentFlags().StringVar(&FlagsFile, "flags-file", "", "File with one flag per line, '#' comments allowed, read in place of the flag, same as @file")
+	rootCmd.PersistentFlags().DurationVar(&Timeout, "timeout", 0, "Maximum time the whole command may take e.g. 45m, retries of failed Google API calls stop once another wouldn't fit, no limit if not set")
+	rootCmd.PersistentFlags().StringVar(&Proxy, "proxy", "", "Proxy URL for Google API requests e.g. http://proxy:3128, HTTPS_PROXY environment variable is used if not set")
+	rootCmd.PersistentFlags().Float64Var(&QPS, "qps", 0, "Maximum Google API requests per second e.g. 5 when batch publishing many apps, calls over quota are retried once it refills, no limit if not set")
+	rootCmd.PersistentFlags().BoolVar(&TraceHTTP, "trace-http", false, "Log URL, response status and latency of every Google API request, bodies and credentials left out")
+	rootCmd.PersistentFlags().StringVar(&Output, "output", OutputText, "Output format: text or json, json prints results and errors to stdout for pipelines to parse")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
 }
 
 func Execute() {
-	rootCmd.CompletionOptions.DisableDefaultCmd = true
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+	// interrupting cancels running publish, which then cleans up after itself
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	args, err := expandFlagsFiles(afero.NewOsFs(), os.Args[1:])
+	if err == nil {
+		rootCmd.SetArgs(args)
+		err = rootCmd.ExecuteContext(ctx)
+	} else {
+		err = usageError{err}
+	}
+	cancelTimeout()
+	stop()
+	if err == nil {
+		return
+	}
+	code := exitCode(err)
+	if JSONOutput {
+		printJSON(newErrorOutput(err, code))
+	} else {
+		log.Print(err)
+		if problem := playstore.Diagnose(err); problem != nil {
+			log.Printf("Setup problem: %s", problem)
+		}
+	}
+	os.Exit(code)
+}
+
+// usageError flags or inputs command was run with are invalid
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns exit code of command failure
+func exitCode(err error) int {
+	var ue usageError
+	var ae *playstore.APIError
+	switch {
+	case errors.As(err, &ue), errors.Is(err, playstore.ErrAuthFileMissing), errors.Is(err, playstore.ErrUnsupportedTrack),
+		errors.Is(err, playstore.ErrInvalidPackageName):
+		return exitUsage
+	case errors.Is(err, playstore.ErrEditConflict):
+		return exitConflict
+	case errors.Is(err, playstore.ErrVersionAlreadyExists):
+		return exitExists
+	case errors.Is(err, playstore.ErrIntegrityMismatch):
+		return exitIntegrity
+	case errors.As(err, &ae):
+		return exitAPI
+	}
+	return exitFailure
+}
+
+// errorOutput command failure as printed with json output
+type errorOutput struct {
+	Error      string `json:"error"`
+	ExitCode   int    `json:"exitCode"`
+	StatusCode int    `json:"statusCode,omitempty"` // HTTP status code Play responded with
+	// edit failed publish left behind, see abandon
+	EditID      string   `json:"editId,omitempty"`
+	EditDeleted bool     `json:"editDeleted,omitempty"`
+	Uploaded    []string `json:"uploaded,omitempty"`
+	// commit may have published the edit, check the track before publishing again
+	CommitUncertain bool `json:"commitUncertain,omitempty"`
+	// outcome of every app of failed publish batch
+	Apps []playstore.BatchResult `json:"apps,omitempty"`
+	// setup problem failure points to, with steps fixing it
+	Problem *playstore.SetupProblem `json:"problem,omitempty"`
+}
+
+func newErrorOutput(err error, code int) errorOutput {
+	out := errorOutput{Error: err.Error(), ExitCode: code, StatusCode: playstore.StatusCode(err), Problem: playstore.Diagnose(err)}
+	var ae *playstore.AbortError
+	if errors.As(err, &ae) {
+		out.EditID = ae.EditID
+		out.EditDeleted = ae.EditDeleted
+		out.Uploaded = ae.Uploaded
+		out.CommitUncertain = ae.CommitUncertain
+	}
+	var be *playstore.BatchError
+	if errors.As(err, &be) {
+		out.Apps = be.Report.Apps
+	}
+	return out
+}
+
+// printJSON prints v as indented JSON to stdout
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// services authorized clients shared by every command run in this process
+var services = playstore.NewServiceCache()
+
+// newService returns playstore service authorized with --adc, --authFile or credentials from environment if neither set.
+// Its client is cached for the whole process and refreshes tokens on its own, so it's not bound to command context.
+func newService(opts ...playstore.ServiceOption) (playstore.IGService, error) {
+	opts = append(opts, clientOptions()...)
+	if UseADC {
+		return services.ServiceWithADC(opts...)
+	}
+	if SecretFile != "" {
+		return services.Service(SecretFile, opts...)
+	}
+	credentials, err := playstore.CredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return services.ServiceFromJSON(credentials, opts...)
+}
+
+// newGCSClient client fetching gs:// artifacts with the same credentials newService uses
+func newGCSClient(ctx context.Context) (*http.Client, error) {
+	opts := clientOptions()
+	if UseADC {
+		return playstore.NewGCSClientWithADC(ctx, opts...)
+	}
+	if SecretFile != "" {
+		return playstore.NewGCSClient(ctx, SecretFile, opts...)
+	}
+	credentials, err := playstore.CredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return playstore.NewGCSClientFromJSON(ctx, credentials, opts...)
+}
+
+// clientOptions HTTP client options set with persistent flags
+func clientOptions() []playstore.ServiceOption {
+	opts := []playstore.ServiceOption{}
+	if TraceHTTP {
+		opts = append(opts, playstore.WithHTTPTracing())
+	}
+	if Proxy != "" {
+		opts = append(opts, playstore.WithProxy(Proxy))
+	}
+	if QPS > 0 {
+		opts = append(opts, playstore.WithRateLimit(QPS))
 	}
+	return opts
 }
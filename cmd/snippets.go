@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// image, app, track and binaries snippets are made for, kept apart from upload flags so their defaults don't leak
+	SnippetImage string
+	SnippetAppID string
+	SnippetTrack string
+	SnippetBins  []string
+)
+
+var snippetsCmd = &cobra.Command{
+	Use:       "snippets docker|github|gitlab",
+	Short:     "Print ready to use upload invocation for docker, GitHub Actions step or GitLab CI job",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"docker", "github", "gitlab"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printSnippet(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snippetsCmd)
+
+	snippetsCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file mounted into docker container, PSTORE_CREDENTIALS passed through if not set. CI snippets always use PSTORE_CREDENTIALS secret")
+	snippetsCmd.Flags().StringVar(&SnippetAppID, "appId", "com.sample.app", "Application ID e.g. com.sample.app")
+	snippetsCmd.Flags().StringVar(&SnippetTrack, "track", playstore.TrackInternal, "Track to publish binaries to e.g. internal, alpha, beta, production")
+	snippetsCmd.Flags().StringArrayVar(&SnippetBins, "appBinOnly", []string{"app/build/outputs/bundle/release/app-release.aab"}, "Path to binary file relative to repository root, can be repeated")
+	snippetsCmd.Flags().StringVar(&SnippetImage, "image", "pstore:latest", "Docker image with pstore as its entrypoint")
+}
+
+// snippet values templates are filled with
+type snippet struct {
+	Image    string
+	AuthFile string
+	Args     string
+}
+
+// snippet templates use [[ ]] delimiters, GitHub Actions expressions use {{ }} of their own
+var snippets = map[string]string{
+	"docker": `docker run --rm \
+  -v "$PWD:/workspace" -w /workspace \
+[[- if .AuthFile ]]
+  -v "[[ .AuthFile ]]:/secrets/auth.json:ro" \
+[[- else ]]
+  -e PSTORE_CREDENTIALS \
+[[- end ]]
+  [[ .Image ]] pstore[[ if .AuthFile ]] --authFile /secrets/auth.json[[ end ]] [[ .Args ]]
+`,
+	"github": `- name: Publish to Google Play
+  env:
+    # base64 encoded service account key
+    PSTORE_CREDENTIALS: ${{ secrets.PSTORE_CREDENTIALS }}
+  run: |
+    docker run --rm \
+      -v "$PWD:/workspace" -w /workspace \
+      -e PSTORE_CREDENTIALS \
+      [[ .Image ]] pstore [[ .Args ]] --runId "${{ github.run_id }}-${{ github.run_attempt }}"
+`,
+	"gitlab": `publish:
+  stage: deploy
+  image:
+    name: [[ .Image ]]
+    entrypoint: [""]
+  # PSTORE_CREDENTIALS base64 encoded service account key, set as masked CI/CD variable
+  script:
+    - pstore pstore [[ .Args ]] --runId "$CI_PIPELINE_ID"
+  rules:
+    - if: $CI_COMMIT_TAG
+`,
+}
+
+// printSnippet prints snippet of a kind for the app, track and binaries given with flags
+func printSnippet(kind string) error {
+	t, err := template.New(kind).Delims("[[", "]]").Parse(snippets[kind])
+	if err != nil {
+		return err
+	}
+	args := []string{"--appId", SnippetAppID, "--track", SnippetTrack}
+	for _, b := range SnippetBins {
+		args = append(args, "--appBinOnly", b)
+	}
+	if SnippetTrack == playstore.TrackProduction {
+		args = append(args, "--confirm-production")
+	}
+	quoted := make([]string, 0, len(args))
+	for _, a := range args {
+		quoted = append(quoted, shellQuote(a))
+	}
+	// docker mounts absolute paths only
+	auth := SecretFile
+	if auth != "" {
+		if auth, err = filepath.Abs(auth); err != nil {
+			return err
+		}
+	}
+	if err := t.Execute(os.Stdout, snippet{Image: SnippetImage, AuthFile: auth, Args: strings.Join(quoted, " ")}); err != nil {
+		return fmt.Errorf("failed printing '%s' snippet: %w", kind, err)
+	}
+	return nil
+}
+
+// shellQuote quotes argument unless shell leaves it as is
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=,@") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
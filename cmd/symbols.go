@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// appVersion and native debug symbols zip of upload-symbols, mappings come with --mapping
+	SymbolsVersionCode int64
+	NativeSymbols      string
+)
+
+var uploadSymbolsCmd = &cobra.Command{
+	Use:   "upload-symbols",
+	Short: "Upload proguard mapping or native debug symbols of appVersion already on Play, changing nothing else",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if Mapping == "" && NativeSymbols == "" {
+			return usageError{fmt.Errorf("--mapping or --symbols file to upload is required")}
+		}
+		return uploadSymbols(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uploadSymbolsCmd)
+
+	uploadSymbolsCmd.Flags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	uploadSymbolsCmd.Flags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	uploadSymbolsCmd.Flags().Int64Var(&SymbolsVersionCode, "versionCode", 0, "appVersion code symbols belong to")
+	uploadSymbolsCmd.Flags().StringVar(&Mapping, "mapping", "", "Proguard mapping file, plain text or gzipped e.g. mapping.txt")
+	uploadSymbolsCmd.Flags().StringVar(&NativeSymbols, "symbols", "", "Zip of native debug symbols e.g. native-debug-symbols.zip")
+	uploadSymbolsCmd.Flags().BoolVar(&DryRunOnly, "dry-run", false, "Upload and validate symbols, then discard the edit instead of committing")
+	uploadSymbolsCmd.Flags().BoolVar(&AnyPackageName, "any-package-name", false, "Accept --appId not following Android application ID rules, e.g. of legacy apps")
+	uploadSymbolsCmd.Flags().BoolVar(&NoReviewFallback, "noReviewFallback", false, "Retry commit with changes not sent for review when Play can't send them for review automatically")
+	uploadSymbolsCmd.Flags().BoolVar(&NoReview, "no-review", false, "Commit with changes not sent for review, to send them for review from Play Console later")
+
+	uploadSymbolsCmd.MarkFlagRequired("appId")
+	uploadSymbolsCmd.MarkFlagRequired("versionCode")
+}
+
+func uploadSymbols(ctx context.Context) error {
+	opts := []playstore.Option{}
+	if DryRunOnly {
+		opts = append(opts, playstore.DryRun())
+	}
+	if NoReviewFallback {
+		opts = append(opts, playstore.WithNotSentForReviewFallback())
+	}
+	if NoReview {
+		opts = append(opts, playstore.WithChangesNotSentForReview())
+	}
+	if AnyPackageName {
+		opts = append(opts, playstore.WithAnyPackageName())
+	}
+	gs, err := newService(serviceOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	report, err := playstore.UploadSymbols(ctx, gs, afero.NewOsFs(), AppID, SymbolsVersionCode, Mapping, NativeSymbols, opts...)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return printJSON(report)
+	}
+	for _, f := range []string{report.Mapping, report.NativeSymbols} {
+		if f != "" {
+			fmt.Printf("'%s' uploaded for appVersion %d\n", f, report.VersionCode)
+		}
+	}
+	if report.DryRun {
+		fmt.Printf("Dry run passed validation, edit '%s' deleted without committing\n", report.EditID)
+	}
+	return nil
+}
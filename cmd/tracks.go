@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	PruneTrack string
+	PruneKeep  int
+	JSONOutput bool
+)
+
+var tracksCmd = &cobra.Command{
+	Use:   "tracks",
+	Short: "List application tracks with their releases",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listTracks(cmd.Context())
+	},
+}
+
+var tracksPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove release names and notes from old draft and halted releases",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pruneTracks(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tracksCmd)
+	tracksCmd.AddCommand(tracksPruneCmd)
+
+	tracksCmd.PersistentFlags().StringVar(&SecretFile, "authFile", "", "Authentication file, PSTORE_CREDENTIALS (base64) or GOOGLE_APPLICATION_CREDENTIALS used if not set")
+	tracksCmd.PersistentFlags().StringVar(&AppID, "appId", "", "Application ID e.g. com.sample.app")
+	tracksCmd.MarkPersistentFlagRequired("appId")
+
+	tracksCmd.Flags().BoolVar(&JSONOutput, "json", false, "Print tracks as JSON")
+	tracksCmd.Flags().MarkDeprecated("json", "use --output json")
+
+	tracksPruneCmd.Flags().StringVar(&PruneTrack, "track", playstore.TrackInternal, "Track to prune e.g. beta")
+	tracksPruneCmd.Flags().IntVar(&PruneKeep, "keep", 5, "Number of newest draft and halted releases to leave untouched")
+}
+
+func listTracks(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	tracks, err := playstore.ListTracks(ctx, gs, AppID)
+	if err != nil {
+		return fmt.Errorf("failed listing tracks: %w", err)
+	}
+
+	if JSONOutput {
+		return printJSON(tracks)
+	}
+	for _, t := range tracks {
+		fmt.Printf("%s\n", t.Track)
+		if len(t.Releases) == 0 {
+			fmt.Println("  no releases")
+		}
+		for _, r := range t.Releases {
+			name := r.Name
+			if name == "" {
+				name = "-"
+			}
+			fraction := ""
+			if r.UserFraction > 0 {
+				fraction = fmt.Sprintf(" %.1f%% of users", r.UserFraction*100)
+			}
+			countries := ""
+			if len(r.Countries) > 0 {
+				countries = fmt.Sprintf(" in %s", strings.Join(r.Countries, ","))
+				if r.IncludeRestOfWorld {
+					countries += " and rest of world"
+				}
+			}
+			fmt.Printf("  %-12s %-20s versionCodes %v%s%s\n", r.Status, name, r.VersionCodes, fraction, countries)
+		}
+	}
+	return nil
+}
+
+func pruneTracks(ctx context.Context) error {
+	gs, err := newService()
+	if err != nil {
+		return fmt.Errorf("failed creating new playstore service instance: %w", err)
+	}
+	r, err := playstore.PruneTracks(ctx, gs, AppID, PruneTrack, PruneKeep, nil)
+	if err != nil {
+		return fmt.Errorf("failed pruning track: %w", err)
+	}
+
+	if JSONOutput {
+		return printJSON(r)
+	}
+	fmt.Printf("Track '%s': %d trimmed, %d kept, %d skipped\n", r.Track, len(r.Trimmed), len(r.Kept), len(r.Skipped))
+	for _, t := range r.Trimmed {
+		fmt.Printf("  trimmed  %s\n", t)
+	}
+	for _, k := range r.Kept {
+		fmt.Printf("  kept     %s\n", k)
+	}
+	for _, s := range r.Skipped {
+		fmt.Printf("  skipped  %s\n", s)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	abortCleanupTimeout = 30 * time.Second
+)
+
+// AbortError is returned when publish fails after an edit was created, describing what was cleaned up
+type AbortError struct {
+	Err         error
+	RunID       string
+	EditID      string
+	EditDeleted bool
+	DeleteErr   error // why edit could not be deleted
+	Resumable   bool  // edit kept on purpose with resume state for the next run to continue in
+	// commit may have published the edit, so it's neither deleted nor published again, see CommitUncertainError
+	CommitUncertain bool
+	Uploaded        []string // files uploaded to the edit before publish failed
+	ExpiresAt       time.Time
+}
+
+func (e *AbortError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AbortError) Unwrap() error {
+	return e.Err
+}
+
+// FollowUp describes manual steps needed after failed publish, empty if none
+func (e *AbortError) FollowUp() string {
+	if e.EditDeleted {
+		return ""
+	}
+	if e.CommitUncertain {
+		return fmt.Sprintf("check the track in Play Console, release of edit '%s' may be published already. Publish again only if it isn't.", e.EditID)
+	}
+	if e.Resumable {
+		return fmt.Sprintf("edit '%s' kept with upload progress, run again with the same resume state to continue, or 'pstore abandon --edit %s' to discard it.", e.EditID, e.EditID)
+	}
+	expiry := "once it expires"
+	if !e.ExpiresAt.IsZero() {
+		expiry = "at " + e.ExpiresAt.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("edit '%s' could not be deleted, Play discards it %s. Until then new edits for the app may conflict with it, run 'pstore abandon --edit %s' to delete it sooner.", e.EditID, expiry, e.EditID)
+}
+
+// DeleteEditByID deletes edit left behind by a crashed or aborted publish, e.g. one reported by AbortError
+func DeleteEditByID(ctx context.Context, es IEditsService, packageName, editId string) error {
+	if es == nil {
+		return errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return err
+	}
+	id := strings.TrimSpace(editId)
+	if id == "" {
+		return fmt.Errorf("edit id must not be empty")
+	}
+	if err := es.deleteEdit(ctx, name, id); err != nil {
+		return fmt.Errorf("failed deleting edit '%s': %w", id, err)
+	}
+	return nil
+}
+
+// Report human readable summary of cleanup done after failed publish
+func (e *AbortError) Report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Publish aborted: %v\n", e.Err)
+	if e.RunID != "" {
+		fmt.Fprintf(&b, "  run '%s'\n", e.RunID)
+	}
+	if e.EditDeleted {
+		fmt.Fprintf(&b, "  edit '%s' deleted, nothing was published\n", e.EditID)
+	} else if e.CommitUncertain {
+		fmt.Fprintf(&b, "  edit '%s' may have been committed, it's not known if anything was published\n", e.EditID)
+	} else if e.Resumable {
+		fmt.Fprintf(&b, "  edit '%s' kept for resuming, nothing was published\n", e.EditID)
+	} else {
+		fmt.Fprintf(&b, "  edit '%s' NOT deleted: %v\n", e.EditID, e.DeleteErr)
+	}
+	if len(e.Uploaded) == 0 {
+		fmt.Fprintln(&b, "  no files uploaded")
+	}
+	for _, f := range e.Uploaded {
+		fmt.Fprintf(&b, "  uploaded before failure: %s\n", f)
+	}
+	if f := e.FollowUp(); f != "" {
+		fmt.Fprintf(&b, "  follow-up: %s\n", f)
+	}
+	return b.String()
+}
+
+// abort deletes edit of a failed publish, unless it's kept for resuming, and returns error describing cleanup
+func (p *publish) abort(edit *Edit, uploaded []string, err error) error {
+	editId := edit.ID()
+	ae := &AbortError{
+		Err:       err,
+		RunID:     p.runID,
+		EditID:    editId,
+		Uploaded:  uploaded,
+		ExpiresAt: edit.ExpiresAt(),
+	}
+	if errors.Is(err, ErrEditExpired) {
+		// Play discarded the edit already, so there's nothing to delete
+		ae.EditDeleted = true
+		p.Debugf("edit '%s' is gone, not deleting it", editId)
+		return ae
+	}
+	if errors.Is(err, ErrCommitUncertain) {
+		// edit is gone if commit went through, deleting or retrying it can't help
+		ae.CommitUncertain = true
+		p.Debugf("commit of edit '%s' may have gone through, not deleting it", editId)
+		return ae
+	}
+	if p.resume != nil {
+		ae.Resumable = true
+		p.Debugf("keeping edit '%s' for resuming", editId)
+		return ae
+	}
+	// publish context may be cancelled already, cleanup gets its own
+	ctx, cancel := context.WithTimeout(context.Background(), abortCleanupTimeout)
+	defer cancel()
+	if derr := edit.Delete(ctx); derr != nil {
+		ae.DeleteErr = derr
+		p.Warnf("failed deleting edit '%s': %v", editId, derr)
+	} else {
+		ae.EditDeleted = true
+		p.Debugf("deleted edit '%s'", editId)
+	}
+	return ae
+}
@@ -0,0 +1,122 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestAbortReport(t *testing.T) {
+
+	t.Run("should report deleted edit and uploaded files", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "mapping.txt")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		commitErr := errors.New("commit failed")
+		gs := &mockGService{commitErrors: []error{commitErr}}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ae *AbortError
+		if !errors.As(err, &ae) {
+			t.Fatalf("want AbortError, got %v", err)
+		}
+		if !errors.Is(err, commitErr) {
+			t.Errorf("want '%v' cause, got '%v'", commitErr, ae.Err)
+		}
+		if !ae.EditDeleted || ae.FollowUp() != "" {
+			t.Errorf("want edit deleted with no follow-up, got %+v", ae)
+		}
+		if !reflect.DeepEqual(ae.Uploaded, []string{"test.aab", "mapping.txt"}) {
+			t.Errorf("want binary and mapping uploaded, got %v", ae.Uploaded)
+		}
+	})
+
+	t.Run("should delete edit even when publish context is cancelled", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		gs := &mockGService{commitErrors: []error{context.Canceled}}
+
+		// Act
+		_, err := publish.UploadFiles(ctx, gs)
+
+		// Assert
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want context cancelled error, got %v", err)
+		}
+		if gs.deleteEditCount != 1 || gs.deleteCtxErr != nil {
+			t.Errorf("want edit deleted with live context, got %d deletes with '%v'", gs.deleteEditCount, gs.deleteCtxErr)
+		}
+	})
+
+	t.Run("should ask for follow-up when edit can't be deleted", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		gs := &mockGService{Sha256: "mismatch", deleteError: errors.New("network down")}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ae *AbortError
+		if !errors.As(err, &ae) {
+			t.Fatalf("want AbortError, got %v", err)
+		}
+		if ae.EditDeleted || ae.FollowUp() == "" {
+			t.Errorf("want follow-up for undeleted edit, got %+v", ae)
+		}
+		if len(ae.Uploaded) != 0 {
+			t.Errorf("want no files reported uploaded, got %v", ae.Uploaded)
+		}
+	})
+}
+
+func TestDeleteEditByID(t *testing.T) {
+
+	t.Run("should delete given edit", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		err := DeleteEditByID(context.Background(), gs, "com.test.app", "stale-edit")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.deleteEditCount != 1 {
+			t.Errorf("want 1 deleteEdit call, got %d", gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should fail without edit id", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		err := DeleteEditByID(context.Background(), gs, "com.test.app", " ")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.deleteEditCount != 0 {
+			t.Errorf("want no deleteEdit calls, got %d", gs.deleteEditCount)
+		}
+	})
+}
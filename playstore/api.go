@@ -0,0 +1,169 @@
+package playstore
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+// API Google Play calls IGService is made of, with exported methods so services can be implemented outside
+// this package, e.g. fakes of tools embedding it. Edit scoped calls get ID CreateEdit returned.
+// See ServiceFromAPI and playstoretest.Fake.
+type API interface {
+	CreateEdit(ctx context.Context, packageName string) (editId string, expiresAt time.Time, err error)
+	ValidateEdit(ctx context.Context, packageName, editId string) error
+	DeleteEdit(ctx context.Context, packageName, editId string) error
+	CommitEdit(ctx context.Context, packageName, editId string, changesNotSentForReview bool) error
+	GetEdit(ctx context.Context, packageName, editId string) (expiresAt time.Time, err error)
+	UploadBundle(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error)
+	UploadApk(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error)
+	UploadProguardMapping(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error
+	UploadNativeSymbols(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error
+	UploadExpansionFile(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string) error
+	CreateRelease(ctx context.Context, packageName, editId, trackName string, release *androidpublisher.TrackRelease) error
+	VersionHashes(ctx context.Context, packageName, editId string) (map[int64]Hashes, error)
+	ListTracks(ctx context.Context, packageName, editId string) ([]*androidpublisher.Track, error)
+	GetTrack(ctx context.Context, packageName, editId, trackName string) (*androidpublisher.Track, error)
+	UpdateTrack(ctx context.Context, packageName, editId string, track *androidpublisher.Track) error
+	ListListings(ctx context.Context, packageName, editId string) ([]Listing, error)
+	UpdateListing(ctx context.Context, packageName, editId string, listing Listing) error
+	DeleteListing(ctx context.Context, packageName, editId, locale string) error
+	DeleteAllImages(ctx context.Context, packageName, editId, locale, imageType string) (deleted int, err error)
+	UploadImage(ctx context.Context, r io.Reader, packageName, editId, locale, imageType string) error
+	ListImages(ctx context.Context, packageName, editId, locale, imageType string) (sha256 []string, err error)
+	ListReviews(ctx context.Context, packageName string) ([]Review, error)
+	GetReview(ctx context.Context, packageName, reviewId string) (*Review, error)
+	ReplyReview(ctx context.Context, packageName, reviewId, text string) error
+	GetDetails(ctx context.Context, packageName, editId string) (*AppDetails, error)
+	UpdateDetails(ctx context.Context, packageName, editId string, details AppDetails) error
+}
+
+// ServiceFromAPI returns IGService making its calls through api
+func ServiceFromAPI(api API) IGService {
+	return &apiService{api: api}
+}
+
+// apiService IGService of API implemented outside this package, failures with googleapi errors are wrapped
+// with APIError the same way as calls of Google API service are
+type apiService struct {
+	api API
+}
+
+func (s *apiService) createEdit(ctx context.Context, packageName string) (string, time.Time, error) {
+	v, w, err := s.api.CreateEdit(ctx, packageName)
+	return v, w, apiError("create edit", err)
+}
+
+func (s *apiService) validateEdit(ctx context.Context, packageName, editId string) error {
+	return apiError("validate edit", s.api.ValidateEdit(ctx, packageName, editId))
+}
+
+func (s *apiService) deleteEdit(ctx context.Context, packageName, editId string) error {
+	return apiError("delete edit", s.api.DeleteEdit(ctx, packageName, editId))
+}
+
+func (s *apiService) commitEdit(ctx context.Context, packageName, editId string, changesNotSentForReview bool) error {
+	return apiError("commit edit", s.api.CommitEdit(ctx, packageName, editId, changesNotSentForReview))
+}
+
+func (s *apiService) getEdit(ctx context.Context, packageName, editId string) (time.Time, error) {
+	v, err := s.api.GetEdit(ctx, packageName, editId)
+	return v, apiError("get edit", err)
+}
+
+func (s *apiService) uploadBundle(ctx context.Context, r io.Reader, packageName, editId string) (int64, string, error) {
+	v, w, err := s.api.UploadBundle(ctx, r, packageName, editId)
+	return v, w, apiError("upload bundle", err)
+}
+
+func (s *apiService) uploadApk(ctx context.Context, r io.Reader, packageName, editId string) (int64, string, error) {
+	v, w, err := s.api.UploadApk(ctx, r, packageName, editId)
+	return v, w, apiError("upload apk", err)
+}
+
+func (s *apiService) uploadProguardMapping(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error {
+	return apiError("upload proguard mapping", s.api.UploadProguardMapping(ctx, r, packageName, editId, appVersionCode))
+}
+
+func (s *apiService) uploadNativeSymbols(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error {
+	return apiError("upload native symbols", s.api.UploadNativeSymbols(ctx, r, packageName, editId, appVersionCode))
+}
+
+func (s *apiService) uploadExpansionFile(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string) error {
+	return apiError("upload expansion file", s.api.UploadExpansionFile(ctx, r, packageName, editId, appVersionCode, fileType))
+}
+
+func (s *apiService) createRelease(ctx context.Context, packageName, editId, trackName string, release *androidpublisher.TrackRelease) error {
+	return apiError("create release", s.api.CreateRelease(ctx, packageName, editId, trackName, release))
+}
+
+func (s *apiService) versionHashes(ctx context.Context, packageName, editId string) (map[int64]Hashes, error) {
+	v, err := s.api.VersionHashes(ctx, packageName, editId)
+	return v, apiError("version hashes", err)
+}
+
+func (s *apiService) listTracks(ctx context.Context, packageName, editId string) ([]*androidpublisher.Track, error) {
+	v, err := s.api.ListTracks(ctx, packageName, editId)
+	return v, apiError("list tracks", err)
+}
+
+func (s *apiService) getTrack(ctx context.Context, packageName, editId, trackName string) (*androidpublisher.Track, error) {
+	v, err := s.api.GetTrack(ctx, packageName, editId, trackName)
+	return v, apiError("get track", err)
+}
+
+func (s *apiService) updateTrack(ctx context.Context, packageName, editId string, track *androidpublisher.Track) error {
+	return apiError("update track", s.api.UpdateTrack(ctx, packageName, editId, track))
+}
+
+func (s *apiService) listListings(ctx context.Context, packageName, editId string) ([]Listing, error) {
+	v, err := s.api.ListListings(ctx, packageName, editId)
+	return v, apiError("list listings", err)
+}
+
+func (s *apiService) updateListing(ctx context.Context, packageName, editId string, listing Listing) error {
+	return apiError("update listing", s.api.UpdateListing(ctx, packageName, editId, listing))
+}
+
+func (s *apiService) deleteListing(ctx context.Context, packageName, editId, locale string) error {
+	return apiError("delete listing", s.api.DeleteListing(ctx, packageName, editId, locale))
+}
+
+func (s *apiService) deleteAllImages(ctx context.Context, packageName, editId, locale, imageType string) (int, error) {
+	v, err := s.api.DeleteAllImages(ctx, packageName, editId, locale, imageType)
+	return v, apiError("delete all images", err)
+}
+
+func (s *apiService) uploadImage(ctx context.Context, r io.Reader, packageName, editId, locale, imageType string) error {
+	return apiError("upload image", s.api.UploadImage(ctx, r, packageName, editId, locale, imageType))
+}
+
+func (s *apiService) listImages(ctx context.Context, packageName, editId, locale, imageType string) ([]string, error) {
+	v, err := s.api.ListImages(ctx, packageName, editId, locale, imageType)
+	return v, apiError("list images", err)
+}
+
+func (s *apiService) listReviews(ctx context.Context, packageName string) ([]Review, error) {
+	v, err := s.api.ListReviews(ctx, packageName)
+	return v, apiError("list reviews", err)
+}
+
+func (s *apiService) getReview(ctx context.Context, packageName, reviewId string) (*Review, error) {
+	v, err := s.api.GetReview(ctx, packageName, reviewId)
+	return v, apiError("get review", err)
+}
+
+func (s *apiService) replyReview(ctx context.Context, packageName, reviewId, text string) error {
+	return apiError("reply review", s.api.ReplyReview(ctx, packageName, reviewId, text))
+}
+
+func (s *apiService) getDetails(ctx context.Context, packageName, editId string) (*AppDetails, error) {
+	v, err := s.api.GetDetails(ctx, packageName, editId)
+	return v, apiError("get details", err)
+}
+
+func (s *apiService) updateDetails(ctx context.Context, packageName, editId string, details AppDetails) error {
+	return apiError("update details", s.api.UpdateDetails(ctx, packageName, editId, details))
+}
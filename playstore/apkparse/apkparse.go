@@ -0,0 +1,77 @@
+// Package apkparse reads package name and version of Android app bundles and apks from their manifest,
+// without aapt or bundletool
+package apkparse
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// app bundles keep base module manifest compiled to aapt2 protobuf XML
+	bundleManifest = "base/manifest/AndroidManifest.xml"
+	// apks keep manifest as Android binary XML
+	apkManifest = "AndroidManifest.xml"
+
+	// android:versionCode and android:versionName attribute resource IDs
+	attrVersionCode = 0x0101021b
+	attrVersionName = 0x0101021c
+)
+
+// ErrNoManifest file is a zip, but has neither bundle nor apk manifest
+var ErrNoManifest = errors.New("no Android manifest found")
+
+// errMalformed manifest content doesn't follow its format
+var errMalformed = errors.New("malformed Android manifest")
+
+// Manifest app identity read from bundle or apk manifest
+type Manifest struct {
+	PackageName string
+	VersionCode int64
+	// empty when manifest references a string resource instead of literal value
+	VersionName string
+}
+
+// Parse reads manifest of app bundle or apk of given size
+func Parse(r io.ReaderAt, size int64) (*Manifest, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not an app bundle or apk: %w", err)
+	}
+	var apk *zip.File
+	for _, zf := range zr.File {
+		switch zf.Name {
+		case bundleManifest:
+			b, err := readEntry(zf)
+			if err != nil {
+				return nil, err
+			}
+			return parseProtoXML(b)
+		case apkManifest:
+			apk = zf
+		}
+	}
+	if apk == nil {
+		return nil, ErrNoManifest
+	}
+	b, err := readEntry(apk)
+	if err != nil {
+		return nil, err
+	}
+	return parseBinaryXML(b)
+}
+
+func readEntry(zf *zip.File) ([]byte, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed reading '%s': %w", zf.Name, err)
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading '%s': %w", zf.Name, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,212 @@
+package apkparse
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	t.Run("should read bundle manifest", func(t *testing.T) {
+		// Arrange
+		manifest := protoManifest(
+			protoAttr("package", "com.test.app", 0, nil),
+			protoAttr("versionCode", "42", attrVersionCode, protoInt(42)),
+			protoAttr("versionName", "1.2.3", attrVersionName, nil),
+		)
+		b := zipWith(t, bundleManifest, manifest)
+
+		// Act
+		m, err := Parse(bytes.NewReader(b), int64(len(b)))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if *m != (Manifest{PackageName: "com.test.app", VersionCode: 42, VersionName: "1.2.3"}) {
+			t.Errorf("want com.test.app 42 (1.2.3), got %+v", m)
+		}
+	})
+
+	t.Run("should read apk manifest", func(t *testing.T) {
+		// Arrange
+		b := zipWith(t, apkManifest, binaryManifest(false))
+
+		// Act
+		m, err := Parse(bytes.NewReader(b), int64(len(b)))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if *m != (Manifest{PackageName: "com.test.app", VersionCode: 7, VersionName: "2.0"}) {
+			t.Errorf("want com.test.app 7 (2.0), got %+v", m)
+		}
+	})
+
+	t.Run("should read apk manifest with UTF-8 string pool", func(t *testing.T) {
+		// Arrange
+		b := zipWith(t, apkManifest, binaryManifest(true))
+
+		// Act
+		m, err := Parse(bytes.NewReader(b), int64(len(b)))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if m.PackageName != "com.test.app" || m.VersionCode != 7 {
+			t.Errorf("want com.test.app 7, got %+v", m)
+		}
+	})
+
+	t.Run("should fail without manifest", func(t *testing.T) {
+		// Arrange
+		b := zipWith(t, "classes.dex", []byte("dex"))
+
+		// Act
+		_, err := Parse(bytes.NewReader(b), int64(len(b)))
+
+		// Assert
+		if !errors.Is(err, ErrNoManifest) {
+			t.Errorf("want '%v', got: %v", ErrNoManifest, err)
+		}
+	})
+
+	t.Run("should fail on truncated manifest", func(t *testing.T) {
+		// Arrange
+		manifest := binaryManifest(false)
+		b := zipWith(t, apkManifest, manifest[:len(manifest)-10])
+
+		// Act
+		_, err := Parse(bytes.NewReader(b), int64(len(b)))
+
+		// Assert
+		if !errors.Is(err, errMalformed) {
+			t.Errorf("want '%v', got: %v", errMalformed, err)
+		}
+	})
+}
+
+func zipWith(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(content)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// protobuf encoding of aapt2 manifest XmlNode
+func protoField(num int, data []byte) []byte {
+	b := binary.AppendUvarint(nil, uint64(num<<3|wireBytes))
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func protoVarint(num int, v uint64) []byte {
+	b := binary.AppendUvarint(nil, uint64(num<<3|wireVarint))
+	return binary.AppendUvarint(b, v)
+}
+
+func protoInt(v uint64) []byte {
+	return protoField(itemPrim, protoVarint(primIntDecimal, v))
+}
+
+func protoAttr(name, value string, resID uint64, item []byte) []byte {
+	b := append(protoField(1, []byte("http://schemas.android.com/apk/res/android")), protoField(xmlAttributeName, []byte(name))...)
+	b = append(b, protoField(xmlAttributeValue, []byte(value))...)
+	if resID != 0 {
+		b = append(b, protoVarint(xmlAttributeResourceID, resID)...)
+	}
+	if item != nil {
+		b = append(b, protoField(xmlAttributeCompiledItem, item)...)
+	}
+	return b
+}
+
+func protoManifest(attrs ...[]byte) []byte {
+	element := protoField(xmlElementName, []byte("manifest"))
+	for _, a := range attrs {
+		element = append(element, protoField(xmlElementAttribute, a)...)
+	}
+	return protoField(xmlNodeElement, element)
+}
+
+// binaryManifest Android binary XML manifest with versionCode named only through resource map
+func binaryManifest(utf8 bool) []byte {
+	strs := []string{"", "versionName", "package", "manifest", "com.test.app", "2.0"}
+
+	// string pool
+	var data []byte
+	offsets := make([]uint32, len(strs))
+	for i, s := range strs {
+		offsets[i] = uint32(len(data))
+		if utf8 {
+			data = append(data, byte(len(s)), byte(len(s)))
+			data = append(data, s...)
+			data = append(data, 0)
+			continue
+		}
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(s)))
+		for _, r := range s {
+			data = binary.LittleEndian.AppendUint16(data, uint16(r))
+		}
+		data = append(data, 0, 0)
+	}
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+	var flags uint32
+	if utf8 {
+		flags = stringPoolUTF8
+	}
+	pool := header(chunkStringPool, 28, 28+4*len(strs)+len(data))
+	pool = le32(pool, uint32(len(strs)), 0, flags, uint32(28+4*len(strs)), 0)
+	pool = le32(pool, offsets...)
+	pool = append(pool, data...)
+
+	// resource map naming strings 0 and 1 as versionCode and versionName
+	resMap := le32(header(chunkResourceMap, 8, 16), attrVersionCode, attrVersionName)
+
+	// manifest element with package, versionCode and versionName attributes
+	attr := func(name, raw uint32, dataType byte, value uint32) []byte {
+		a := le32(nil, 0, name, raw)
+		a = append(a, 8, 0, 0, dataType)
+		return le32(a, value)
+	}
+	attrs := append(attr(2, 4, typeString, 4), attr(0, noIndex, typeIntDec, 7)...)
+	attrs = append(attrs, attr(1, 5, typeString, 5)...)
+	element := header(chunkStartElement, 16, 16+20+len(attrs))
+	element = le32(element, 1, noIndex, noIndex, 3)
+	element = binary.LittleEndian.AppendUint16(element, 20)
+	element = binary.LittleEndian.AppendUint16(element, 20)
+	element = binary.LittleEndian.AppendUint16(element, 3)
+	element = append(element, 0, 0, 0, 0, 0, 0)
+	element = append(element, attrs...)
+
+	body := append(append(pool, resMap...), element...)
+	return append(header(chunkXML, 8, 8+len(body)), body...)
+}
+
+func header(typ uint16, headerSize, size int) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, typ)
+	b = binary.LittleEndian.AppendUint16(b, uint16(headerSize))
+	return binary.LittleEndian.AppendUint32(b, uint32(size))
+}
+
+func le32(b []byte, values ...uint32) []byte {
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, v)
+	}
+	return b
+}
@@ -0,0 +1,202 @@
+package apkparse
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Android binary XML chunk and value types, see frameworks/base/libs/androidfw/include/androidfw/ResourceTypes.h
+const (
+	chunkStringPool   = 0x0001
+	chunkXML          = 0x0003
+	chunkStartElement = 0x0102
+	chunkResourceMap  = 0x0180
+
+	stringPoolUTF8 = 1 << 8
+
+	typeString = 0x03
+	typeIntDec = 0x10
+	typeIntHex = 0x11
+
+	// value of attribute without raw string
+	noIndex = 0xffffffff
+)
+
+// parseBinaryXML reads manifest element attributes of compiled apk manifest
+func parseBinaryXML(b []byte) (*Manifest, error) {
+	if len(b) < 8 || binary.LittleEndian.Uint16(b) != chunkXML {
+		return nil, fmt.Errorf("%w: not an Android binary XML", errMalformed)
+	}
+	var (
+		strs   []string
+		resIDs []uint32
+		err    error
+	)
+	off := int(binary.LittleEndian.Uint16(b[2:]))
+	for off+8 <= len(b) {
+		typ := binary.LittleEndian.Uint16(b[off:])
+		headerSize := int(binary.LittleEndian.Uint16(b[off+2:]))
+		size := int(binary.LittleEndian.Uint32(b[off+4:]))
+		if size < 8 || headerSize > size || off+size > len(b) {
+			return nil, fmt.Errorf("%w: chunk at %d overflows file", errMalformed, off)
+		}
+		chunk := b[off : off+size]
+		switch typ {
+		case chunkStringPool:
+			if strs, err = parseStringPool(chunk, headerSize); err != nil {
+				return nil, err
+			}
+		case chunkResourceMap:
+			for i := headerSize; i+4 <= size; i += 4 {
+				resIDs = append(resIDs, binary.LittleEndian.Uint32(chunk[i:]))
+			}
+		case chunkStartElement:
+			// first element is the root one
+			return manifestElement(chunk, headerSize, strs, resIDs)
+		}
+		off += size
+	}
+	return nil, fmt.Errorf("%w: no manifest element", errMalformed)
+}
+
+// manifestElement reads package and version attributes of the root element
+func manifestElement(chunk []byte, headerSize int, strs []string, resIDs []uint32) (*Manifest, error) {
+	if headerSize+20 > len(chunk) {
+		return nil, fmt.Errorf("%w: element overflows chunk", errMalformed)
+	}
+	ext := chunk[headerSize:]
+	if name := poolString(strs, binary.LittleEndian.Uint32(ext[4:])); name != "manifest" {
+		return nil, fmt.Errorf("%w: root element is '%s', not manifest", errMalformed, name)
+	}
+	start := int(binary.LittleEndian.Uint16(ext[8:]))
+	size := int(binary.LittleEndian.Uint16(ext[10:]))
+	count := int(binary.LittleEndian.Uint16(ext[12:]))
+
+	m := &Manifest{}
+	for i := 0; i < count; i++ {
+		a := headerSize + start + i*size
+		if a+20 > len(chunk) {
+			return nil, fmt.Errorf("%w: attribute overflows element", errMalformed)
+		}
+		attr := chunk[a:]
+		nameIdx := binary.LittleEndian.Uint32(attr[4:])
+		raw := binary.LittleEndian.Uint32(attr[8:])
+		dataType := attr[15]
+		data := binary.LittleEndian.Uint32(attr[16:])
+
+		// apks built with resource shrinking may drop android attribute names, which resource IDs keep
+		name := poolString(strs, nameIdx)
+		if int(nameIdx) < len(resIDs) {
+			switch resIDs[nameIdx] {
+			case attrVersionCode:
+				name = "versionCode"
+			case attrVersionName:
+				name = "versionName"
+			}
+		}
+		value := poolString(strs, raw)
+		if dataType == typeString {
+			value = poolString(strs, data)
+		}
+		switch name {
+		case "package":
+			m.PackageName = value
+		case "versionName":
+			m.VersionName = value
+		case "versionCode":
+			if dataType == typeIntDec || dataType == typeIntHex {
+				m.VersionCode = int64(data)
+				continue
+			}
+			v, err := strconv.ParseInt(value, 0, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: versionCode '%s' is not a number", errMalformed, value)
+			}
+			m.VersionCode = v
+		}
+	}
+	return m, nil
+}
+
+// parseStringPool decodes every string of UTF-8 or UTF-16 string pool chunk
+func parseStringPool(chunk []byte, headerSize int) ([]string, error) {
+	if headerSize < 28 {
+		return nil, fmt.Errorf("%w: string pool header too short", errMalformed)
+	}
+	count := int(binary.LittleEndian.Uint32(chunk[8:]))
+	utf8 := binary.LittleEndian.Uint32(chunk[16:])&stringPoolUTF8 != 0
+	start := int(binary.LittleEndian.Uint32(chunk[20:]))
+	if count < 0 || headerSize+count*4 > len(chunk) {
+		return nil, fmt.Errorf("%w: string pool offsets overflow chunk", errMalformed)
+	}
+
+	strs := make([]string, count)
+	for i := range strs {
+		o := start + int(binary.LittleEndian.Uint32(chunk[headerSize+i*4:]))
+		if o < 0 || o >= len(chunk) {
+			return nil, fmt.Errorf("%w: string %d overflows string pool", errMalformed, i)
+		}
+		s, ok := decodeString(chunk[o:], utf8)
+		if !ok {
+			return nil, fmt.Errorf("%w: string %d overflows string pool", errMalformed, i)
+		}
+		strs[i] = s
+	}
+	return strs, nil
+}
+
+// decodeString reads length prefixed pool string, false if it runs past b
+func decodeString(b []byte, utf8 bool) (string, bool) {
+	if utf8 {
+		// UTF-16 length comes first, then UTF-8 byte length, each 1 or 2 bytes
+		i := 1
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			i = 2
+		}
+		if i >= len(b) {
+			return "", false
+		}
+		n := int(b[i])
+		i++
+		if n&0x80 != 0 {
+			if i >= len(b) {
+				return "", false
+			}
+			n = (n&0x7f)<<8 | int(b[i])
+			i++
+		}
+		if i+n > len(b) {
+			return "", false
+		}
+		return string(b[i : i+n]), true
+	}
+
+	if len(b) < 2 {
+		return "", false
+	}
+	n, i := int(binary.LittleEndian.Uint16(b)), 2
+	if n&0x8000 != 0 {
+		if len(b) < 4 {
+			return "", false
+		}
+		n, i = (n&0x7fff)<<16|int(binary.LittleEndian.Uint16(b[2:])), 4
+	}
+	if i+n*2 > len(b) {
+		return "", false
+	}
+	units := make([]uint16, n)
+	for j := range units {
+		units[j] = binary.LittleEndian.Uint16(b[i+j*2:])
+	}
+	return string(utf16.Decode(units)), true
+}
+
+// poolString returns string at index, empty for no or out of range index
+func poolString(strs []string, idx uint32) string {
+	if idx == noIndex || int(idx) >= len(strs) {
+		return ""
+	}
+	return strs[idx]
+}
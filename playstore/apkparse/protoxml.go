@@ -0,0 +1,201 @@
+package apkparse
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// aapt2 Resources.proto field numbers of manifest parts read here
+const (
+	xmlNodeElement = 1 // XmlNode.element
+
+	xmlElementName      = 3 // XmlElement.name
+	xmlElementAttribute = 4 // XmlElement.attribute
+
+	xmlAttributeName         = 2 // XmlAttribute.name
+	xmlAttributeValue        = 3 // XmlAttribute.value
+	xmlAttributeResourceID   = 5 // XmlAttribute.resource_id
+	xmlAttributeCompiledItem = 6 // XmlAttribute.compiled_item
+
+	itemPrim       = 7 // Item.prim
+	primIntDecimal = 6 // Primitive.int_decimal_value
+	primIntHex     = 7 // Primitive.int_hexadecimal_value
+)
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// parseProtoXML reads manifest element attributes of bundle manifest compiled to aapt2 XmlNode
+func parseProtoXML(b []byte) (*Manifest, error) {
+	var element []byte
+	err := protoFields(b, func(num, wire int, _ uint64, data []byte) error {
+		if num == xmlNodeElement && wire == wireBytes {
+			element = data
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if element == nil {
+		return nil, fmt.Errorf("%w: no manifest element", errMalformed)
+	}
+
+	var (
+		name  string
+		attrs [][]byte
+	)
+	err = protoFields(element, func(num, wire int, _ uint64, data []byte) error {
+		switch {
+		case num == xmlElementName && wire == wireBytes:
+			name = string(data)
+		case num == xmlElementAttribute && wire == wireBytes:
+			attrs = append(attrs, data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if name != "manifest" {
+		return nil, fmt.Errorf("%w: root element is '%s', not manifest", errMalformed, name)
+	}
+
+	m := &Manifest{}
+	for _, a := range attrs {
+		attr, err := parseProtoAttribute(a)
+		if err != nil {
+			return nil, err
+		}
+		switch attr.name {
+		case "package":
+			m.PackageName = attr.value
+		case "versionName":
+			m.VersionName = attr.value
+		case "versionCode":
+			if attr.hasInt {
+				m.VersionCode = attr.int
+				continue
+			}
+			v, err := strconv.ParseInt(attr.value, 0, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: versionCode '%s' is not a number", errMalformed, attr.value)
+			}
+			m.VersionCode = v
+		}
+	}
+	return m, nil
+}
+
+// protoAttribute XmlAttribute name with its source value and compiled integer if any
+type protoAttribute struct {
+	name   string
+	value  string
+	int    int64
+	hasInt bool
+}
+
+func parseProtoAttribute(b []byte) (protoAttribute, error) {
+	var (
+		a     protoAttribute
+		resID uint64
+		item  []byte
+	)
+	err := protoFields(b, func(num, wire int, v uint64, data []byte) error {
+		switch {
+		case num == xmlAttributeName && wire == wireBytes:
+			a.name = string(data)
+		case num == xmlAttributeValue && wire == wireBytes:
+			a.value = string(data)
+		case num == xmlAttributeResourceID && wire == wireVarint:
+			resID = v
+		case num == xmlAttributeCompiledItem && wire == wireBytes:
+			item = data
+		}
+		return nil
+	})
+	if err != nil {
+		return a, err
+	}
+	switch resID {
+	case attrVersionCode:
+		a.name = "versionCode"
+	case attrVersionName:
+		a.name = "versionName"
+	}
+	if item == nil {
+		return a, nil
+	}
+
+	var prim []byte
+	err = protoFields(item, func(num, wire int, _ uint64, data []byte) error {
+		if num == itemPrim && wire == wireBytes {
+			prim = data
+		}
+		return nil
+	})
+	if err != nil || prim == nil {
+		return a, err
+	}
+	err = protoFields(prim, func(num, wire int, v uint64, _ []byte) error {
+		if (num == primIntDecimal || num == primIntHex) && wire == wireVarint {
+			// int32 values are sign extended to 64 bits
+			a.int, a.hasInt = int64(int32(v)), true
+		}
+		return nil
+	})
+	return a, err
+}
+
+// protoFields calls fn for every field of protobuf encoded message, with varint value or length
+// delimited content depending on wire type
+func protoFields(b []byte, fn func(num, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return fmt.Errorf("%w: invalid protobuf field key", errMalformed)
+		}
+		b = b[n:]
+		num, wire := int(key>>3), int(key&7)
+
+		var (
+			v    uint64
+			data []byte
+		)
+		switch wire {
+		case wireVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return fmt.Errorf("%w: invalid protobuf varint", errMalformed)
+			}
+			b = b[n:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > uint64(len(b)-n) {
+				return fmt.Errorf("%w: protobuf field overflows message", errMalformed)
+			}
+			data, b = b[n:n+int(l)], b[n+int(l):]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if wire == wireFixed32 {
+				size = 4
+			}
+			if len(b) < size {
+				return fmt.Errorf("%w: protobuf field overflows message", errMalformed)
+			}
+			b = b[size:]
+		default:
+			return fmt.Errorf("%w: unsupported protobuf wire type %d", errMalformed, wire)
+		}
+		if err := fn(num, wire, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+// listing image types removed together with a pruned locale
+var listingImageTypes = []string{
+	ImageTypePhoneScreenshots,
+	ImageTypeSevenInchScreenshots,
+	ImageTypeTenInchScreenshots,
+	ImageTypeTvScreenshots,
+	ImageTypeWearScreenshots,
+	ImageTypeIcon,
+	ImageTypeFeatureGraphic,
+	ImageTypeTvBanner,
+}
+
+// ApplyReport difference between local metadata and Play, and what of it was applied
+type ApplyReport struct {
+	Added     []string     `json:"added"`     // locales with new listings
+	Updated   []string     `json:"updated"`   // locales with changed listings
+	Unchanged []string     `json:"unchanged"` // locales matching Play already
+	Deleted   []string     `json:"deleted"`   // remote locales no longer present locally, deleted with prune
+	Unmanaged []string     `json:"unmanaged"` // remote locales no longer present locally, left as is without prune
+	DryRun    bool         `json:"dryRun"`    // nothing was changed on Play
+	Images    *ImageReport `json:"images,omitempty"`
+}
+
+/**
+ * Apply makes Play store listings match metadata directory
+ *
+ * metadataDir - fastlane style metadata directory, see ReadListings, with optional <locale>/images/, see ReadMetadataImages
+ * prune - delete listings and images for locales not present in metadataDir
+ * dryRun - only report differences, edit is deleted without any changes
+ */
+func Apply(ctx context.Context, gs IGService, fs afero.Fs, packageName, metadataDir string, prune, dryRun bool) (*ApplyReport, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	local, err := ReadListings(fs, metadataDir)
+	if err != nil {
+		return nil, err
+	}
+	images, err := ReadMetadataImages(fs, metadataDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(local) == 0 && len(images) == 0 {
+		return nil, fmt.Errorf("no listings found in '%s'", metadataDir)
+	}
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	report, changed, err := applyMetadata(ctx, gs, fs, name, edit, local, images, prune, dryRun)
+	if err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if dryRun || !changed {
+		gs.deleteEdit(ctx, name, edit)
+		return report, nil
+	}
+
+	if err := gs.validateEdit(ctx, name, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if err := gs.commitEdit(ctx, name, edit, false); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	return report, nil
+}
+
+// applyMetadata makes listings and images within edit match local ones and returns whether anything changed.
+// dryRun only reports differences.
+func applyMetadata(ctx context.Context, gs IGService, fs afero.Fs, packageName, editId string, local []Listing, images []LocaleImages, prune, dryRun bool) (*ApplyReport, bool, error) {
+	remote, err := gs.listListings(ctx, packageName, editId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	report, changes := diffListings(local, remote, prune)
+	report.DryRun = dryRun
+	if dryRun {
+		report.Images, err = pushImages(ctx, gs, fs, packageName, editId, images, true)
+		if err != nil {
+			return nil, false, err
+		}
+		return report, false, nil
+	}
+
+	// listings go first, Play needs locale listing before its images
+	for _, l := range changes {
+		if err := gs.updateListing(ctx, packageName, editId, l); err != nil {
+			return nil, false, fmt.Errorf("failed updating '%s' listing: %w", l.Locale, err)
+		}
+	}
+	report.Images, err = pushImages(ctx, gs, fs, packageName, editId, images, false)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, locale := range report.Deleted {
+		for _, t := range listingImageTypes {
+			if _, err := gs.deleteAllImages(ctx, packageName, editId, locale, t); err != nil {
+				return nil, false, fmt.Errorf("failed deleting '%s' '%s' images: %w", locale, t, err)
+			}
+		}
+		if err := gs.deleteListing(ctx, packageName, editId, locale); err != nil {
+			return nil, false, fmt.Errorf("failed deleting '%s' listing: %w", locale, err)
+		}
+	}
+	changed := len(changes) != 0 || len(report.Deleted) != 0 || len(report.Images.Uploaded) != 0
+	return report, changed, nil
+}
+
+// diffListings compares local and remote listings and returns report with listings that need updating
+func diffListings(local, remote []Listing, prune bool) (*ApplyReport, []Listing) {
+	report := &ApplyReport{}
+	byLocale := make(map[string]Listing, len(remote))
+	for _, r := range remote {
+		byLocale[r.Locale] = r
+	}
+
+	changes := make([]Listing, 0)
+	seen := make(map[string]bool, len(local))
+	for _, l := range local {
+		seen[l.Locale] = true
+		r, ok := byLocale[l.Locale]
+		switch {
+		case !ok:
+			report.Added = append(report.Added, l.Locale)
+			changes = append(changes, l)
+		case r != l:
+			report.Updated = append(report.Updated, l.Locale)
+			changes = append(changes, l)
+		default:
+			report.Unchanged = append(report.Unchanged, l.Locale)
+		}
+	}
+
+	stale := make([]string, 0)
+	for _, r := range remote {
+		if !seen[r.Locale] {
+			stale = append(stale, r.Locale)
+		}
+	}
+	sort.Strings(stale)
+	if prune {
+		report.Deleted = stale
+	} else {
+		report.Unmanaged = stale
+	}
+	return report, changes
+}
@@ -0,0 +1,121 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestApply(t *testing.T) {
+
+	createMetadata := func(t *testing.T) afero.Fs {
+		t.Helper()
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "metadata/en-US/title.txt", []byte("Sample"), 0o644)
+		afero.WriteFile(fs, "metadata/en-US/short_description.txt", []byte("Short"), 0o644)
+		afero.WriteFile(fs, "metadata/lt-LT/title.txt", []byte("Pavyzdys\n"), 0o644)
+		return fs
+	}
+
+	t.Run("should update changed listings and keep unmanaged locales", func(t *testing.T) {
+		// Arrange
+		fs := createMetadata(t)
+		gs := &mockGService{listings: []Listing{
+			{Locale: "en-US", Title: "Sample", ShortDescription: "Short"},
+			{Locale: "lt-LT", Title: "Senas"},
+			{Locale: "de-DE", Title: "Beispiel"},
+		}}
+
+		// Act
+		report, err := Apply(context.Background(), gs, fs, "com.test.app", "metadata", false, false)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(gs.updatedListings, []Listing{{Locale: "lt-LT", Title: "Pavyzdys"}}) {
+			t.Errorf("want only lt-LT listing updated, got %+v", gs.updatedListings)
+		}
+		if !reflect.DeepEqual(report.Unmanaged, []string{"de-DE"}) || len(gs.deletedListings) != 0 {
+			t.Errorf("want de-DE left unmanaged, got %+v deleted %v", report, gs.deletedListings)
+		}
+		if gs.commitEditCount != 1 {
+			t.Errorf("want 1 commitEdit call, got %d", gs.commitEditCount)
+		}
+	})
+
+	t.Run("should delete listings and images of removed locales with prune", func(t *testing.T) {
+		// Arrange
+		fs := createMetadata(t)
+		gs := &mockGService{listings: []Listing{{Locale: "de-DE", Title: "Beispiel"}}}
+
+		// Act
+		report, err := Apply(context.Background(), gs, fs, "com.test.app", "metadata", true, false)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(report.Added, []string{"en-US", "lt-LT"}) {
+			t.Errorf("want en-US and lt-LT added, got %v", report.Added)
+		}
+		if !reflect.DeepEqual(gs.deletedListings, []string{"de-DE"}) {
+			t.Errorf("want de-DE listing deleted, got %v", gs.deletedListings)
+		}
+		if len(gs.deletedImages) != len(listingImageTypes) {
+			t.Errorf("want all de-DE image types deleted, got %v", gs.deletedImages)
+		}
+	})
+
+	t.Run("should not change anything on dry run", func(t *testing.T) {
+		// Arrange
+		fs := createMetadata(t)
+		gs := &mockGService{listings: []Listing{{Locale: "de-DE", Title: "Beispiel"}}}
+
+		// Act
+		report, err := Apply(context.Background(), gs, fs, "com.test.app", "metadata", true, true)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(report.Deleted, []string{"de-DE"}) {
+			t.Errorf("want de-DE reported for deletion, got %v", report.Deleted)
+		}
+		if len(gs.updatedListings) != 0 || len(gs.deletedListings) != 0 || gs.commitEditCount != 0 {
+			t.Errorf("want no changes, got %d updates %d deletes %d commits", len(gs.updatedListings), len(gs.deletedListings), gs.commitEditCount)
+		}
+		if gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted, got %d deleteEdit calls", gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should publish listings and images in one edit", func(t *testing.T) {
+		// Arrange
+		fs := createMetadata(t)
+		createTestImage(t, fs, "metadata/en-US/images/icon.png", 512, 512)
+		gs := &mockGService{listings: []Listing{
+			{Locale: "en-US", Title: "Sample", ShortDescription: "Short"},
+			{Locale: "lt-LT", Title: "Pavyzdys"},
+		}}
+
+		// Act
+		report, err := Apply(context.Background(), gs, fs, "com.test.app", "metadata", false, false)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(gs.uploadedImages, []string{"en-US/" + ImageTypeIcon}) {
+			t.Errorf("want en-US icon uploaded, got %v", gs.uploadedImages)
+		}
+		if report.Images.Uploaded["en-US"][ImageTypeIcon] != 1 {
+			t.Errorf("want icon upload reported, got %v", report.Images)
+		}
+		if gs.createEditCount != 1 || gs.commitEditCount != 1 {
+			t.Errorf("want single committed edit, got %d created %d committed", gs.createEditCount, gs.commitEditCount)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// AppAccess result of probing whether service account can publish app
+type AppAccess struct {
+	PackageName string `json:"packageName"`
+	Accessible  bool   `json:"accessible"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// ProbeApps checks which of given apps service account can access, by opening and deleting an edit for each.
+// Play Developer API has no way of listing apps, so package names have to be known upfront.
+func ProbeApps(ctx context.Context, gs IGService, packageNames []string) ([]AppAccess, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	if len(packageNames) == 0 {
+		return nil, fmt.Errorf("at least one package name to probe is required")
+	}
+
+	apps := make([]AppAccess, 0, len(packageNames))
+	for _, n := range packageNames {
+		name := strings.TrimSpace(n)
+		if name == "" {
+			return nil, fmt.Errorf("package name must not be empty")
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		edit, _, err := gs.createEdit(ctx, name)
+		if err != nil {
+			apps = append(apps, AppAccess{PackageName: name, Reason: accessFailureReason(err)})
+			continue
+		}
+		gs.deleteEdit(ctx, name, edit)
+		apps = append(apps, AppAccess{PackageName: name, Accessible: true})
+	}
+	return apps, nil
+}
+
+// accessFailureReason explains create edit failure in terms of Play Console setup
+func accessFailureReason(err error) string {
+	var ge *googleapi.Error
+	if errors.As(err, &ge) {
+		switch ge.Code {
+		case http.StatusNotFound:
+			return "package not found, app must be created and have at least one upload in Play Console"
+		case http.StatusForbidden, http.StatusUnauthorized:
+			return "permission denied, grant service account access to the app in Play Console users and permissions"
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,50 @@
+package playstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestProbeApps(t *testing.T) {
+
+	t.Run("should report access per app", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{createEditErrors: map[string]error{
+			"com.test.missing": &googleapi.Error{Code: 404},
+			"com.test.denied":  &googleapi.Error{Code: 403},
+		}}
+
+		// Act
+		apps, err := ProbeApps(context.Background(), gs, []string{"com.test.app", "com.test.missing", "com.test.denied"})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(apps) != 3 {
+			t.Fatalf("want 3 apps, got %d", len(apps))
+		}
+		if !apps[0].Accessible || apps[1].Accessible || apps[2].Accessible {
+			t.Errorf("want only 'com.test.app' accessible, got %+v", apps)
+		}
+		if !strings.Contains(apps[1].Reason, "not found") || !strings.Contains(apps[2].Reason, "permission") {
+			t.Errorf("want not found and permission reasons, got '%s' and '%s'", apps[1].Reason, apps[2].Reason)
+		}
+		if gs.deleteEditCount != 1 {
+			t.Errorf("want probe edit deleted once, got %d", gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should fail without package names", func(t *testing.T) {
+		// Act
+		_, err := ProbeApps(context.Background(), &mockGService{}, nil)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Hashes digests Play reports for an uploaded bundle or apk
+type Hashes struct {
+	Sha256 string
+	Sha1   string
+}
+
+// UploadResult binary uploaded by publish with digests verified against Play
+type UploadResult struct {
+	Path        string        `json:"path"`
+	VersionCode int64         `json:"versionCode"`
+	Sha256      string        `json:"sha256"`
+	Sha1        string        `json:"sha1"`
+	Duration    time.Duration `json:"durationNs"`
+	// Play had the binary already, see WithSkipExisting
+	Skipped bool `json:"skipped,omitempty"`
+	// mappings and expansion files uploaded with the binary, see WithFileDigests
+	Files []FileDigest `json:"files,omitempty"`
+}
+
+// VersionHashes returns hashes of every bundle and apk uploaded for the app by appVersionCode.
+// It uses a throwaway edit, which is deleted once listing is done.
+func VersionHashes(ctx context.Context, gs IGService, packageName string) (map[int64]Hashes, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer gs.deleteEdit(ctx, name, edit)
+
+	return gs.versionHashes(ctx, name, edit)
+}
@@ -0,0 +1,40 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestVersionHashes(t *testing.T) {
+
+	t.Run("should return hashes by version code and delete edit", func(t *testing.T) {
+		// Arrange
+		expected := map[int64]Hashes{1: {Sha256: "a256", Sha1: "a1"}, 2: {Sha256: "b256", Sha1: "b1"}}
+		gs := &mockGService{hashes: expected}
+
+		// Act
+		actual, err := VersionHashes(context.Background(), gs, "com.test.app")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("want %v, got %v", expected, actual)
+		}
+		if gs.deleteEditCount != 1 {
+			t.Errorf("want 1 deleteEdit call, got %d", gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should not allow empty packageName", func(t *testing.T) {
+		// Act
+		_, err := VersionHashes(context.Background(), &mockGService{}, " ")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
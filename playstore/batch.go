@@ -0,0 +1,361 @@
+package playstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// BatchConfig apps published together with PublishBatch, e.g. white-label variants of the same app
+type BatchConfig struct {
+	// stop at first app failing to publish, leaving the rest unpublished
+	FailFast bool `json:"failFast,omitempty"`
+	// authentication file apps are published with, credentials from environment if not set
+	AuthFile string     `json:"authFile,omitempty"`
+	Apps     []BatchApp `json:"apps"`
+	// named blocks e.g. staging and prod, one of which is selected with Environment
+	Environments map[string]BatchEnvironment `json:"environments,omitempty"`
+	// app published to every one of PackageNames, with app of Overrides applied for its package name,
+	// see TemplateApps. Apps of template follow Apps.
+	Template     *BatchApp           `json:"template,omitempty"`
+	PackageNames []string            `json:"appIds,omitempty"`
+	Overrides    map[string]BatchApp `json:"overrides,omitempty"`
+}
+
+// batchAppIDPlaceholder replaced with package name in binary and mapping paths of template app
+const batchAppIDPlaceholder = "{appId}"
+
+// TemplateApps returns template app for every package name e.g. white-label flavors sharing metadata,
+// '{appId}' in its binary and mapping paths replaced with the package name. Override of a package name
+// replaces template fields it sets, its release notes are merged with template ones by locale. Overrides
+// of other package names are ignored.
+func TemplateApps(template BatchApp, packageNames []string, overrides map[string]BatchApp) ([]BatchApp, error) {
+	listed := make(map[string]bool, len(packageNames))
+	apps := make([]BatchApp, 0, len(packageNames))
+	for _, name := range packageNames {
+		if listed[name] {
+			return nil, fmt.Errorf("template package name '%s' is listed more than once", name)
+		}
+		listed[name] = true
+		apps = append(apps, template.overridden(name, overrides[name]))
+	}
+	return apps, nil
+}
+
+// overridden returns app of template for package name with override applied
+func (a BatchApp) overridden(packageName string, o BatchApp) BatchApp {
+	app := a
+	app.PackageName = packageName
+	if o.Track != "" {
+		app.Track = o.Track
+	}
+	if o.Apk {
+		app.Apk = true
+	}
+	if len(o.Binaries) > 0 {
+		app.Binaries = o.Binaries
+	}
+	if o.RolloutFraction != 0 {
+		app.RolloutFraction = o.RolloutFraction
+	}
+	if o.UpdatePriority != 0 {
+		app.UpdatePriority = o.UpdatePriority
+	}
+	if len(o.ReleaseNotes) > 0 {
+		notes := make(map[string]string, len(a.ReleaseNotes)+len(o.ReleaseNotes))
+		for l, text := range a.ReleaseNotes {
+			notes[l] = text
+		}
+		for l, text := range o.ReleaseNotes {
+			notes[l] = text
+		}
+		app.ReleaseNotes = notes
+	}
+	binaries := make([]BatchBinary, len(app.Binaries))
+	for i, b := range app.Binaries {
+		b.Path = strings.ReplaceAll(b.Path, batchAppIDPlaceholder, packageName)
+		b.Mapping = strings.ReplaceAll(b.Mapping, batchAppIDPlaceholder, packageName)
+		binaries[i] = b
+	}
+	app.Binaries = binaries
+	return app
+}
+
+// BatchEnvironment named block of batch config, fields it sets replace the ones of config
+type BatchEnvironment struct {
+	FailFast *bool  `json:"failFast,omitempty"`
+	AuthFile string `json:"authFile,omitempty"`
+	// track of apps without track of their own
+	Track string     `json:"track,omitempty"`
+	Apps  []BatchApp `json:"apps,omitempty"`
+}
+
+// Environment returns config with named environment applied, failing for environment config doesn't have
+func (c *BatchConfig) Environment(name string) (*BatchConfig, error) {
+	env, ok := c.Environments[name]
+	if !ok {
+		names := make([]string, 0, len(c.Environments))
+		for n := range c.Environments {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("batch config has no '%s' environment, environments are %v", name, names)
+	}
+	config := &BatchConfig{FailFast: c.FailFast, AuthFile: c.AuthFile, Apps: c.Apps}
+	if env.FailFast != nil {
+		config.FailFast = *env.FailFast
+	}
+	if env.AuthFile != "" {
+		config.AuthFile = env.AuthFile
+	}
+	if len(env.Apps) > 0 {
+		config.Apps = env.Apps
+	}
+	if env.Track != "" {
+		apps := make([]BatchApp, len(config.Apps))
+		for i, a := range config.Apps {
+			if a.Track == "" {
+				a.Track = env.Track
+			}
+			apps[i] = a
+		}
+		config.Apps = apps
+	}
+	if len(config.Apps) == 0 {
+		return nil, fmt.Errorf("'%s' environment of batch config has no apps", name)
+	}
+	return config, nil
+}
+
+// BatchApp app of a batch and binaries published to it
+type BatchApp struct {
+	PackageName     string            `json:"appId"`
+	Track           string            `json:"track,omitempty"` // internal if not set
+	Apk             bool              `json:"apk,omitempty"`
+	Binaries        []BatchBinary     `json:"binaries"`
+	RolloutFraction float64           `json:"rolloutFraction,omitempty"`
+	UpdatePriority  int               `json:"updatePriority,omitempty"`
+	ReleaseNotes    map[string]string `json:"releaseNotes,omitempty"`
+}
+
+// BatchBinary binary of a batch app with its optional mappings
+type BatchBinary struct {
+	Path    string            `json:"path"`
+	Mapping string            `json:"mapping,omitempty"`
+	Notes   map[string]string `json:"notes,omitempty"` // release notes lines of the binary by locale, see Noted
+}
+
+// BatchResult outcome of publishing one app of a batch
+type BatchResult struct {
+	PackageName string   `json:"appId"`
+	Track       string   `json:"track"`
+	Receipt     *Receipt `json:"receipt,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	// not published, as batch stopped at an earlier failure or was cancelled
+	Skipped bool  `json:"skipped,omitempty"`
+	Err     error `json:"-"`
+}
+
+// BatchReport outcome of every app of a batch, in order apps were given
+type BatchReport struct {
+	Apps []BatchResult `json:"apps"`
+}
+
+// Failed returns number of apps that failed to publish, skipped ones excluded
+func (r *BatchReport) Failed() int {
+	failed := 0
+	for _, a := range r.Apps {
+		if a.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Err returns nil when every app was published, otherwise BatchError
+func (r *BatchReport) Err() error {
+	for _, a := range r.Apps {
+		if a.Err != nil || a.Skipped {
+			return &BatchError{Report: r}
+		}
+	}
+	return nil
+}
+
+// BatchError batch with apps that failed or were skipped, wrapping the first app failure
+type BatchError struct {
+	Report *BatchReport
+}
+
+func (e *BatchError) Error() string {
+	if err := e.Unwrap(); err != nil {
+		return fmt.Sprintf("%d of %d apps failed publishing: %v", e.Report.Failed(), len(e.Report.Apps), err)
+	}
+	return fmt.Sprintf("%d of %d apps were not published", len(e.Report.Apps)-e.published(), len(e.Report.Apps))
+}
+
+func (e *BatchError) Unwrap() error {
+	for _, a := range e.Report.Apps {
+		if a.Err != nil {
+			return fmt.Errorf("app '%s': %w", a.PackageName, a.Err)
+		}
+	}
+	return nil
+}
+
+func (e *BatchError) published() int {
+	published := 0
+	for _, a := range e.Report.Apps {
+		if a.Receipt != nil {
+			published++
+		}
+	}
+	return published
+}
+
+// LoadBatchConfig reads batch config file, YAML if it has .yaml or .yml extension and JSON otherwise.
+// YAML keys are the same as JSON ones.
+func LoadBatchConfig(fs afero.Fs, path string) (*BatchConfig, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading batch config '%s': %w", path, err)
+	}
+	format := "JSON"
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		format = "YAML"
+		if b, err = yamlToJSON(b); err != nil {
+			return nil, fmt.Errorf("failed parsing batch config '%s', expected YAML: %w", path, err)
+		}
+	}
+	config := &BatchConfig{}
+	if err := json.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("failed parsing batch config '%s', expected %s: %w", path, format, err)
+	}
+	if config.Template != nil {
+		if len(config.PackageNames) == 0 {
+			return nil, fmt.Errorf("batch config '%s' has template but no appIds to publish it to", path)
+		}
+		apps, err := TemplateApps(*config.Template, config.PackageNames, config.Overrides)
+		if err != nil {
+			return nil, fmt.Errorf("batch config '%s': %w", path, err)
+		}
+		if err := validateOverrides(config.Overrides, config.PackageNames); err != nil {
+			return nil, fmt.Errorf("batch config '%s': %w", path, err)
+		}
+		config.Apps = append(config.Apps, apps...)
+	} else if len(config.PackageNames) > 0 || len(config.Overrides) > 0 {
+		return nil, fmt.Errorf("batch config '%s' has appIds or overrides but no template", path)
+	}
+	if len(config.Apps) == 0 && len(config.Environments) == 0 {
+		return nil, fmt.Errorf("batch config '%s' has no apps", path)
+	}
+	if err := validateBatchApps(config.Apps, fmt.Sprintf("batch config '%s'", path)); err != nil {
+		return nil, err
+	}
+	for name, env := range config.Environments {
+		if err := validateBatchApps(env.Apps, fmt.Sprintf("'%s' environment of batch config '%s'", name, path)); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// yamlToJSON converts YAML document to JSON, so config is decoded by its JSON tags whatever the format
+func yamlToJSON(b []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// validateOverrides checks every override is for one of package names, catching typos
+func validateOverrides(overrides map[string]BatchApp, packageNames []string) error {
+	listed := make(map[string]bool, len(packageNames))
+	for _, name := range packageNames {
+		listed[name] = true
+	}
+	names := make([]string, 0)
+	for name := range overrides {
+		if !listed[name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return fmt.Errorf("overrides of %v are for package names not in appIds", names)
+}
+
+// validateBatchApps checks every app has appId and binaries
+func validateBatchApps(apps []BatchApp, where string) error {
+	for i, a := range apps {
+		if a.PackageName == "" {
+			return fmt.Errorf("app %d of %s has no appId", i+1, where)
+		}
+		if len(a.Binaries) == 0 {
+			return fmt.Errorf("app '%s' of %s has no binaries", a.PackageName, where)
+		}
+	}
+	return nil
+}
+
+// PublishBatch publishes binaries of every app one after another with its own edit, options shared by all
+// of them. App failing doesn't stop the rest unless failFast is set, see BatchReport for outcome of each.
+func PublishBatch(ctx context.Context, fs afero.Fs, gs IGService, authFile string, apps []BatchApp, failFast, verbose bool, opts ...Option) *BatchReport {
+	report := &BatchReport{Apps: make([]BatchResult, 0, len(apps))}
+	stopped := false
+	for _, a := range apps {
+		track := a.Track
+		if track == "" {
+			track = TrackInternal
+		}
+		res := BatchResult{PackageName: a.PackageName, Track: track}
+		if stopped || ctx.Err() != nil {
+			res.Skipped = true
+			report.Apps = append(report.Apps, res)
+			continue
+		}
+		res.Receipt, res.Err = publishBatchApp(ctx, fs, gs, authFile, a, track, verbose, opts)
+		if res.Err != nil {
+			res.Error = res.Err.Error()
+			stopped = failFast
+		}
+		report.Apps = append(report.Apps, res)
+	}
+	return report
+}
+
+// publishBatchApp publishes app of a batch, its rollout fraction and release notes overriding shared ones
+func publishBatchApp(ctx context.Context, fs afero.Fs, gs IGService, authFile string, a BatchApp, track string, verbose bool, opts []Option) (*Receipt, error) {
+	files := make([]binary, 0, len(a.Binaries))
+	for _, b := range a.Binaries {
+		files = append(files, Noted(BinaryWithMapping(b.Path, b.Mapping), b.Notes))
+	}
+	appOpts := append([]Option{}, opts...)
+	if a.RolloutFraction != 0 {
+		appOpts = append(appOpts, WithRolloutFraction(a.RolloutFraction))
+	}
+	if a.UpdatePriority != 0 {
+		appOpts = append(appOpts, WithUpdatePriority(a.UpdatePriority))
+	}
+	if len(a.ReleaseNotes) != 0 {
+		appOpts = append(appOpts, ReleaseNotes(a.ReleaseNotes))
+	}
+	p, err := Publish(ctx, fs, a.PackageName, track, authFile, files, a.Apk, verbose, appOpts...)
+	if err != nil {
+		return nil, err
+	}
+	results, err := p.UploadFiles(ctx, gs)
+	if err != nil {
+		return nil, err
+	}
+	return p.Receipt(results), nil
+}
@@ -0,0 +1,221 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestPublishBatch(t *testing.T) {
+
+	apps := []BatchApp{
+		{PackageName: "com.test.red", Binaries: []BatchBinary{{Path: "red.aab"}}},
+		{PackageName: "com.test.green", Binaries: []BatchBinary{{Path: "missing.aab"}}},
+		{PackageName: "com.test.blue", Track: TrackAlpha, Binaries: []BatchBinary{{Path: "blue.aab"}}},
+	}
+
+	t.Run("should publish every app reporting failed ones", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "red.aab", 10)
+		createTestFile(t, fs, "blue.aab", 20)
+		gs := &mockGService{AppVersionCode: 3}
+
+		// Act
+		report := PublishBatch(context.Background(), fs, gs, "auth.json", apps, false, false)
+
+		// Assert
+		var be *BatchError
+		if report.Failed() != 1 || !errors.As(report.Err(), &be) {
+			t.Fatalf("want 1 failed app, got %d: %v", report.Failed(), report.Err())
+		}
+		if report.Apps[0].Receipt == nil || report.Apps[0].Receipt.PackageName != "com.test.red" {
+			t.Errorf("want 'com.test.red' published, got %+v", report.Apps[0])
+		}
+		if report.Apps[1].Error == "" || report.Apps[1].Skipped {
+			t.Errorf("want 'com.test.green' failed, got %+v", report.Apps[1])
+		}
+		if report.Apps[2].Receipt == nil || report.Apps[2].Track != TrackAlpha {
+			t.Errorf("want 'com.test.blue' published to alpha, got %+v", report.Apps[2])
+		}
+		if gs.commitEditCount != 2 {
+			t.Errorf("want 2 edits committed, got %d", gs.commitEditCount)
+		}
+	})
+
+	t.Run("should skip remaining apps after failure with fail fast", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "red.aab", 10)
+		createTestFile(t, fs, "blue.aab", 20)
+		gs := &mockGService{AppVersionCode: 3}
+
+		// Act
+		report := PublishBatch(context.Background(), fs, gs, "auth.json", apps, true, false)
+
+		// Assert
+		if !report.Apps[2].Skipped || report.Apps[2].Receipt != nil {
+			t.Errorf("want 'com.test.blue' skipped, got %+v", report.Apps[2])
+		}
+		if report.Failed() != 1 || gs.commitEditCount != 1 {
+			t.Errorf("want 1 failed and 1 committed app, got %d failed and %d committed", report.Failed(), gs.commitEditCount)
+		}
+	})
+
+	t.Run("should load batch config", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "multi.yaml", []byte(`{"failFast": true, "apps": [{"appId": "com.test.red", "track": "beta", "binaries": [{"path": "red.aab", "mapping": "red.txt"}]}]}`), 0644)
+		afero.WriteFile(fs, "empty.yaml", []byte(`{"apps": [{"appId": "com.test.red"}]}`), 0644)
+
+		// Act
+		config, err := LoadBatchConfig(fs, "multi.yaml")
+		_, emptyErr := LoadBatchConfig(fs, "empty.yaml")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !config.FailFast || config.Apps[0].Track != TrackBeta || config.Apps[0].Binaries[0].Mapping != "red.txt" {
+			t.Errorf("want config parsed, got %+v", config)
+		}
+		if emptyErr == nil {
+			t.Error("want error for app without binaries")
+		}
+	})
+
+	t.Run("should load YAML batch config", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "multi.yml", []byte(`failFast: true
+apps:
+  - appId: com.test.red
+    track: beta
+    rolloutFraction: 0.1
+    binaries:
+      - path: red.aab
+        mapping: red.txt
+environments:
+  prod:
+    authFile: prod.json
+`), 0644)
+		afero.WriteFile(fs, "broken.yaml", []byte("apps: [\n"), 0644)
+
+		// Act
+		config, err := LoadBatchConfig(fs, "multi.yml")
+		_, brokenErr := LoadBatchConfig(fs, "broken.yaml")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		app := config.Apps[0]
+		if !config.FailFast || app.PackageName != "com.test.red" || app.Track != TrackBeta || app.RolloutFraction != 0.1 || app.Binaries[0].Mapping != "red.txt" {
+			t.Errorf("want config parsed, got %+v", config)
+		}
+		if config.Environments["prod"].AuthFile != "prod.json" {
+			t.Errorf("want prod environment parsed, got %+v", config.Environments)
+		}
+		if brokenErr == nil || !strings.Contains(brokenErr.Error(), "expected YAML") {
+			t.Errorf("want YAML parse error, got: %v", brokenErr)
+		}
+	})
+
+	t.Run("should apply selected environment", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "envs.json", []byte(`{
+			"authFile": "dev.json",
+			"apps": [{"appId": "com.test.red", "binaries": [{"path": "red.aab"}]}, {"appId": "com.test.blue", "track": "alpha", "binaries": [{"path": "blue.aab"}]}],
+			"environments": {
+				"staging": {"track": "beta"},
+				"prod": {"authFile": "prod.json", "failFast": true, "apps": [{"appId": "com.test.red.prod", "binaries": [{"path": "red.aab"}]}]}
+			}
+		}`), 0644)
+		config, err := LoadBatchConfig(fs, "envs.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		staging, stagingErr := config.Environment("staging")
+		prod, prodErr := config.Environment("prod")
+		_, missingErr := config.Environment("qa")
+
+		// Assert
+		if stagingErr != nil || prodErr != nil {
+			t.Fatalf("want no errors, got: %v, %v", stagingErr, prodErr)
+		}
+		if staging.AuthFile != "dev.json" || staging.Apps[0].Track != TrackBeta || staging.Apps[1].Track != TrackAlpha {
+			t.Errorf("want staging apps without track on beta, got %+v", staging)
+		}
+		if config.Apps[0].Track != "" {
+			t.Errorf("want config left as is, got %+v", config.Apps[0])
+		}
+		if prod.AuthFile != "prod.json" || !prod.FailFast || len(prod.Apps) != 1 || prod.Apps[0].PackageName != "com.test.red.prod" {
+			t.Errorf("want prod credentials and apps, got %+v", prod)
+		}
+		if missingErr == nil || !strings.Contains(missingErr.Error(), "[prod staging]") {
+			t.Errorf("want error listing environments, got: %v", missingErr)
+		}
+	})
+
+	t.Run("should publish template to every package name with overrides", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "white-label.json", []byte(`{
+			"template": {"track": "beta", "binaries": [{"path": "build/{appId}.aab", "mapping": "build/{appId}.txt"}], "releaseNotes": {"en-US": "Bug fixes", "de-DE": "Fehlerbehebungen"}},
+			"appIds": ["com.test.red", "com.test.blue"],
+			"overrides": {"com.test.blue": {"rolloutFraction": 0.1, "releaseNotes": {"de-DE": "Neue Farben"}}}
+		}`), 0644)
+
+		// Act
+		config, err := LoadBatchConfig(fs, "white-label.json")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(config.Apps) != 2 {
+			t.Fatalf("want 2 apps, got %+v", config.Apps)
+		}
+		red, blue := config.Apps[0], config.Apps[1]
+		if red.PackageName != "com.test.red" || red.Track != TrackBeta || red.Binaries[0].Path != "build/com.test.red.aab" || red.Binaries[0].Mapping != "build/com.test.red.txt" {
+			t.Errorf("want red of template, got %+v", red)
+		}
+		if blue.Binaries[0].Path != "build/com.test.blue.aab" || blue.RolloutFraction != 0.1 {
+			t.Errorf("want blue of template with rollout override, got %+v", blue)
+		}
+		if blue.ReleaseNotes["en-US"] != "Bug fixes" || blue.ReleaseNotes["de-DE"] != "Neue Farben" || red.ReleaseNotes["de-DE"] != "Fehlerbehebungen" {
+			t.Errorf("want release notes merged by locale for blue only, got %v and %v", red.ReleaseNotes, blue.ReleaseNotes)
+		}
+	})
+
+	t.Run("should refuse overrides of package names not in appIds", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "typo.json", []byte(`{
+			"template": {"binaries": [{"path": "{appId}.aab"}]},
+			"appIds": ["com.test.red"],
+			"overrides": {"com.test.rde": {"track": "alpha"}}
+		}`), 0644)
+		afero.WriteFile(fs, "no-template.json", []byte(`{"appIds": ["com.test.red"]}`), 0644)
+
+		// Act
+		_, err := LoadBatchConfig(fs, "typo.json")
+		_, templateErr := LoadBatchConfig(fs, "no-template.json")
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "[com.test.rde]") {
+			t.Errorf("want error naming com.test.rde, got: %v", err)
+		}
+		if templateErr == nil || !strings.Contains(templateErr.Error(), "no template") {
+			t.Errorf("want error for appIds without template, got: %v", templateErr)
+		}
+	})
+}
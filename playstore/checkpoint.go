@@ -0,0 +1,235 @@
+package playstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// ResumeState upload progress recorded by WithResume, so interrupted upload continues in the same edit
+type ResumeState struct {
+	PackageName string        `json:"packageName"`
+	EditID      string        `json:"editId"`
+	Files       []ResumedFile `json:"files"`
+}
+
+// ResumedFile upload progress of a single binary
+type ResumedFile struct {
+	Path       string `json:"path"`
+	Sha256     string `json:"sha256"`
+	Size       int64  `json:"size"`
+	SessionURI string `json:"sessionUri,omitempty"`
+	Offset     int64  `json:"offset"`
+	// set once Play created binary from the upload
+	VersionCode  int64  `json:"versionCode,omitempty"`
+	RemoteSha256 string `json:"remoteSha256,omitempty"`
+}
+
+// WithResume records binary upload progress to given state file, so upload interrupted by a crash or
+// failure continues from the last confirmed chunk on the next run with the same file, instead of
+// starting over. Failed publish keeps its edit for that, state file is removed once publish succeeds.
+func WithResume(stateFile string) Option {
+	return func(p *publish) {
+		p.resumeFile = stateFile
+	}
+}
+
+// checkpoint resume state shared by parallel uploads, saved on every change
+type checkpoint struct {
+	mu    sync.Mutex
+	fs    afero.Fs
+	path  string
+	state ResumeState
+}
+
+// loadCheckpoint reads state file if there is one, state of another app is refused
+func loadCheckpoint(fs afero.Fs, path, packageName string) (*checkpoint, error) {
+	c := &checkpoint{fs: fs, path: path, state: ResumeState{PackageName: packageName}}
+	b, err := afero.ReadFile(fs, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading resume state '%s': %w", path, err)
+	}
+	if err := json.Unmarshal(b, &c.state); err != nil {
+		return nil, fmt.Errorf("resume state '%s' is not valid: %w", path, err)
+	}
+	if c.state.PackageName != packageName {
+		return nil, fmt.Errorf("resume state '%s' is for '%s', not '%s'", path, c.state.PackageName, packageName)
+	}
+	return c, nil
+}
+
+// update changes state and saves it
+func (c *checkpoint) update(change func(s *ResumeState)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	change(&c.state)
+	b, err := json.MarshalIndent(c.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(c.fs, c.path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed writing resume state '%s': %w", c.path, err)
+	}
+	return nil
+}
+
+// file returns recorded progress of a binary, zero if its content changed or it wasn't uploaded yet
+func (c *checkpoint) file(path, sha256 string, size int64) ResumedFile {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range c.state.Files {
+		if f.Path == path && f.Sha256 == sha256 && f.Size == size {
+			return f
+		}
+	}
+	return ResumedFile{Path: path, Sha256: sha256, Size: size}
+}
+
+// setFile records binary progress, replacing earlier record of the same path
+func (c *checkpoint) setFile(rf ResumedFile) error {
+	return c.update(func(s *ResumeState) {
+		for i, f := range s.Files {
+			if f.Path == rf.Path {
+				s.Files[i] = rf
+				return
+			}
+		}
+		s.Files = append(s.Files, rf)
+	})
+}
+
+// remove deletes state file of finished publish
+func (c *checkpoint) remove() error {
+	if err := c.fs.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed removing resume state '%s': %w", c.path, err)
+	}
+	return nil
+}
+
+// removeCheckpoint removes resume state of finished publish, nothing is left to resume
+func (p *publish) removeCheckpoint() {
+	if p.resume == nil {
+		return
+	}
+	if err := p.resume.remove(); err != nil {
+		p.Warnf("%v", err)
+	}
+}
+
+// openEdit reuses edit of resume state if it's still open, otherwise creates new one
+func (p *publish) openEdit(ctx context.Context, gs IGService) (*Edit, error) {
+	if p.resume == nil {
+		return createEdit(ctx, gs, p.packageName)
+	}
+	if id := p.resume.state.EditID; id != "" {
+		expiresAt, err := gs.getEdit(ctx, p.packageName, id)
+		if err == nil {
+			p.Infof("Resuming upload in edit '%s'.", id)
+			return newEdit(gs, p.packageName, id, expiresAt), nil
+		}
+		p.fallbackf("edit '%s' of resume state can't be used, uploading from scratch: %v", id, err)
+	}
+	edit, err := createEdit(ctx, gs, p.packageName)
+	if err != nil {
+		return nil, err
+	}
+	err = p.resume.update(func(s *ResumeState) {
+		s.EditID = edit.ID()
+		s.Files = nil
+	})
+	if err != nil {
+		edit.Delete(ctx)
+		return nil, err
+	}
+	return edit, nil
+}
+
+// resumableUpload uploads binary over resumable session recorded in resume state, continuing earlier
+// session from the offset Play confirmed
+func (p *publish) resumableUpload(ctx context.Context, edit *Edit, filePath string, isApk bool) (int64, Hashes, error) {
+	if err := edit.check("upload to", EditOpen); err != nil {
+		return -1, Hashes{}, err
+	}
+	editId := edit.ID()
+	us, ok := edit.gs.(IResumableUploadService)
+	if !ok {
+		return -1, Hashes{}, errResumableUnsupported
+	}
+	f, err := p.fs.Open(filePath)
+	if err != nil {
+		return -1, Hashes{}, err
+	}
+	defer f.Close()
+	local, err := fileHashes(f)
+	if err != nil {
+		return -1, Hashes{}, fmt.Errorf("failed calculating '%s' hashes: %w", filePath, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return -1, Hashes{}, err
+	}
+	size := info.Size()
+
+	rf := p.resume.file(filePath, local.Sha256, size)
+	if rf.VersionCode == 0 && rf.SessionURI != "" {
+		offset, res, err := us.resumableOffset(ctx, rf.SessionURI, size)
+		switch {
+		case err != nil:
+			p.fallbackf("upload session of '%s' can't be resumed, uploading it from scratch: %v", filePath, err)
+			rf.SessionURI = ""
+		case res != nil:
+			rf.VersionCode, rf.RemoteSha256, rf.Offset = res.VersionCode, res.Sha256, size
+		default:
+			rf.Offset = offset
+			p.fileInfof(filePath, "Resuming '%s' upload at %d of %d bytes.", filePath, offset, size)
+		}
+	}
+	if rf.VersionCode == 0 && rf.SessionURI == "" {
+		uri, err := us.startResumable(ctx, p.packageName, editId, isApk, size)
+		if err != nil {
+			return -1, Hashes{}, err
+		}
+		rf.SessionURI, rf.Offset = uri, 0
+		if err := p.resume.setFile(rf); err != nil {
+			return -1, Hashes{}, err
+		}
+	}
+	if rf.VersionCode == 0 {
+		var saveErr error
+		res, err := us.resumeUpload(ctx, rf.SessionURI, f, rf.Offset, size, func(offset int64) {
+			rf.Offset = offset
+			if err := p.resume.setFile(rf); err != nil && saveErr == nil {
+				saveErr = err
+			}
+			p.fileDebugf(filePath, "'%s' %d of %d bytes uploaded", filePath, offset, size)
+			p.reportProgress(filePath, offset, size)
+		})
+		if err != nil {
+			return -1, Hashes{}, err
+		}
+		if saveErr != nil {
+			return -1, Hashes{}, saveErr
+		}
+		rf.VersionCode, rf.RemoteSha256 = res.VersionCode, res.Sha256
+	}
+	if err := p.resume.setFile(rf); err != nil {
+		return -1, Hashes{}, err
+	}
+
+	if !strings.EqualFold(rf.RemoteSha256, local.Sha256) {
+		// corrupted upload is started over on the next run
+		p.resume.setFile(ResumedFile{Path: filePath, Sha256: local.Sha256, Size: size})
+		return -1, Hashes{}, &IntegrityError{Path: filePath, LocalSha256: local.Sha256, RemoteSha256: rf.RemoteSha256, Size: size, BytesSent: size, Attempts: 1}
+	}
+	edit.record(rf.VersionCode)
+	return rf.VersionCode, local, nil
+}
@@ -0,0 +1,134 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestResume(t *testing.T) {
+
+	t.Run("should continue interrupted upload in the same edit", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		content := createTestFile(t, fs, "test.aab", 20)
+		gs := &mockResumableGService{sessions: map[string][]byte{}, failAt: 8}
+		first, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithResume("state.json"))
+		second, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithResume("state.json"))
+
+		// Act
+		_, firstErr := first.UploadFiles(context.Background(), gs)
+		results, err := second.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ae *AbortError
+		if !errors.As(firstErr, &ae) || !ae.Resumable || gs.deleteEditCount != 0 {
+			t.Fatalf("want first run aborted keeping its edit, got: %v", firstErr)
+		}
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.createEditCount != 1 || gs.started != 1 || gs.resumedFrom != 8 {
+			t.Errorf("want single edit and session resumed at 8 bytes, got %d edits %d sessions resumed at %d", gs.createEditCount, gs.started, gs.resumedFrom)
+		}
+		if !bytes.Equal(gs.sessions["session-1"], content) {
+			t.Errorf("want whole file received once, got %d bytes", len(gs.sessions["session-1"]))
+		}
+		if len(results) != 1 || results[0].VersionCode != 7 || gs.commitEditCount != 1 {
+			t.Errorf("want appVersion 7 committed, got %+v", results)
+		}
+		if ok, _ := afero.Exists(fs, "state.json"); ok {
+			t.Error("want resume state removed after publish, got it kept")
+		}
+	})
+
+	t.Run("should start over when edit of resume state is gone", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 20)
+		afero.WriteFile(fs, "state.json", []byte(`{"packageName": "com.test.app", "editId": "old"}`), 0o644)
+		gs := &mockResumableGService{sessions: map[string][]byte{}}
+		gs.getEditError = errors.New("edit expired")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithResume("state.json"))
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.createEditCount != 1 {
+			t.Errorf("want new edit created, got %d", gs.createEditCount)
+		}
+	})
+
+	t.Run("should refuse resume state of another app", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 20)
+		afero.WriteFile(fs, "state.json", []byte(`{"packageName": "com.other.app", "editId": "1"}`), 0o644)
+		gs := &mockResumableGService{sessions: map[string][]byte{}}
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithResume("state.json"))
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.createEditCount != 0 {
+			t.Errorf("want no edit created, got %d", gs.createEditCount)
+		}
+	})
+}
+
+// mockResumableGService keeps resumable sessions in memory, sending 4 bytes per chunk
+type mockResumableGService struct {
+	mockGService
+	sessions map[string][]byte
+	started  int
+	// offset first upload fails at, 0 to never fail
+	failAt      int64
+	resumedFrom int64
+}
+
+func (gs *mockResumableGService) startResumable(ctx context.Context, packageName, editId string, isApk bool, size int64) (string, error) {
+	gs.started++
+	uri := fmt.Sprintf("session-%d", gs.started)
+	gs.sessions[uri] = nil
+	return uri, nil
+}
+
+func (gs *mockResumableGService) resumableOffset(ctx context.Context, sessionURI string, size int64) (int64, *resumableResult, error) {
+	return int64(len(gs.sessions[sessionURI])), nil, nil
+}
+
+func (gs *mockResumableGService) resumeUpload(ctx context.Context, sessionURI string, r io.ReadSeeker, offset, size int64, acked func(offset int64)) (*resumableResult, error) {
+	if offset > 0 {
+		gs.resumedFrom = offset
+	}
+	r.Seek(offset, io.SeekStart)
+	for offset < size {
+		chunk := make([]byte, 4)
+		n, _ := io.ReadFull(r, chunk)
+		gs.sessions[sessionURI] = append(gs.sessions[sessionURI], chunk[:n]...)
+		offset += int64(n)
+		acked(offset)
+		if gs.failAt > 0 && offset >= gs.failAt {
+			gs.failAt = 0
+			return nil, errors.New("connection reset")
+		}
+	}
+	sha, _ := fileSha256(bytes.NewReader(gs.sessions[sessionURI]))
+	return &resumableResult{VersionCode: 7, Sha256: sha}, nil
+}
@@ -0,0 +1,81 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Concurrency worker counts, 0 or 1 runs work one item at a time
+type Concurrency struct {
+	Uploads   int // binaries uploaded at once
+	Mappings  int // mapping files uploaded at once
+	Downloads int // remote artifacts downloaded at once
+}
+
+// WithConcurrency sets how many binaries, mappings and downloads are processed at once
+func WithConcurrency(c Concurrency) Option {
+	return func(p *publish) {
+		p.concurrency = c
+	}
+}
+
+// WithMaxParallelUploads uploads up to n binaries at once within the same edit, same as
+// WithConcurrency with only Uploads set. First failed upload cancels the rest and the edit is deleted.
+func WithMaxParallelUploads(n int) Option {
+	return func(p *publish) {
+		p.concurrency.Uploads = n
+	}
+}
+
+// runParallel calls fn for n items using up to given workers, cancelling context handed to calls
+// still running once one of them fails. Returns first error joined with errors of other calls that
+// failed on their own, calls failing only because of the cancellation are left out.
+func runParallel(ctx context.Context, workers, n int, fn func(ctx context.Context, i int) error) error {
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			if err := fn(ctx, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	items := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				if err := fn(ctx, i); err != nil {
+					mu.Lock()
+					if len(errs) == 0 || !errors.Is(err, context.Canceled) {
+						errs = append(errs, err)
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		items <- i
+	}
+	close(items)
+	wg.Wait()
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunParallel(t *testing.T) {
+
+	t.Run("should call every item once", func(t *testing.T) {
+		// Arrange
+		var calls [10]int32
+
+		// Act
+		err := runParallel(context.Background(), 3, len(calls), func(ctx context.Context, i int) error {
+			atomic.AddInt32(&calls[i], 1)
+			return nil
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		for i, c := range calls {
+			if c != 1 {
+				t.Errorf("want item %d called once, got %d", i, c)
+			}
+		}
+	})
+
+	t.Run("should stop sequential run on first error", func(t *testing.T) {
+		// Arrange
+		want := errors.New("failed")
+		calls := 0
+
+		// Act
+		err := runParallel(context.Background(), 1, 5, func(ctx context.Context, i int) error {
+			calls++
+			if i == 1 {
+				return want
+			}
+			return nil
+		})
+
+		// Assert
+		if !errors.Is(err, want) {
+			t.Errorf("want '%v', got '%v'", want, err)
+		}
+		if calls != 2 {
+			t.Errorf("want 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("should cancel running workers on error", func(t *testing.T) {
+		// Arrange
+		want := errors.New("failed")
+
+		// Act
+		err := runParallel(context.Background(), 2, 2, func(ctx context.Context, i int) error {
+			if i == 0 {
+				return want
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		// Assert
+		if !errors.Is(err, want) {
+			t.Errorf("want '%v', got '%v'", want, err)
+		}
+	})
+
+	t.Run("should report every failure except cancelled calls", func(t *testing.T) {
+		// Arrange
+		first, second := errors.New("first failed"), errors.New("second failed")
+		started := make(chan struct{})
+
+		// Act
+		err := runParallel(context.Background(), 3, 3, func(ctx context.Context, i int) error {
+			switch i {
+			case 0:
+				<-started
+				return first
+			case 1:
+				<-started
+				return second
+			}
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		// Assert
+		if !errors.Is(err, first) || !errors.Is(err, second) {
+			t.Errorf("want both failures reported, got '%v'", err)
+		}
+		if errors.Is(err, context.Canceled) {
+			t.Errorf("want cancelled call left out, got '%v'", err)
+		}
+	})
+}
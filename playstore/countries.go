@@ -0,0 +1,181 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+// CountryReport country targeting change of a track release, no countries for release available everywhere
+type CountryReport struct {
+	Track        string   `json:"track"`
+	VersionCodes []int64  `json:"versionCodes"`
+	From         []string `json:"from"`
+	To           []string `json:"to"`
+	// release also available in countries Play adds later
+	IncludeRestOfWorld bool `json:"includeRestOfWorld,omitempty"`
+}
+
+// WithCountries makes release available only in given countries, ISO 3166-1 alpha-2 codes e.g. "NL", "BE".
+// Coarse alternative to staged rollout by user fraction, countries are added later with ExpandCountries.
+func WithCountries(countries ...string) Option {
+	return func(p *publish) {
+		p.countries = countries
+	}
+}
+
+// WithRestOfWorld makes country targeted release also available in countries Play adds later, the
+// rest of the world of Play Console, requires WithCountries(...)
+func WithRestOfWorld() Option {
+	return func(p *publish) {
+		p.restOfWorld = true
+	}
+}
+
+// normalizeCountries upper cases country codes and drops duplicates, failing on anything but two letters
+func normalizeCountries(countries []string) ([]string, error) {
+	seen := make(map[string]bool, len(countries))
+	codes := make([]string, 0, len(countries))
+	for _, c := range countries {
+		code := strings.ToUpper(strings.TrimSpace(c))
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			return nil, fmt.Errorf("country '%s' is not a two letter ISO 3166-1 code e.g. 'NL'", c)
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// validateCountries normalizes country targeting of the release
+func (p *publish) validateCountries() error {
+	if len(p.countries) == 0 {
+		if p.restOfWorld {
+			return errors.New("rest of world applies to country targeted release only, countries to target are required")
+		}
+		return nil
+	}
+	codes, err := normalizeCountries(p.countries)
+	if err != nil {
+		return err
+	}
+	p.countries = codes
+	return nil
+}
+
+// countryTargeting of the release, nil for release available everywhere
+func (p *publish) countryTargeting() *androidpublisher.CountryTargeting {
+	if len(p.countries) == 0 {
+		return nil
+	}
+	return &androidpublisher.CountryTargeting{Countries: p.countries, IncludeRestOfWorld: p.restOfWorld}
+}
+
+/**
+ * ExpandCountries adds countries to the country targeted release on a track, without uploading anything
+ *
+ * countries - new country set, must include every country release already targets. None makes
+ *             release available in every country
+ * opts - WithRestOfWorld() adds countries Play adds later, kept if release has them already, AllowProduction() for production track, WithNotSentForReviewFallback() and WithManagedPublishing(...) apply to commit
+ */
+func ExpandCountries(ctx context.Context, gs IGService, packageName, trackName string, countries []string, opts ...Option) (*CountryReport, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+
+	p := &publish{}
+	for _, o := range opts {
+		o(p)
+	}
+	pr := p.profile
+	if pr == nil {
+		pr = &ProfileDefault
+	}
+
+	name, err := validatePackageName(packageName, p.anyPackageName)
+	if err != nil {
+		return nil, err
+	}
+	t, err := p.targetTrack(pr, trackName)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := normalizeCountries(countries)
+	if err != nil {
+		return nil, err
+	}
+	p.packageName = name
+	p.track = t
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkTrack(ctx, gs, edit, t); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+
+	track, err := gs.getTrack(ctx, name, edit, t)
+	if err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("failed reading '%s' track: %w", t, err)
+	}
+	var report *CountryReport
+	for _, r := range track.Releases {
+		if r.CountryTargeting == nil || r.Status == StatusDraft {
+			continue
+		}
+		if missing := missingCountries(r.CountryTargeting.Countries, codes); len(codes) > 0 && len(missing) > 0 {
+			gs.deleteEdit(ctx, name, edit)
+			return nil, fmt.Errorf("countries can only be added, '%s' track release also targets %v", t, missing)
+		}
+		report = &CountryReport{Track: t, VersionCodes: r.VersionCodes, From: r.CountryTargeting.Countries, To: codes}
+		restOfWorld := r.CountryTargeting.IncludeRestOfWorld || p.restOfWorld
+		r.CountryTargeting = nil
+		if len(codes) > 0 {
+			r.CountryTargeting = &androidpublisher.CountryTargeting{Countries: codes, IncludeRestOfWorld: restOfWorld}
+			report.IncludeRestOfWorld = restOfWorld
+		}
+		break
+	}
+	if report == nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("'%s' track has no country targeted release", t)
+	}
+
+	if err := gs.updateTrack(ctx, name, edit, track); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("failed updating track '%s': %w", t, err)
+	}
+	if err := gs.validateEdit(ctx, name, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if err := p.commit(ctx, gs, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	return report, nil
+}
+
+// missingCountries returns countries of current not in next
+func missingCountries(current, next []string) []string {
+	in := make(map[string]bool, len(next))
+	for _, c := range next {
+		in[c] = true
+	}
+	var missing []string
+	for _, c := range current {
+		if !in[strings.ToUpper(c)] {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
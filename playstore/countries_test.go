@@ -0,0 +1,170 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestWithCountries(t *testing.T) {
+
+	internal := func() *androidpublisher.Track {
+		return &androidpublisher.Track{
+			Track:    TrackInternal,
+			Releases: []*androidpublisher.TrackRelease{{Status: StatusCompleted, VersionCodes: []int64{11}}},
+		}
+	}
+
+	t.Run("should promote release targeting given countries", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackInternal: internal()}}
+
+		// Act
+		_, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", TrackInternal, TrackProduction,
+			AllowProduction(), WithReleaseStatus(StatusCompleted), WithCountries("nl", " BE", "NL"))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(gs.releases) != 1 || gs.releases[0].CountryTargeting == nil {
+			t.Fatalf("want 1 release with country targeting, got %+v", gs.releases)
+		}
+		if got := gs.releases[0].CountryTargeting.Countries; !reflect.DeepEqual(got, []string{"NL", "BE"}) {
+			t.Errorf("want [NL BE] countries, got %v", got)
+		}
+	})
+
+	t.Run("should promote release including rest of world", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackInternal: internal()}}
+
+		// Act
+		_, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", TrackInternal, TrackBeta, WithCountries("US"), WithRestOfWorld())
+		_, noCountriesErr := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", TrackInternal, TrackBeta, WithRestOfWorld())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(gs.releases) != 1 || !gs.releases[0].CountryTargeting.IncludeRestOfWorld {
+			t.Errorf("want 1 release including rest of world, got %+v", gs.releases)
+		}
+		if noCountriesErr == nil {
+			t.Error("want rest of world without countries refused")
+		}
+	})
+
+	t.Run("should refuse invalid country code", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackInternal: internal()}}
+
+		// Act
+		_, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", TrackInternal, TrackBeta, WithCountries("NLD"))
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.createEditCount != 0 {
+			t.Errorf("want no edit created, got %d", gs.createEditCount)
+		}
+	})
+}
+
+func TestExpandCountries(t *testing.T) {
+
+	production := func() *androidpublisher.Track {
+		return &androidpublisher.Track{
+			Track: TrackProduction,
+			Releases: []*androidpublisher.TrackRelease{
+				{Status: StatusCompleted, VersionCodes: []int64{11}, CountryTargeting: &androidpublisher.CountryTargeting{Countries: []string{"NL", "BE"}}},
+			},
+		}
+	}
+
+	t.Run("should add countries to targeted release", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackProduction: production()}}
+
+		// Act
+		r, err := ExpandCountries(context.Background(), gs, "com.test.app", TrackProduction, []string{"NL", "BE", "de"}, AllowProduction())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(r.From, []string{"NL", "BE"}) || !reflect.DeepEqual(r.To, []string{"NL", "BE", "DE"}) {
+			t.Errorf("want countries expanded from [NL BE] to [NL BE DE], got %+v", r)
+		}
+		if len(gs.updatedTracks) != 1 || len(gs.updatedTracks[0].Releases[0].CountryTargeting.Countries) != 3 || gs.commitEditCount != 1 {
+			t.Errorf("want committed track update with 3 countries, got %d updates %d commits", len(gs.updatedTracks), gs.commitEditCount)
+		}
+	})
+
+	t.Run("should keep rest of world of targeted release", func(t *testing.T) {
+		// Arrange
+		track := production()
+		track.Releases[0].CountryTargeting.IncludeRestOfWorld = true
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackProduction: track}}
+
+		// Act
+		r, err := ExpandCountries(context.Background(), gs, "com.test.app", TrackProduction, []string{"NL", "BE", "DE"}, AllowProduction())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !r.IncludeRestOfWorld || !gs.updatedTracks[0].Releases[0].CountryTargeting.IncludeRestOfWorld {
+			t.Errorf("want rest of world kept, got %+v", r)
+		}
+	})
+
+	t.Run("should release everywhere without countries", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackProduction: production()}}
+
+		// Act
+		_, err := ExpandCountries(context.Background(), gs, "com.test.app", TrackProduction, nil, AllowProduction())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(gs.updatedTracks) != 1 || gs.updatedTracks[0].Releases[0].CountryTargeting != nil {
+			t.Error("want country targeting removed")
+		}
+	})
+
+	t.Run("should refuse removing countries", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackProduction: production()}}
+
+		// Act
+		_, err := ExpandCountries(context.Background(), gs, "com.test.app", TrackProduction, []string{"NL", "DE"}, AllowProduction())
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if len(gs.updatedTracks) != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted without changes, got %d updates", len(gs.updatedTracks))
+		}
+	})
+
+	t.Run("should fail without country targeted release", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: {Track: TrackBeta, Releases: []*androidpublisher.TrackRelease{{Status: StatusCompleted}}}}}
+
+		// Act
+		_, err := ExpandCountries(context.Background(), gs, "com.test.app", TrackBeta, []string{"NL"})
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package playstore
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/option"
+)
+
+const (
+	// service account JSON encoded as base64, for CI secrets that can't be files
+	CredentialsEnv = "PSTORE_CREDENTIALS"
+	// path to service account JSON file, as used by Google client libraries
+	GoogleCredentialsEnv = "GOOGLE_APPLICATION_CREDENTIALS"
+)
+
+// NewGEditsServiceFromJSON creates service authorized with service account JSON, without it touching disk
+func NewGEditsServiceFromJSON(ctx context.Context, credentials []byte, opts ...ServiceOption) (IGService, error) {
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("service account credentials must not be empty")
+	}
+	return newGEditsService(ctx, option.WithCredentialsJSON(credentials), opts...)
+}
+
+// NewGEditsServiceWithADC creates service authorized with Application Default Credentials e.g. GKE workload
+// identity, gcloud auth application-default login or GOOGLE_APPLICATION_CREDENTIALS, without a key file
+func NewGEditsServiceWithADC(ctx context.Context, opts ...ServiceOption) (IGService, error) {
+	return newGEditsService(ctx, adcCredentials(), opts...)
+}
+
+// adcCredentials scopes Application Default Credentials to Play Developer API
+func adcCredentials() option.ClientOption {
+	return option.WithScopes(androidpublisher.AndroidpublisherScope)
+}
+
+// CredentialsFromEnv reads service account JSON from PSTORE_CREDENTIALS as base64,
+// or from file GOOGLE_APPLICATION_CREDENTIALS points to if former is not set
+func CredentialsFromEnv() ([]byte, error) {
+	if v := strings.TrimSpace(os.Getenv(CredentialsEnv)); v != "" {
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not valid base64: %w", CredentialsEnv, err)
+		}
+		return b, nil
+	}
+	if path := os.Getenv(GoogleCredentialsEnv); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading '%s' credentials file '%s': %w", GoogleCredentialsEnv, path, err)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("no credentials found, set '%s' or '%s'", CredentialsEnv, GoogleCredentialsEnv)
+}
@@ -0,0 +1,76 @@
+package playstore
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCredentialsFromEnv(t *testing.T) {
+
+	t.Run("should decode base64 credentials", func(t *testing.T) {
+		// Arrange
+		t.Setenv(CredentialsEnv, base64.StdEncoding.EncodeToString([]byte(`{"type":"service_account"}`)))
+		t.Setenv(GoogleCredentialsEnv, "")
+
+		// Act
+		b, err := CredentialsFromEnv()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if string(b) != `{"type":"service_account"}` {
+			t.Errorf("want decoded credentials, got '%s'", b)
+		}
+	})
+
+	t.Run("should read file from google application credentials", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "auth.json")
+		if err := os.WriteFile(path, []byte(`{}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(CredentialsEnv, "")
+		t.Setenv(GoogleCredentialsEnv, path)
+
+		// Act
+		b, err := CredentialsFromEnv()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if string(b) != `{}` {
+			t.Errorf("want file content, got '%s'", b)
+		}
+	})
+
+	t.Run("should fail on invalid base64", func(t *testing.T) {
+		// Arrange
+		t.Setenv(CredentialsEnv, "not base64!")
+
+		// Act
+		_, err := CredentialsFromEnv()
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should fail without credentials", func(t *testing.T) {
+		// Arrange
+		t.Setenv(CredentialsEnv, "")
+		t.Setenv(GoogleCredentialsEnv, "")
+
+		// Act
+		_, err := CredentialsFromEnv()
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
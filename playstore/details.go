@@ -0,0 +1,203 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// AppDetails app contact details and default language of its listings
+type AppDetails struct {
+	DefaultLanguage string `json:"defaultLanguage"`
+	ContactEmail    string `json:"contactEmail,omitempty"`
+	ContactPhone    string `json:"contactPhone,omitempty"`
+	ContactWebsite  string `json:"contactWebsite,omitempty"`
+}
+
+// DetailsReport app details UpdateAppDetails set, or would set on dry run
+type DetailsReport struct {
+	Details AppDetails `json:"details"`
+	// json names of fields that changed, empty when details were as given already
+	Changed []string `json:"changed"`
+	DryRun  bool     `json:"dryRun,omitempty"`
+}
+
+// Merge returns details with non empty fields of changes replacing the ones of d
+func (d AppDetails) Merge(changes AppDetails) AppDetails {
+	set := func(v *string, change string) {
+		if change = strings.TrimSpace(change); change != "" {
+			*v = change
+		}
+	}
+	set(&d.DefaultLanguage, changes.DefaultLanguage)
+	set(&d.ContactEmail, changes.ContactEmail)
+	set(&d.ContactPhone, changes.ContactPhone)
+	set(&d.ContactWebsite, changes.ContactWebsite)
+	return d
+}
+
+// changed json names of fields differing from other
+func (d AppDetails) changed(other AppDetails) []string {
+	changed := []string{}
+	for _, f := range []struct {
+		name     string
+		old, new string
+	}{
+		{"defaultLanguage", other.DefaultLanguage, d.DefaultLanguage},
+		{"contactEmail", other.ContactEmail, d.ContactEmail},
+		{"contactPhone", other.ContactPhone, d.ContactPhone},
+		{"contactWebsite", other.ContactWebsite, d.ContactWebsite},
+	} {
+		if f.old != f.new {
+			changed = append(changed, f.name)
+		}
+	}
+	return changed
+}
+
+// validate checks details before Play does, which only says request is invalid
+func (d AppDetails) validate() error {
+	if strings.TrimSpace(d.DefaultLanguage) == "" {
+		return errors.New("app default language must not be empty")
+	}
+	if d.ContactEmail != "" {
+		if a, err := mail.ParseAddress(d.ContactEmail); err != nil || a.Address != d.ContactEmail {
+			return fmt.Errorf("contact email '%s' is not an email address", d.ContactEmail)
+		}
+	}
+	if d.ContactWebsite != "" {
+		if u, err := url.Parse(d.ContactWebsite); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("contact website '%s' is not an http or https URL", d.ContactWebsite)
+		}
+	}
+	return nil
+}
+
+// WithAccessCheck reads app details before uploading anything, so missing permission to the app fails
+// publish with clear error before any upload bytes are sent
+func WithAccessCheck() Option {
+	return func(p *publish) {
+		p.accessCheck = true
+	}
+}
+
+// GetAppDetails reads app details within a temporary edit, deleted once read
+func GetAppDetails(ctx context.Context, gs IGService, packageName string) (*AppDetails, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	edit, err := createEdit(ctx, gs, name)
+	if err != nil {
+		return nil, accessError(name, err)
+	}
+	defer edit.Abort(ctx)
+	details, err := gs.getDetails(ctx, name, edit.ID())
+	if err != nil {
+		return nil, accessError(name, err)
+	}
+	return details, nil
+}
+
+/**
+ * UpdateAppDetails sets default language and contact details of the app, keeping details left empty as they are
+ *
+ * changes - details to set, e.g. AppDetails{ContactEmail: "dev@sample.com"}
+ * opts - DryRun() only reports changes, WithNotSentForReviewFallback() and WithManagedPublishing(...) apply to commit
+ */
+func UpdateAppDetails(ctx context.Context, gs IGService, packageName string, changes AppDetails, opts ...Option) (*DetailsReport, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+
+	p := &publish{}
+	for _, o := range opts {
+		o(p)
+	}
+	name, err := validatePackageName(packageName, p.anyPackageName)
+	if err != nil {
+		return nil, err
+	}
+	p.packageName = name
+	if changes == (AppDetails{}) {
+		return nil, errors.New("at least one app detail to set is required")
+	}
+
+	edit, err := createEdit(ctx, gs, name)
+	if err != nil {
+		return nil, accessError(name, err)
+	}
+	current, err := edit.Details(ctx)
+	if err != nil {
+		edit.Abort(ctx)
+		return nil, fmt.Errorf("failed reading app details: %w", err)
+	}
+	details := current.Merge(changes)
+	if err := details.validate(); err != nil {
+		edit.Abort(ctx)
+		return nil, err
+	}
+	report := &DetailsReport{Details: details, Changed: details.changed(*current), DryRun: p.dryRun}
+	if p.dryRun || len(report.Changed) == 0 {
+		edit.Abort(ctx)
+		return report, nil
+	}
+
+	if err := edit.UpdateDetails(ctx, details); err != nil {
+		edit.Abort(ctx)
+		return nil, fmt.Errorf("failed updating app details: %w", err)
+	}
+	if err := edit.Validate(ctx); err != nil {
+		edit.Abort(ctx)
+		return nil, err
+	}
+	if err := p.commitWith(func(changesNotSentForReview bool) error { return edit.Commit(ctx, changesNotSentForReview) }); err != nil {
+		edit.Abort(ctx)
+		return nil, err
+	}
+	return report, nil
+}
+
+// checkAccess confirms app details can be read within the edit
+func (p *publish) checkAccess(ctx context.Context, gs IGService, edit string) error {
+	details, err := gs.getDetails(ctx, p.packageName, edit)
+	if err != nil {
+		return accessError(p.packageName, err)
+	}
+	p.Debugf("'%s' is accessible, default language '%s'", p.packageName, details.DefaultLanguage)
+	return nil
+}
+
+// appAccessError Play refused access to the app, matching ErrNoAppAccess
+type appAccessError struct {
+	packageName string
+	err         error
+}
+
+func (e *appAccessError) Error() string {
+	return fmt.Sprintf("no access to app '%s', check it exists and credentials are granted access to it in Play Console: %v", e.packageName, e.err)
+}
+
+func (e *appAccessError) Unwrap() error {
+	return e.err
+}
+
+func (e *appAccessError) Is(target error) bool {
+	return target == ErrNoAppAccess
+}
+
+// accessError wraps error Play responds with for apps credentials can't access, others are returned as they are
+func accessError(packageName string, err error) error {
+	switch StatusCode(err) {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return &appAccessError{packageName: packageName, err: err}
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+func TestAppDetails(t *testing.T) {
+
+	t.Run("should read app details in temporary edit", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{details: &AppDetails{DefaultLanguage: "en-US", ContactEmail: "dev@sample.com"}}
+
+		// Act
+		details, err := GetAppDetails(context.Background(), gs, "com.test.app")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if details.DefaultLanguage != "en-US" || details.ContactEmail != "dev@sample.com" {
+			t.Errorf("want details read, got %+v", details)
+		}
+		if gs.createEditCount != 1 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit created and deleted, got %d created %d deleted", gs.createEditCount, gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should report missing access before uploading with access check", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.aab", 10)
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("app.aab")}, false, false, WithAccessCheck())
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{createEditErrors: map[string]error{
+			"com.test.app": &APIError{Op: "create edit", StatusCode: http.StatusForbidden, Err: &googleapi.Error{Code: http.StatusForbidden}},
+		}}
+
+		// Act
+		_, err = publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if !errors.Is(err, ErrNoAppAccess) || StatusCode(err) != http.StatusForbidden {
+			t.Errorf("want '%v' with 403, got: %v", ErrNoAppAccess, err)
+		}
+		if gs.uploadBundleCallCount != 0 {
+			t.Errorf("want nothing uploaded, got %d uploads", gs.uploadBundleCallCount)
+		}
+	})
+
+	t.Run("should leave other errors as they are", func(t *testing.T) {
+		// Arrange
+		ge := &googleapi.Error{Code: http.StatusInternalServerError}
+
+		// Act
+		err := accessError("com.test.app", ge)
+
+		// Assert
+		if err != ge {
+			t.Errorf("want '%v' as is, got: %v", ge, err)
+		}
+	})
+
+	t.Run("should update given details keeping the rest", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{details: &AppDetails{DefaultLanguage: "en-US", ContactEmail: "old@sample.com", ContactPhone: "+100"}}
+
+		// Act
+		report, err := UpdateAppDetails(context.Background(), gs, "com.test.app", AppDetails{ContactEmail: "dev@sample.com", ContactWebsite: "https://sample.com"})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		want := AppDetails{DefaultLanguage: "en-US", ContactEmail: "dev@sample.com", ContactPhone: "+100", ContactWebsite: "https://sample.com"}
+		if gs.updatedDetails == nil || *gs.updatedDetails != want {
+			t.Errorf("want '%+v' set, got %+v", want, gs.updatedDetails)
+		}
+		if !reflect.DeepEqual(report.Changed, []string{"contactEmail", "contactWebsite"}) || gs.commitEditCount != 1 {
+			t.Errorf("want email and website changed and committed, got %v with %d commits", report.Changed, gs.commitEditCount)
+		}
+	})
+
+	t.Run("should not update details on dry run or without changes", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{details: &AppDetails{DefaultLanguage: "en-US", ContactEmail: "dev@sample.com"}}
+
+		// Act
+		dry, dryErr := UpdateAppDetails(context.Background(), gs, "com.test.app", AppDetails{DefaultLanguage: "de-DE"}, DryRun())
+		same, sameErr := UpdateAppDetails(context.Background(), gs, "com.test.app", AppDetails{ContactEmail: "dev@sample.com"})
+
+		// Assert
+		if dryErr != nil || sameErr != nil {
+			t.Fatalf("want no errors, got: %v, %v", dryErr, sameErr)
+		}
+		if !reflect.DeepEqual(dry.Changed, []string{"defaultLanguage"}) || len(same.Changed) != 0 {
+			t.Errorf("want default language changed on dry run only, got %v and %v", dry.Changed, same.Changed)
+		}
+		if gs.updatedDetails != nil || gs.commitEditCount != 0 || gs.deleteEditCount != 2 {
+			t.Errorf("want edits deleted without updates, got %+v, %d commits, %d deletes", gs.updatedDetails, gs.commitEditCount, gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should refuse invalid details before updating", func(t *testing.T) {
+		for _, changes := range []AppDetails{{ContactEmail: "dev at sample.com"}, {ContactWebsite: "sample.com"}, {}} {
+			// Arrange
+			gs := &mockGService{details: &AppDetails{DefaultLanguage: "en-US"}}
+
+			// Act
+			_, err := UpdateAppDetails(context.Background(), gs, "com.test.app", changes)
+
+			// Assert
+			if err == nil || gs.updatedDetails != nil {
+				t.Errorf("want '%+v' refused, got: %v", changes, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package playstore
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// FileDigest digests of a file uploaded along with binary, which Play doesn't report back to verify against
+type FileDigest struct {
+	Path   string `json:"path"`
+	Type   string `json:"type"` // mapping type e.g. 'proguard' or expansion file type e.g. 'main'
+	Sha256 string `json:"sha256"`
+	Sha1   string `json:"sha1"`
+}
+
+// WithFileDigests records digests of mappings and expansion files uploaded along with binaries, logging them
+// and adding them to UploadResult, so every uploaded file can be verified afterwards. Digests are of content
+// sent to Play, for gzipped mappings that's decompressed mappings.
+func WithFileDigests() Option {
+	return func(p *publish) {
+		p.fileDigests = true
+	}
+}
+
+// fileDigests digests by file path, recorded by parallel uploads
+type fileDigests struct {
+	mu     sync.Mutex
+	byPath map[string]FileDigest
+}
+
+// recordDigest computes digests of file content about to be uploaded, when file digests are enabled
+func (p *publish) recordDigest(path, fileType string, r io.ReadSeeker) error {
+	if p.digests == nil {
+		return nil
+	}
+	h, err := fileHashes(r)
+	if err != nil {
+		return fmt.Errorf("failed calculating '%s' hashes: %w", path, err)
+	}
+	p.Infof("'%s' %s file sha256 %s sha1 %s", path, fileType, h.Sha256, h.Sha1)
+	p.digests.mu.Lock()
+	defer p.digests.mu.Unlock()
+	p.digests.byPath[path] = FileDigest{Path: path, Type: fileType, Sha256: h.Sha256, Sha1: h.Sha1}
+	return nil
+}
+
+// attachDigests adds digests recorded for binary mappings and expansion files to its upload result
+func (p *publish) attachDigests(r *UploadResult, b binary) {
+	if p.digests == nil {
+		return
+	}
+	p.digests.mu.Lock()
+	defer p.digests.mu.Unlock()
+	for _, path := range []string{b.mappingPath, b.mainObb, b.patchObb} {
+		if d, ok := p.digests.byPath[path]; ok && path != "" {
+			r.Files = append(r.Files, d)
+		}
+	}
+}
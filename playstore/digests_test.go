@@ -0,0 +1,68 @@
+package playstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestFileDigests(t *testing.T) {
+
+	t.Run("should record digests of mappings and expansion files", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.apk", 10)
+		mapping := createTestFile(t, fs, "mapping.txt", 20)
+		obb := createTestFile(t, fs, "main.obb", 30)
+		bin := Expanded(BinaryWithMapping("app.apk", "mapping.txt"), "main.obb", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, true, false, WithFileDigests())
+		gs := &mockGService{AppVersionCode: 7}
+
+		// Act
+		results, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(results) != 1 || len(results[0].Files) != 2 {
+			t.Fatalf("want mapping and expansion file digests, got %+v", results)
+		}
+		m, o := results[0].Files[0], results[0].Files[1]
+		if m.Path != "mapping.txt" || m.Type != DeobfuscationFileProguard || m.Sha256 != sha256Hex(mapping) {
+			t.Errorf("want mapping.txt proguard sha256 %s, got %+v", sha256Hex(mapping), m)
+		}
+		if o.Path != "main.obb" || o.Type != ExpansionFileMain || o.Sha256 != sha256Hex(obb) {
+			t.Errorf("want main.obb main sha256 %s, got %+v", sha256Hex(obb), o)
+		}
+	})
+
+	t.Run("should not record digests by default", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createMockBinary(t, fs, "app.aab", "mapping.txt")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{BinaryWithMapping("app.aab", "mapping.txt")}, false, false)
+		gs := &mockGService{AppVersionCode: 7}
+
+		// Act
+		results, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(results) != 1 || results[0].Files != nil {
+			t.Errorf("want no file digests, got %+v", results)
+		}
+	})
+}
+
+func sha256Hex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd)
+
+package playstore
+
+import "github.com/spf13/afero"
+
+// freeDiskSpace free space is not checked on this platform
+func freeDiskSpace(fs afero.Fs, dir string) (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd
+
+package playstore
+
+import (
+	"syscall"
+
+	"github.com/spf13/afero"
+)
+
+// freeDiskSpace returns bytes available to unprivileged users on file system holding dir, false when unknown
+func freeDiskSpace(fs afero.Fs, dir string) (int64, bool) {
+	if _, ok := fs.(*afero.OsFs); !ok {
+		return 0, false
+	}
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, false
+	}
+	return int64(st.Bavail) * int64(st.Bsize), true
+}
@@ -0,0 +1,257 @@
+package playstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// remote artifact schemes Publish downloads before validating them
+var remoteSchemes = []string{"https://", "http://", "gs://"}
+
+// diskFree reports space available for downloads, replaced in tests
+var diskFree = freeDiskSpace
+
+// WithKeepDownloads keeps local copies of remote binaries and mappings in a directory logged on
+// download, instead of removing them with publish workspace once upload is done
+func WithKeepDownloads() Option {
+	return func(p *publish) {
+		p.keepDownloads = true
+	}
+}
+
+// WithDownloadClient sets client remote artifacts are fetched with e.g. one authorized for private
+// GCS buckets, http.DefaultClient if not set
+func WithDownloadClient(c *http.Client) Option {
+	return func(p *publish) {
+		p.downloadClient = c
+	}
+}
+
+// isRemote reports whether artifact path is an URL to download
+func isRemote(path string) bool {
+	for _, s := range remoteSchemes {
+		if strings.HasPrefix(strings.ToLower(path), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteArtifact URL to download artifact from and its expected sha256 given as '#sha256=<hex>'
+type remoteArtifact struct {
+	source string
+	url    string
+	name   string
+	sha256 string
+}
+
+// parseRemote reads artifact URL, gs://bucket/object is fetched over Cloud Storage XML API
+func parseRemote(source string) (remoteArtifact, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return remoteArtifact{}, fmt.Errorf("remote artifact '%s' is not a valid URL: %w", source, err)
+	}
+	a := remoteArtifact{source: source}
+	if u.Fragment != "" {
+		digest, ok := strings.CutPrefix(u.Fragment, "sha256=")
+		if !ok {
+			return remoteArtifact{}, fmt.Errorf("remote artifact '%s' fragment must be 'sha256=<hex>'", source)
+		}
+		a.sha256 = strings.ToLower(digest)
+		u.Fragment = ""
+	}
+	if u.Scheme == "gs" {
+		u = &url.URL{Scheme: "https", Host: "storage.googleapis.com", Path: "/" + u.Host + u.Path}
+	}
+	a.url = u.String()
+	a.name = path.Base(u.Path)
+	return a, nil
+}
+
+// downloadRemote fetches remote binaries, mappings and expansion files, returning files with their
+// local copies instead. Free disk space is checked against sizes servers report before anything is fetched.
+func (p *publish) downloadRemote(ctx context.Context, files []binary) ([]binary, error) {
+	sources := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, f := range files {
+		for _, s := range []string{f.filePath, f.mappingPath, f.mainObb, f.patchObb} {
+			// streamed binaries are fetched while uploading
+			if f.stream != nil && s == f.filePath {
+				continue
+			}
+			if isRemote(s) && !seen[s] {
+				seen[s] = true
+				sources = append(sources, s)
+			}
+		}
+	}
+	if len(sources) == 0 {
+		return files, nil
+	}
+	artifacts := make([]remoteArtifact, len(sources))
+	for i, s := range sources {
+		a, err := parseRemote(s)
+		if err != nil {
+			return nil, err
+		}
+		if a.sha256 == "" {
+			p.Debugf("no sha256 given for '%s', only its size is verified", s)
+		}
+		artifacts[i] = a
+	}
+
+	dir, err := p.downloadDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkDiskSpace(ctx, dir, artifacts); err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	local := make(map[string]string, len(artifacts))
+	err = runParallel(ctx, p.concurrency.Downloads, len(artifacts), func(ctx context.Context, i int) error {
+		target := filepath.Join(dir, fmt.Sprintf("%d-%s", i, artifacts[i].name))
+		if err := p.download(ctx, artifacts[i], target); err != nil {
+			return err
+		}
+		mu.Lock()
+		local[artifacts[i].source] = target
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]binary, len(files))
+	for i, f := range files {
+		for _, s := range []*string{&f.filePath, &f.mappingPath, &f.mainObb, &f.patchObb} {
+			if f.stream != nil && s == &f.filePath {
+				continue
+			}
+			if l, ok := local[*s]; ok {
+				*s = l
+			}
+		}
+		resolved[i] = f
+	}
+	return resolved, nil
+}
+
+// downloadDir returns directory downloads go to, kept one with WithKeepDownloads
+func (p *publish) downloadDir() (string, error) {
+	if p.keepDownloads {
+		dir, err := afero.TempDir(p.fs, "", workspacePrefix+"downloads-")
+		if err != nil {
+			return "", fmt.Errorf("failed creating downloads directory: %w", err)
+		}
+		p.Infof("Downloads are kept in '%s'.", dir)
+		return dir, nil
+	}
+	if p.ws == nil {
+		p.ws = newWorkspace(p.fs)
+	}
+	dir, err := p.ws.path("downloads")
+	if err != nil {
+		return "", err
+	}
+	if err := p.fs.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed creating downloads directory: %w", err)
+	}
+	return dir, nil
+}
+
+// httpClient returns client remote artifacts are fetched with
+func (p *publish) httpClient() *http.Client {
+	if p.downloadClient != nil {
+		return p.downloadClient
+	}
+	return http.DefaultClient
+}
+
+// checkDiskSpace fails when artifacts servers report sizes for don't fit into free space of dir.
+// Check is skipped when free space can't be told e.g. for in memory file systems.
+func (p *publish) checkDiskSpace(ctx context.Context, dir string, artifacts []remoteArtifact) error {
+	free, ok := diskFree(p.fs, dir)
+	if !ok {
+		p.Debugf("free disk space of '%s' unknown, skipping download space check", dir)
+		return nil
+	}
+	var total int64
+	for _, a := range artifacts {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, a.url, nil)
+		if err != nil {
+			return err
+		}
+		res, err := p.httpClient().Do(req)
+		if err != nil {
+			return fmt.Errorf("failed checking '%s' size: %w", a.source, err)
+		}
+		res.Body.Close()
+		if res.StatusCode >= 300 {
+			return fmt.Errorf("failed checking '%s' size: %s", a.source, res.Status)
+		}
+		if res.ContentLength > 0 {
+			total += res.ContentLength
+		}
+	}
+	p.Debugf("downloads need %d bytes, %d bytes free in '%s'", total, free, dir)
+	if total > free {
+		return fmt.Errorf("remote artifacts need %d bytes, but only %d bytes are free in '%s'", total, free, dir)
+	}
+	return nil
+}
+
+// download fetches artifact to target, verifying its size and sha256 if given. Partial file is removed on failure.
+func (p *publish) download(ctx context.Context, a remoteArtifact, target string) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := p.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed downloading '%s': %w", a.source, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("failed downloading '%s': %s", a.source, res.Status)
+	}
+
+	f, err := p.fs.Create(target)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+		if err != nil {
+			p.fs.Remove(target)
+		}
+	}()
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), res.Body)
+	if err != nil {
+		return fmt.Errorf("failed downloading '%s': %w", a.source, err)
+	}
+	if res.ContentLength >= 0 && n != res.ContentLength {
+		return fmt.Errorf("download of '%s' incomplete, got %d of %d bytes", a.source, n, res.ContentLength)
+	}
+	if digest := hex.EncodeToString(h.Sum(nil)); a.sha256 != "" && digest != a.sha256 {
+		return fmt.Errorf("downloaded '%s' sha256 '%s' doesn't match expected '%s'", a.source, digest, a.sha256)
+	}
+	p.Infof("Downloaded '%s' (%d bytes).", a.source, n)
+	return nil
+}
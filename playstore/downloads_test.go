@@ -0,0 +1,151 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestDownloads(t *testing.T) {
+
+	// serves test binary at /app.aab
+	newServer := func(t *testing.T, content []byte) *httptest.Server {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/app.aab" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			http.ServeContent(w, r, "app.aab", time.Time{}, bytes.NewReader(content))
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("should upload downloaded binary and remove it afterwards", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		content := createTestFile(t, afero.NewMemMapFs(), "app.aab", 20)
+		srv := newServer(t, content)
+		gs := &mockGService{}
+
+		// Act
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary(srv.URL + "/app.aab")}, false, false)
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		local := publish.files[0].filePath
+		results, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if sum := sha256.Sum256(content); len(results) != 1 || results[0].Sha256 != hex.EncodeToString(sum[:]) {
+			t.Errorf("want downloaded binary uploaded, got %+v", results)
+		}
+		if ok, _ := afero.Exists(fs, local); ok {
+			t.Errorf("want download '%s' removed after upload", local)
+		}
+	})
+
+	t.Run("should keep downloads when asked", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		content := createTestFile(t, afero.NewMemMapFs(), "app.aab", 20)
+		srv := newServer(t, content)
+		gs := &mockGService{}
+
+		// Act
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary(srv.URL + "/app.aab")}, false, false, WithKeepDownloads())
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		local := publish.files[0].filePath
+		_, err = publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if ok, _ := afero.Exists(fs, local); !ok {
+			t.Errorf("want download '%s' kept", local)
+		}
+	})
+
+	t.Run("should refuse download not matching sha256", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		srv := newServer(t, createTestFile(t, afero.NewMemMapFs(), "app.aab", 20))
+		sum := sha256.Sum256([]byte("other"))
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary(srv.URL + "/app.aab#sha256=" + hex.EncodeToString(sum[:]))}, false, false)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "doesn't match") {
+			t.Errorf("want sha256 mismatch error, got: %v", err)
+		}
+		if dirs, _ := afero.Glob(fs, filepath.Join(os.TempDir(), workspacePrefix+"*")); len(dirs) != 0 {
+			t.Errorf("want downloads removed, got %v", dirs)
+		}
+	})
+
+	t.Run("should fail before download without enough disk space", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		srv := newServer(t, createTestFile(t, afero.NewMemMapFs(), "app.aab", 20))
+		diskFree = func(afero.Fs, string) (int64, bool) { return 10, true }
+		t.Cleanup(func() { diskFree = freeDiskSpace })
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary(srv.URL + "/app.aab")}, false, false)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "only 10 bytes are free") {
+			t.Errorf("want disk space error, got: %v", err)
+		}
+	})
+
+	t.Run("should fail on missing remote artifact", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		srv := newServer(t, nil)
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary(srv.URL + "/missing.aab")}, false, false)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "404") {
+			t.Errorf("want download error, got: %v", err)
+		}
+	})
+
+	t.Run("should read gs URL over Cloud Storage", func(t *testing.T) {
+		// Act
+		a, err := parseRemote("gs://builds/app/1.0/app.aab#sha256=ABC")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if a.url != "https://storage.googleapis.com/builds/app/1.0/app.aab" || a.name != "app.aab" || a.sha256 != "abc" {
+			t.Errorf("want Cloud Storage URL with sha256, got %+v", a)
+		}
+	})
+}
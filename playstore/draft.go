@@ -0,0 +1,59 @@
+package playstore
+
+import (
+	"fmt"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+// DraftRelease draft release left on a track for completing in Play Console
+type DraftRelease struct {
+	PackageName  string            `json:"packageName"`
+	Track        string            `json:"track"`
+	VersionCodes []int64           `json:"versionCodes"`
+	ReleaseNotes map[string]string `json:"releaseNotes,omitempty"`
+}
+
+// DraftOnly uploads binaries and creates a draft release only, nothing is rolled out to users.
+// Can't be combined with rollout fraction or release status other than draft, see Draft for its details.
+func DraftOnly() Option {
+	return func(p *publish) {
+		p.draftOnly = true
+	}
+}
+
+// validateDraftOnly checks no other release options would roll the release out
+func (p *publish) validateDraftOnly() error {
+	if !p.draftOnly {
+		return nil
+	}
+	if p.rolloutFraction > 0 {
+		return fmt.Errorf("draft only release can't have rollout fraction")
+	}
+	if p.releaseStatus != "" && p.releaseStatus != StatusDraft {
+		return fmt.Errorf("draft only release can't have '%s' status", p.releaseStatus)
+	}
+	return nil
+}
+
+/**
+ * Draft returns draft release committed by UploadFiles, nil if release created wasn't a draft or upload failed
+ */
+func (p *publish) Draft() *DraftRelease {
+	r := p.created
+	if r == nil || r.Status != StatusDraft {
+		return nil
+	}
+	return newDraftRelease(p.packageName, p.track, r)
+}
+
+func newDraftRelease(packageName, track string, r *androidpublisher.TrackRelease) *DraftRelease {
+	d := &DraftRelease{PackageName: packageName, Track: track, VersionCodes: r.VersionCodes}
+	if len(r.ReleaseNotes) > 0 {
+		d.ReleaseNotes = make(map[string]string, len(r.ReleaseNotes))
+		for _, n := range r.ReleaseNotes {
+			d.ReleaseNotes[n.Language] = n.Text
+		}
+	}
+	return d
+}
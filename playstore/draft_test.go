@@ -0,0 +1,71 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestDraftOnly(t *testing.T) {
+
+	t.Run("should return committed draft details", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, DraftOnly(), ReleaseNotes(map[string]string{"en-US": "Fixes"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{AppVersionCode: 5}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+		d := publish.Draft()
+
+		// Assert
+		want := &DraftRelease{PackageName: "com.test.app", Track: TrackBeta, VersionCodes: []int64{5}, ReleaseNotes: map[string]string{"en-US": "Fixes"}}
+		if !reflect.DeepEqual(d, want) {
+			t.Errorf("want %+v, got %+v", want, d)
+		}
+	})
+
+	t.Run("should reject options rolling release out", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+
+		for _, o := range []Option{WithRolloutFraction(0.1), WithReleaseStatus(StatusCompleted)} {
+			// Act
+			_, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, DraftOnly(), o)
+
+			// Assert
+			if err == nil {
+				t.Error("want error, got nil")
+			}
+		}
+	})
+
+	t.Run("should not report draft for staged rollout", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, WithRolloutFraction(0.1))
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), &mockGService{}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if d := publish.Draft(); d != nil {
+			t.Errorf("want no draft, got %+v", d)
+		}
+	})
+}
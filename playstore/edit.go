@@ -0,0 +1,342 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+// EditState stage of Edit lifecycle
+type EditState int
+
+const (
+	EditOpen      EditState = iota // binaries can be uploaded
+	EditTracked                    // track releases set, more can be set but binaries can't be uploaded
+	EditValidated                  // Play accepted the changes, edit can be committed
+	EditCommitted                  // changes are published, edit is gone
+	EditDeleted                    // changes are discarded, edit is gone
+)
+
+func (s EditState) String() string {
+	switch s {
+	case EditOpen:
+		return "open"
+	case EditTracked:
+		return "tracked"
+	case EditValidated:
+		return "validated"
+	case EditCommitted:
+		return "committed"
+	case EditDeleted:
+		return "deleted"
+	}
+	return fmt.Sprintf("EditState(%d)", int(s))
+}
+
+// EditStateError operation is not allowed in the state edit is in
+type EditStateError struct {
+	Op    string
+	State EditState
+}
+
+func (e *EditStateError) Error() string {
+	return fmt.Sprintf("can't %s edit in '%s' state", e.Op, e.State)
+}
+
+/**
+ * Edit Play edit with its lifecycle enforced: binaries are uploaded while it's open, then track releases
+ * are set, edit is validated and committed. Delete discards it at any point before commit.
+ * Publish builds upon it, use it directly for flows Publish doesn't cover, e.g. uploading binaries,
+ * updating listings and country availability in one transaction:
+ *
+ *	edit, err := OpenEdit(ctx, gs, packageName)
+ *	defer edit.Abort(ctx)
+ *	edit.UploadBundle(...), edit.UpdateListing(...), edit.SetTrack(...), edit.SetCountries(...)
+ *	edit.Validate(ctx), edit.Commit(ctx, false)
+ */
+type Edit struct {
+	mu          sync.Mutex
+	gs          IGService
+	packageName string
+	id          string
+	expiresAt   time.Time
+	state       EditState
+	versions    []int64
+}
+
+// OpenEdit begins new edit of the app
+func OpenEdit(ctx context.Context, gs IGService, packageName string) (*Edit, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	return createEdit(ctx, gs, name)
+}
+
+// createEdit creates new edit of the app with package name validated already
+func createEdit(ctx context.Context, gs IGService, packageName string) (*Edit, error) {
+	id, expiresAt, err := gs.createEdit(ctx, packageName)
+	if err != nil {
+		return nil, err
+	}
+	return newEdit(gs, packageName, id, expiresAt), nil
+}
+
+// newEdit wraps edit already open on Play
+func newEdit(gs IGService, packageName, id string, expiresAt time.Time) *Edit {
+	return &Edit{gs: gs, packageName: packageName, id: id, expiresAt: expiresAt}
+}
+
+// ID returns Play edit ID
+func (e *Edit) ID() string {
+	return e.id
+}
+
+// ExpiresAt returns when Play discards the edit, zero if unknown
+func (e *Edit) ExpiresAt() time.Time {
+	return e.expiresAt
+}
+
+// State returns lifecycle stage edit is in
+func (e *Edit) State() EditState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.state
+}
+
+// VersionCodes returns appVersions of binaries uploaded through the edit, in upload order
+func (e *Edit) VersionCodes() []int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]int64(nil), e.versions...)
+}
+
+// UploadBundle uploads app bundle, returning its appVersion and sha256 Play calculated
+func (e *Edit) UploadBundle(ctx context.Context, r io.Reader) (int64, string, error) {
+	return e.upload("upload bundle to", func() (int64, string, error) {
+		return e.gs.uploadBundle(ctx, r, e.packageName, e.id)
+	})
+}
+
+// UploadApk uploads apk, returning its appVersion and sha256 Play calculated
+func (e *Edit) UploadApk(ctx context.Context, r io.Reader) (int64, string, error) {
+	return e.upload("upload apk to", func() (int64, string, error) {
+		return e.gs.uploadApk(ctx, r, e.packageName, e.id)
+	})
+}
+
+// upload runs upload call while edit is open, recording appVersion it created. Uploads may run in parallel.
+func (e *Edit) upload(op string, call func() (int64, string, error)) (int64, string, error) {
+	if err := e.check(op, EditOpen); err != nil {
+		return -1, "", err
+	}
+	v, sha256, err := call()
+	if err != nil {
+		return -1, "", err
+	}
+	e.record(v)
+	return v, sha256, nil
+}
+
+// record keeps appVersion uploaded through the edit
+func (e *Edit) record(version int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.versions = append(e.versions, version)
+}
+
+// SetTrack creates release on a track, once uploads are done
+func (e *Edit) SetTrack(ctx context.Context, track string, release *androidpublisher.TrackRelease) error {
+	if err := e.check("set track of", EditOpen, EditTracked); err != nil {
+		return err
+	}
+	if err := e.gs.createRelease(ctx, e.packageName, e.id, track, release); err != nil {
+		return err
+	}
+	return e.move(EditTracked)
+}
+
+// Validate asks Play to check edit changes, which commit needs
+func (e *Edit) Validate(ctx context.Context) error {
+	if err := e.check("validate", EditOpen, EditTracked); err != nil {
+		return err
+	}
+	if err := e.gs.validateEdit(ctx, e.packageName, e.id); err != nil {
+		return err
+	}
+	return e.move(EditValidated)
+}
+
+// Commit publishes validated changes, changesNotSentForReview commits them without sending for review.
+// Failed commit may be retried.
+func (e *Edit) Commit(ctx context.Context, changesNotSentForReview bool) error {
+	if err := e.check("commit", EditValidated); err != nil {
+		return err
+	}
+	if err := e.gs.commitEdit(ctx, e.packageName, e.id, changesNotSentForReview); err != nil {
+		return err
+	}
+	return e.move(EditCommitted)
+}
+
+// Delete discards edit with every change made in it
+func (e *Edit) Delete(ctx context.Context) error {
+	if err := e.check("delete", EditOpen, EditTracked, EditValidated); err != nil {
+		return err
+	}
+	if err := e.gs.deleteEdit(ctx, e.packageName, e.id); err != nil {
+		return err
+	}
+	return e.move(EditDeleted)
+}
+
+// Abort deletes edit unless it's committed or deleted already, so it can be deferred right after OpenEdit
+func (e *Edit) Abort(ctx context.Context) error {
+	if state := e.State(); state == EditCommitted || state == EditDeleted {
+		return nil
+	}
+	return e.Delete(ctx)
+}
+
+// UploadMapping uploads proguard mapping of appVersion uploaded through the edit or released before
+func (e *Edit) UploadMapping(ctx context.Context, r io.Reader, versionCode int64) error {
+	if err := e.check("upload mapping to", EditOpen, EditTracked); err != nil {
+		return err
+	}
+	return e.gs.uploadProguardMapping(ctx, r, e.packageName, e.id, versionCode)
+}
+
+// UploadNativeSymbols uploads zip of native debug symbols of appVersion uploaded through the edit or released before
+func (e *Edit) UploadNativeSymbols(ctx context.Context, r io.Reader, versionCode int64) error {
+	if err := e.check("upload native symbols to", EditOpen, EditTracked); err != nil {
+		return err
+	}
+	return e.gs.uploadNativeSymbols(ctx, r, e.packageName, e.id, versionCode)
+}
+
+// Details reads app details as they are in the edit
+func (e *Edit) Details(ctx context.Context) (*AppDetails, error) {
+	if err := e.check("read app details of", EditOpen, EditTracked); err != nil {
+		return nil, err
+	}
+	return e.gs.getDetails(ctx, e.packageName, e.id)
+}
+
+// UpdateDetails replaces app details, fields left empty are cleared, see AppDetails.Merge to keep them
+func (e *Edit) UpdateDetails(ctx context.Context, details AppDetails) error {
+	if err := e.check("update app details of", EditOpen, EditTracked); err != nil {
+		return err
+	}
+	if err := details.validate(); err != nil {
+		return err
+	}
+	return e.gs.updateDetails(ctx, e.packageName, e.id, details)
+}
+
+// UpdateListing creates or replaces store listing of a locale
+func (e *Edit) UpdateListing(ctx context.Context, listing Listing) error {
+	if err := e.check("update listing of", EditOpen, EditTracked); err != nil {
+		return err
+	}
+	if err := listing.validate(); err != nil {
+		return err
+	}
+	return e.gs.updateListing(ctx, e.packageName, e.id, listing)
+}
+
+// DeleteListing deletes store listing of a locale
+func (e *Edit) DeleteListing(ctx context.Context, locale string) error {
+	if err := e.check("delete listing of", EditOpen, EditTracked); err != nil {
+		return err
+	}
+	return e.gs.deleteListing(ctx, e.packageName, e.id, locale)
+}
+
+// SetCountries makes releases of a track Play allows changing available only in given countries, ISO 3166-1
+// alpha-2 codes e.g. "NL". No countries makes them available everywhere. Those are in progress, halted and
+// draft releases, and releases of binaries uploaded through the edit, completed releases already serving are
+// left as they are.
+func (e *Edit) SetCountries(ctx context.Context, trackName string, countries []string) error {
+	if err := e.check("set countries of", EditOpen, EditTracked); err != nil {
+		return err
+	}
+	codes, err := normalizeCountries(countries)
+	if err != nil {
+		return err
+	}
+	track, err := e.gs.getTrack(ctx, e.packageName, e.id, trackName)
+	if err != nil {
+		return fmt.Errorf("failed reading '%s' track: %w", trackName, err)
+	}
+	uploaded := e.VersionCodes()
+	changed := false
+	for _, r := range track.Releases {
+		if !isEditableRelease(r) && !hasAnyVersion(r.VersionCodes, uploaded) {
+			continue
+		}
+		r.CountryTargeting = nil
+		if len(codes) > 0 {
+			r.CountryTargeting = &androidpublisher.CountryTargeting{Countries: codes}
+		}
+		changed = true
+	}
+	if !changed {
+		return fmt.Errorf("'%s' track has no release to set countries of", trackName)
+	}
+	if err := e.gs.updateTrack(ctx, e.packageName, e.id, track); err != nil {
+		return fmt.Errorf("failed updating track '%s': %w", trackName, err)
+	}
+	return e.move(EditTracked)
+}
+
+// isEditableRelease reports if Play accepts changes to release of its status
+func isEditableRelease(r *androidpublisher.TrackRelease) bool {
+	switch r.Status {
+	case StatusInProgress, StatusHalted, StatusDraft:
+		return true
+	}
+	return false
+}
+
+// hasAnyVersion reports if any of versions is in versionCodes
+func hasAnyVersion(versionCodes, versions []int64) bool {
+	for _, v := range versionCodes {
+		for _, u := range versions {
+			if v == u {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// check fails unless edit is in one of allowed states
+func (e *Edit) check(op string, allowed ...EditState) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range allowed {
+		if e.state == s {
+			return nil
+		}
+	}
+	return &EditStateError{Op: op, State: e.state}
+}
+
+// move changes state after successful call, unless edit was deleted or committed meanwhile
+func (e *Edit) move(to EditState) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.state == EditCommitted || e.state == EditDeleted {
+		return &EditStateError{Op: "change", State: e.state}
+	}
+	e.state = to
+	return nil
+}
@@ -0,0 +1,211 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestEdit(t *testing.T) {
+
+	t.Run("should go through upload, track, validate and commit", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{AppVersionCode: 42}
+		ctx := context.Background()
+		edit, err := OpenEdit(ctx, gs, " com.test.app ")
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+
+		// Act
+		v, _, err := edit.UploadBundle(ctx, bytes.NewReader([]byte("bundle")))
+		if err == nil {
+			err = edit.SetTrack(ctx, TrackInternal, &androidpublisher.TrackRelease{VersionCodes: []int64{v}, Status: StatusCompleted})
+		}
+		if err == nil {
+			err = edit.Validate(ctx)
+		}
+		if err == nil {
+			err = edit.Commit(ctx, false)
+		}
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if edit.State() != EditCommitted {
+			t.Errorf("want '%s' state, got '%s'", EditCommitted, edit.State())
+		}
+		if gs.packageName != "com.test.app" || gs.releaseTrack != TrackInternal || gs.commitEditCount != 1 {
+			t.Errorf("want com.test.app released on internal and committed, got %s on '%s' committed %d times", gs.packageName, gs.releaseTrack, gs.commitEditCount)
+		}
+		if codes := edit.VersionCodes(); len(codes) != 1 || codes[0] != 42 {
+			t.Errorf("want appVersion 42 recorded, got %v", codes)
+		}
+	})
+
+	t.Run("should refuse commit before validation", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+		edit, _ := OpenEdit(context.Background(), gs, "com.test.app")
+
+		// Act
+		err := edit.Commit(context.Background(), false)
+
+		// Assert
+		var se *EditStateError
+		if !errors.As(err, &se) || se.State != EditOpen {
+			t.Errorf("want commit refused in open state, got: %v", err)
+		}
+		if gs.commitEditCount != 0 {
+			t.Errorf("want no commit calls, got %d", gs.commitEditCount)
+		}
+	})
+
+	t.Run("should refuse upload once track is set", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+		ctx := context.Background()
+		edit, _ := OpenEdit(ctx, gs, "com.test.app")
+		edit.SetTrack(ctx, TrackInternal, &androidpublisher.TrackRelease{})
+
+		// Act
+		_, _, err := edit.UploadApk(ctx, bytes.NewReader([]byte("apk")))
+
+		// Assert
+		var se *EditStateError
+		if !errors.As(err, &se) || se.State != EditTracked {
+			t.Errorf("want upload refused in tracked state, got: %v", err)
+		}
+		if gs.uploadApkCallCount != 0 {
+			t.Errorf("want no upload calls, got %d", gs.uploadApkCallCount)
+		}
+	})
+
+	t.Run("should refuse delete of committed edit", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+		ctx := context.Background()
+		edit, _ := OpenEdit(ctx, gs, "com.test.app")
+		edit.Validate(ctx)
+		edit.Commit(ctx, false)
+
+		// Act
+		err := edit.Delete(ctx)
+
+		// Assert
+		var se *EditStateError
+		if !errors.As(err, &se) || se.State != EditCommitted {
+			t.Errorf("want delete refused in committed state, got: %v", err)
+		}
+		if gs.deleteEditCount != 0 {
+			t.Errorf("want no delete calls, got %d", gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should stay validated when commit fails", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{commitErrors: []error{errors.New("commit failed")}}
+		ctx := context.Background()
+		edit, _ := OpenEdit(ctx, gs, "com.test.app")
+		edit.Validate(ctx)
+
+		// Act
+		first := edit.Commit(ctx, false)
+		second := edit.Commit(ctx, false)
+
+		// Assert
+		if first == nil || second != nil {
+			t.Errorf("want first commit failed and retry succeeded, got: %v, %v", first, second)
+		}
+		if edit.State() != EditCommitted {
+			t.Errorf("want '%s' state, got '%s'", EditCommitted, edit.State())
+		}
+	})
+
+	t.Run("should combine upload, listing and countries in one edit", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{AppVersionCode: 7, tracks: map[string]*androidpublisher.Track{
+			TrackBeta: {Track: TrackBeta, Releases: []*androidpublisher.TrackRelease{{Status: StatusCompleted, VersionCodes: []int64{7}}}},
+		}}
+		ctx := context.Background()
+		edit, _ := OpenEdit(ctx, gs, "com.test.app")
+		defer edit.Abort(ctx)
+
+		// Act
+		_, _, err := edit.UploadBundle(ctx, bytes.NewReader([]byte("bundle")))
+		if err == nil {
+			err = edit.UpdateListing(ctx, Listing{Locale: "en-US", Title: "Test"})
+		}
+		if err == nil {
+			err = edit.SetCountries(ctx, TrackBeta, []string{"nl", "BE"})
+		}
+		if err == nil {
+			err = edit.Validate(ctx)
+		}
+		if err == nil {
+			err = edit.Commit(ctx, false)
+		}
+		abortErr := edit.Abort(ctx)
+
+		// Assert
+		if err != nil || abortErr != nil {
+			t.Fatalf("want no error, got: %v, %v", err, abortErr)
+		}
+		if gs.createEditCount != 1 || gs.commitEditCount != 1 || gs.deleteEditCount != 0 {
+			t.Errorf("want single committed edit, got %d created %d committed %d deleted", gs.createEditCount, gs.commitEditCount, gs.deleteEditCount)
+		}
+		if len(gs.updatedListings) != 1 || len(gs.updatedTracks) != 1 {
+			t.Fatalf("want listing and track updated, got %v and %v", gs.updatedListings, gs.updatedTracks)
+		}
+		if c := gs.updatedTracks[0].Releases[0].CountryTargeting; c == nil || len(c.Countries) != 2 || c.Countries[0] != "NL" {
+			t.Errorf("want release targeting NL and BE, got %+v", c)
+		}
+	})
+
+	t.Run("should leave countries of completed releases as they are", func(t *testing.T) {
+		// Arrange
+		serving := &androidpublisher.CountryTargeting{Countries: []string{"DE"}}
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{
+			TrackBeta: {Track: TrackBeta, Releases: []*androidpublisher.TrackRelease{
+				{Status: StatusCompleted, VersionCodes: []int64{6}, CountryTargeting: serving},
+				{Status: StatusInProgress, VersionCodes: []int64{7}, UserFraction: 0.1},
+			}},
+		}}
+		ctx := context.Background()
+		edit, _ := OpenEdit(ctx, gs, "com.test.app")
+		defer edit.Abort(ctx)
+
+		// Act
+		err := edit.SetCountries(ctx, TrackBeta, []string{"NL"})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		releases := gs.updatedTracks[0].Releases
+		if releases[0].CountryTargeting != serving || len(serving.Countries) != 1 || serving.Countries[0] != "DE" {
+			t.Errorf("want completed release left targeting DE, got %+v", releases[0].CountryTargeting)
+		}
+		if c := releases[1].CountryTargeting; c == nil || len(c.Countries) != 1 || c.Countries[0] != "NL" {
+			t.Errorf("want in progress release targeting NL, got %+v", c)
+		}
+	})
+
+	t.Run("should delete edit aborted before commit", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+		edit, _ := OpenEdit(context.Background(), gs, "com.test.app")
+
+		// Act
+		err := edit.Abort(context.Background())
+
+		// Assert
+		if err != nil || edit.State() != EditDeleted || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted, got '%s' deleted %d times: %v", edit.State(), gs.deleteEditCount, err)
+		}
+	})
+}
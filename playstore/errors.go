@@ -0,0 +1,97 @@
+package playstore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+var (
+	// ErrAuthFileMissing authentication file to create Google API service with doesn't exist
+	ErrAuthFileMissing = errors.New("authentication file does not exist")
+	// ErrInvalidPackageName package name doesn't follow Android application ID rules, see WithAnyPackageName
+	ErrInvalidPackageName = errors.New("invalid package name")
+	// ErrUnsupportedTrack track isn't one of Play tracks binaries can be released to
+	ErrUnsupportedTrack = errors.New("track not supported")
+	// ErrIntegrityMismatch Play has different content of uploaded binary than the local file, see IntegrityError
+	ErrIntegrityMismatch = errors.New("uploaded binary doesn't match local file")
+	// ErrNoAppAccess app doesn't exist or credentials have no permission to manage it
+	ErrNoAppAccess = errors.New("no access to app")
+	// ErrEditExpired edit expired or was deleted while still in use, see EditExpiredError
+	ErrEditExpired = errors.New("edit expired")
+	// ErrCommitUncertain commit failed in a way that leaves open whether Play published the edit, see CommitUncertainError
+	ErrCommitUncertain = errors.New("commit outcome unknown")
+	// ErrEditConflict Play refused edit change conflicting with another edit of the app, e.g. one committed
+	// after the edit was opened. Opening new edit and starting over usually helps.
+	ErrEditConflict = errors.New("edit conflicts with another edit of the app")
+	// ErrQuotaExceeded Play refused call for exceeding Google API quota, per minute quota is waited for when
+	// retrying, see WithRetryPolicy and WithRateLimit
+	ErrQuotaExceeded = errors.New("google api quota exceeded")
+)
+
+// APIError Google API call failure with HTTP status code Play responded with, googleapi.Error it wraps
+// is available through errors.As
+type APIError struct {
+	Op         string // call that failed, e.g. 'commit edit'
+	StatusCode int
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("failed to %s: %v", e.Op, e.Err)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrEditConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrQuotaExceeded:
+		return isQuotaErr(e.Err)
+	}
+	return false
+}
+
+// apiError wraps googleapi error of a call with APIError, other errors and ones wrapped already are
+// returned as they are
+func apiError(op string, err error) error {
+	var ge *googleapi.Error
+	var ae *APIError
+	if err == nil || !errors.As(err, &ge) || errors.As(err, &ae) {
+		return err
+	}
+	return &APIError{Op: op, StatusCode: ge.Code, Err: err}
+}
+
+// StatusCode returns HTTP status code of Google API call failure, 0 for errors not coming from Google APIs
+func StatusCode(err error) int {
+	var ge *googleapi.Error
+	if !errors.As(err, &ge) {
+		return 0
+	}
+	return ge.Code
+}
+
+// sentinelError error with message of its own, matching sentinel with errors.Is
+type sentinelError struct {
+	sentinel error
+	msg      string
+}
+
+func (e *sentinelError) Error() string {
+	return e.msg
+}
+
+func (e *sentinelError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+// errorOf formats error matching sentinel
+func errorOf(sentinel error, format string, a ...any) error {
+	return &sentinelError{sentinel: sentinel, msg: fmt.Sprintf(format, a...)}
+}
@@ -0,0 +1,105 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+func TestErrors(t *testing.T) {
+
+	t.Run("should report missing authentication file", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false)
+
+		// Assert
+		if !errors.Is(err, ErrAuthFileMissing) {
+			t.Errorf("want '%v', got: %v", ErrAuthFileMissing, err)
+		}
+	})
+
+	t.Run("should report unsupported track", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", "nightly", "auth.json", []binary{Binary("test.aab")}, false, false)
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedTrack) {
+			t.Errorf("want '%v', got: %v", ErrUnsupportedTrack, err)
+		}
+	})
+
+	t.Run("should match integrity error with sentinel", func(t *testing.T) {
+		// Arrange
+		var err error = &IntegrityError{Path: "test.aab", LocalSha256: "a", RemoteSha256: "b"}
+
+		// Act
+		matches := errors.Is(err, ErrIntegrityMismatch)
+
+		// Assert
+		if !matches {
+			t.Errorf("want '%v' matched, got: %v", ErrIntegrityMismatch, err)
+		}
+	})
+
+	t.Run("should wrap api errors with status code", func(t *testing.T) {
+		// Arrange
+		ge := &googleapi.Error{Code: http.StatusConflict, Message: "edit is outdated"}
+		us := &uploadService{media: &stubMedia{err: ge}}
+
+		// Act
+		_, _, err := us.uploadBundle(context.Background(), strings.NewReader("aab"), "com.test.app", "1")
+
+		// Assert
+		var ae *APIError
+		if !errors.As(err, &ae) || ae.Op != "upload bundle" || ae.StatusCode != http.StatusConflict {
+			t.Fatalf("want 409 upload bundle error, got: %v", err)
+		}
+		if !errors.Is(err, ErrEditConflict) || !errors.Is(err, ge) {
+			t.Errorf("want edit conflict wrapping '%v', got: %v", ge, err)
+		}
+		if StatusCode(err) != http.StatusConflict {
+			t.Errorf("want status code 409, got %d", StatusCode(err))
+		}
+	})
+
+	t.Run("should not wrap other errors", func(t *testing.T) {
+		// Arrange
+		plain := errors.New("connection reset")
+
+		// Act
+		err := apiError("commit edit", plain)
+
+		// Assert
+		if err != plain || StatusCode(err) != 0 {
+			t.Errorf("want '%v' as is, got: %v", plain, err)
+		}
+		if apiError("commit edit", nil) != nil {
+			t.Error("want nil for no error")
+		}
+	})
+
+	t.Run("should not wrap api errors twice", func(t *testing.T) {
+		// Arrange
+		wrapped := apiError("commit edit", &googleapi.Error{Code: http.StatusBadRequest})
+
+		// Act
+		err := apiError("commit edit", wrapped)
+
+		// Assert
+		if err != wrapped {
+			t.Errorf("want '%v' as is, got: %v", wrapped, err)
+		}
+	})
+}
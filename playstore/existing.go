@@ -0,0 +1,170 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrVersionAlreadyExists binary is on Play already, which refuses uploading the same appVersion twice
+var ErrVersionAlreadyExists = errors.New("version already exists on Play")
+
+// VersionExistsError local binary matching, by sha256, one Play has under given appVersion
+type VersionExistsError struct {
+	Path        string
+	VersionCode int64
+	Track       string // target track with release of the appVersion, empty if it isn't released there
+	// Play has another binary under the local binary versionCode
+	Different bool
+}
+
+func (e *VersionExistsError) Error() string {
+	if e.Different {
+		return fmt.Sprintf("binary '%s' versionCode %d is taken by another binary on Play", e.Path, e.VersionCode)
+	}
+	if e.Track != "" {
+		return fmt.Sprintf("binary '%s' is on Play already as appVersion %d, released on '%s' track", e.Path, e.VersionCode, e.Track)
+	}
+	return fmt.Sprintf("binary '%s' is on Play already as appVersion %d", e.Path, e.VersionCode)
+}
+
+func (e *VersionExistsError) Is(target error) bool {
+	return target == ErrVersionAlreadyExists
+}
+
+// WithSkipExisting releases binaries Play has already under their existing appVersion, without uploading
+// them or their mappings and expansion files, instead of failing with ErrVersionAlreadyExists. Publish of
+// binaries all live on target track already changes nothing, so re-running it after partial failure is safe.
+func WithSkipExisting() Option {
+	return func(p *publish) {
+		p.skipExisting = true
+	}
+}
+
+// existingVersions matches binaries against bundles and apks Play has before anything is uploaded, returning
+// appVersions of the ones found by file position. Binaries resume state recorded as uploaded to the edit are
+// left out, binaries whose manifest versionCode Play has under other content fail even when skipping.
+// Check is skipped with a warning when Play can't list its binaries.
+func (p *publish) existingVersions(ctx context.Context, gs IGService, edit string, files []binary) (map[int]UploadResult, error) {
+	hashes, err := gs.versionHashes(ctx, p.packageName, edit)
+	if err != nil {
+		p.skippedFeature("duplicate check of binaries on Play", err)
+		return nil, nil
+	}
+	bySha := make(map[string]int64, len(hashes))
+	for v, h := range hashes {
+		bySha[strings.ToLower(h.Sha256)] = v
+	}
+
+	existing := make(map[int]UploadResult)
+	for i, f := range files {
+		if f.stream != nil {
+			p.skippedCheckf(false, "binary '%s' is streamed, skipping its duplicate check", f.filePath)
+			continue
+		}
+		local, size, err := p.localHashes(f.filePath)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := bySha[local.Sha256]
+		if m := p.manifests[f.filePath]; !ok && m != nil {
+			if _, taken := hashes[m.VersionCode]; taken {
+				return nil, &VersionExistsError{Path: f.filePath, VersionCode: m.VersionCode, Different: true}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if p.resume != nil && p.resume.file(f.filePath, local.Sha256, size).VersionCode == v {
+			continue
+		}
+		if !p.skipExisting {
+			return nil, &VersionExistsError{Path: f.filePath, VersionCode: v, Track: p.releasedOn(ctx, gs, edit, v)}
+		}
+		p.Infof("Skipping '%s' upload, Play has it as appVersion %d already.", f.filePath, v)
+		existing[i] = UploadResult{Path: f.filePath, VersionCode: v, Sha256: local.Sha256, Sha1: local.Sha1, Skipped: true}
+	}
+	return existing, nil
+}
+
+// allLive reports every binary being skipped as Play has it released on target track already, by a
+// completed or in progress release having all of their appVersions, so there is nothing to publish
+func (p *publish) allLive(ctx context.Context, gs IGService, edit string, files []binary, existing map[int]UploadResult) bool {
+	if !p.skipExisting || len(files) == 0 || len(existing) != len(files) {
+		return false
+	}
+	track, err := gs.getTrack(ctx, p.packageName, edit, p.track)
+	if err != nil {
+		p.skippedFeature(fmt.Sprintf("live check of binaries on '%s' track", p.track), err)
+		return false
+	}
+	for _, r := range track.Releases {
+		if r.Status != StatusCompleted && r.Status != StatusInProgress {
+			continue
+		}
+		released := make(map[int64]bool, len(r.VersionCodes))
+		for _, v := range r.VersionCodes {
+			released[v] = true
+		}
+		live := true
+		for _, e := range existing {
+			live = live && released[e.VersionCode]
+		}
+		if live {
+			return true
+		}
+	}
+	return false
+}
+
+// finishLive deletes edit of a publish with every binary live on target track already, returning their results
+func (p *publish) finishLive(ctx context.Context, edit *Edit, files []binary, existing map[int]UploadResult) []UploadResult {
+	results := make([]UploadResult, 0, len(files))
+	versions := make([]int64, 0, len(files))
+	for i := range files {
+		results = append(results, existing[i])
+		versions = append(versions, existing[i].VersionCode)
+	}
+	if err := edit.Delete(ctx); err != nil {
+		p.Warnf("failed deleting edit '%s': %v", edit.ID(), err)
+	}
+	p.removeCheckpoint()
+	p.Infof("appVersions %v are live on '%s' track already, nothing to publish.", versions, p.track)
+	return results
+}
+
+// releasedOn returns target track if any of its releases has the appVersion, empty otherwise
+func (p *publish) releasedOn(ctx context.Context, gs IGService, edit string, version int64) string {
+	track, err := gs.getTrack(ctx, p.packageName, edit, p.track)
+	if err != nil {
+		p.skippedCheckf(false, "failed reading '%s' track: %v", p.track, err)
+		return ""
+	}
+	for _, r := range track.Releases {
+		for _, v := range r.VersionCodes {
+			if v == version {
+				return p.track
+			}
+		}
+	}
+	return ""
+}
+
+// localHashes reads hashes and size of a local file
+func (p *publish) localHashes(filePath string) (Hashes, int64, error) {
+	f, err := p.fs.Open(filePath)
+	if err != nil {
+		return Hashes{}, 0, err
+	}
+	defer f.Close()
+	h, err := fileHashes(f)
+	if err != nil {
+		return Hashes{}, 0, fmt.Errorf("failed calculating '%s' hashes: %w", filePath, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return Hashes{}, 0, err
+	}
+	return h, info.Size(), nil
+}
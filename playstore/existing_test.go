@@ -0,0 +1,127 @@
+package playstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestExistingVersions(t *testing.T) {
+
+	setup := func(t *testing.T, opts ...Option) (*publish, *mockGService) {
+		t.Helper()
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		content := createTestFile(t, fs, "test.aab", 20)
+		createTestFile(t, fs, "new.aab", 20)
+		sum := sha256.Sum256(content)
+		gs := &mockGService{
+			AppVersionCode: 12,
+			hashes:         map[int64]Hashes{11: {Sha256: hex.EncodeToString(sum[:])}},
+			tracks:         map[string]*androidpublisher.Track{TrackInternal: {Track: TrackInternal, Releases: []*androidpublisher.TrackRelease{{VersionCodes: []int64{11}}}}},
+		}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab"), Binary("new.aab")}, false, false, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return publish, gs
+	}
+
+	t.Run("should fail before upload when Play has binary already", func(t *testing.T) {
+		// Arrange
+		publish, gs := setup(t)
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ve *VersionExistsError
+		if !errors.Is(err, ErrVersionAlreadyExists) || !errors.As(err, &ve) {
+			t.Fatalf("want '%v', got: %v", ErrVersionAlreadyExists, err)
+		}
+		if ve.VersionCode != 11 || ve.Track != TrackInternal || ve.Path != "test.aab" {
+			t.Errorf("want appVersion 11 of 'test.aab' released on '%s', got %+v", TrackInternal, ve)
+		}
+		if gs.uploadBundleCallCount != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want no uploads and edit deleted, got %d uploads %d deletes", gs.uploadBundleCallCount, gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should release existing appVersion when skipping", func(t *testing.T) {
+		// Arrange
+		publish, gs := setup(t, WithSkipExisting())
+
+		// Act
+		results, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.uploadBundleCallCount != 1 {
+			t.Errorf("want only new binary uploaded, got %d uploads", gs.uploadBundleCallCount)
+		}
+		if len(results) != 2 || !results[0].Skipped || results[0].VersionCode != 11 || results[1].Skipped {
+			t.Errorf("want existing binary skipped as appVersion 11, got %+v", results)
+		}
+		if len(gs.releases) != 1 || len(gs.releases[0].VersionCodes) != 2 || gs.releases[0].VersionCodes[0] != 11 {
+			t.Errorf("want release of appVersions 11 and 12, got %+v", gs.releases)
+		}
+	})
+
+	t.Run("should publish nothing when every binary is live on track already", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		content := createTestFile(t, fs, "test.aab", 20)
+		sum := sha256.Sum256(content)
+		gs := &mockGService{
+			hashes: map[int64]Hashes{11: {Sha256: hex.EncodeToString(sum[:])}},
+			tracks: map[string]*androidpublisher.Track{TrackInternal: {Track: TrackInternal, Releases: []*androidpublisher.TrackRelease{{Status: StatusCompleted, VersionCodes: []int64{11}}}}},
+		}
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithSkipExisting())
+
+		// Act
+		results, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(results) != 1 || !results[0].Skipped || results[0].VersionCode != 11 {
+			t.Errorf("want binary skipped as appVersion 11, got %+v", results)
+		}
+		if len(gs.releases) != 0 || gs.commitEditCount != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted without release, got %d releases %d commits %d deletes", len(gs.releases), gs.commitEditCount, gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should release binaries on Play not live on track", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		content := createTestFile(t, fs, "test.aab", 20)
+		sum := sha256.Sum256(content)
+		gs := &mockGService{
+			hashes: map[int64]Hashes{11: {Sha256: hex.EncodeToString(sum[:])}},
+			tracks: map[string]*androidpublisher.Track{TrackInternal: {Track: TrackInternal, Releases: []*androidpublisher.TrackRelease{{Status: StatusDraft, VersionCodes: []int64{11}}}}},
+		}
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithSkipExisting())
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(gs.releases) != 1 || gs.commitEditCount != 1 {
+			t.Errorf("want draft appVersion released, got %d releases %d commits", len(gs.releases), gs.commitEditCount)
+		}
+	})
+}
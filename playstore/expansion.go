@@ -0,0 +1,73 @@
+package playstore
+
+import (
+	"context"
+	"fmt"
+)
+
+// Play limit for a single apk expansion file
+const expansionMaxFileSize = 2 << 30
+
+// Expanded attaches main and/or patch obb expansion files to apk binary, empty path for none
+func Expanded(b binary, mainObb, patchObb string) binary {
+	b.mainObb = mainObb
+	b.patchObb = patchObb
+	return b
+}
+
+// validateExpansion checks expansion files are only set for apks and exist within Play size limit
+func (p *publish) validateExpansion(b binary, apk bool) error {
+	if b.mainObb == "" && b.patchObb == "" {
+		return nil
+	}
+	if !apk {
+		return fmt.Errorf("expansion files of '%s' are only supported for apk binaries", b.filePath)
+	}
+	for _, obb := range []string{b.mainObb, b.patchObb} {
+		if obb == "" {
+			continue
+		}
+		if !p.fileExits(obb) {
+			return fmt.Errorf("expansion file '%s' does not exist", obb)
+		}
+		size, err := p.fileSize(obb)
+		if err != nil {
+			return err
+		}
+		if size > expansionMaxFileSize {
+			return fmt.Errorf("expansion file '%s' is %d bytes, exceeding Play limit of %d bytes", obb, size, expansionMaxFileSize)
+		}
+	}
+	return nil
+}
+
+// uploadExpansion uploads main and patch expansion files of binary and returns paths uploaded
+func (p *publish) uploadExpansion(ctx context.Context, us IUploadService, b binary, editId string, appVersionCode int64) ([]string, error) {
+	uploaded := make([]string, 0)
+	for _, obb := range []struct{ path, fileType string }{{b.mainObb, ExpansionFileMain}, {b.patchObb, ExpansionFilePatch}} {
+		if obb.path == "" {
+			continue
+		}
+		p.fileDebugf(obb.path, "uploading '%s' expansion file '%s' for appVersionCode '%d'", obb.fileType, obb.path, appVersionCode)
+		f, err := p.fs.Open(obb.path)
+		if err != nil {
+			return uploaded, err
+		}
+		if err := p.recordDigest(obb.path, obb.fileType, f); err != nil {
+			f.Close()
+			return uploaded, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return uploaded, err
+		}
+		err = us.uploadExpansionFile(ctx, p.progressReader(obb.path, f, info.Size()), p.packageName, editId, appVersionCode, obb.fileType)
+		f.Close()
+		if err != nil {
+			return uploaded, fmt.Errorf("failed uploading '%s' expansion file '%s': %w", obb.fileType, obb.path, err)
+		}
+		uploaded = append(uploaded, obb.path)
+	}
+	return uploaded, nil
+}
@@ -0,0 +1,69 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestExpansionFiles(t *testing.T) {
+
+	t.Run("should upload main and patch obb for apk version code", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.apk", 10)
+		createTestFile(t, fs, "main.obb", 30)
+		createTestFile(t, fs, "patch.obb", 5)
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{BinaryWithExpansion("app.apk", "main.obb", "patch.obb")}, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{AppVersionCode: 7}
+
+		// Act
+		_, err = publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		want := []string{"7/" + ExpansionFileMain, "7/" + ExpansionFilePatch}
+		if !reflect.DeepEqual(gs.expansionFiles, want) {
+			t.Errorf("want %v expansion files, got %v", want, gs.expansionFiles)
+		}
+	})
+
+	t.Run("should reject expansion files for bundles", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.aab", 10)
+		createTestFile(t, fs, "main.obb", 30)
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{BinaryWithExpansion("app.aab", "main.obb", "")}, false, false)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should fail on missing expansion file", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.apk", 10)
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{BinaryWithExpansion("app.apk", "", "patch.obb")}, true, false)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
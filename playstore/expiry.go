@@ -0,0 +1,101 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// EditExpiredError edit expired or was deleted while publish was still using it, e.g. during long uploads.
+// Nothing it had was published.
+type EditExpiredError struct {
+	EditID    string
+	ExpiresAt time.Time
+	Err       error
+}
+
+func (e *EditExpiredError) Error() string {
+	expiry := ""
+	if !e.ExpiresAt.IsZero() {
+		expiry = fmt.Sprintf(" (expiry at %s)", e.ExpiresAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("edit '%s'%s expired or was deleted before publish finished, nothing was published. Run again to "+
+		"upload in new edit, allow re-creating expired edit to do it automatically, or upload fewer binaries at once: %v", e.EditID, expiry, e.Err)
+}
+
+func (e *EditExpiredError) Unwrap() error {
+	return e.Err
+}
+
+func (e *EditExpiredError) Is(target error) bool {
+	return target == ErrEditExpired
+}
+
+// CommitUncertainError commit timed out and edit was gone once it was made again, so the timed out commit may
+// have published the edit. Edit is never re-created after it, as that could publish the same release twice.
+type CommitUncertainError struct {
+	EditID string
+	Err    error
+}
+
+func (e *CommitUncertainError) Error() string {
+	return fmt.Sprintf("edit '%s' is gone after commit timed out, the timed out commit may have gone through. Check the track in "+
+		"Play Console before publishing again: %v", e.EditID, e.Err)
+}
+
+func (e *CommitUncertainError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CommitUncertainError) Is(target error) bool {
+	return target == ErrCommitUncertain
+}
+
+// WithEditRecreation uploads everything again in new edit once, when edit expires or is deleted before
+// publish finishes, instead of failing with EditExpiredError
+func WithEditRecreation() Option {
+	return func(p *publish) {
+		p.recreateEdit = true
+	}
+}
+
+// expiredEdit wraps error of a call within edit with EditExpiredError when it failed because edit is gone,
+// which Play answers with editExpired reason. 404 can also be for a missing track or app, so it's only taken
+// for expired edit once getting the edit fails with 404 as well. Others are returned as they are.
+func expiredEdit(edit *Edit, err error) error {
+	if err == nil || errors.Is(err, ErrEditExpired) || errors.Is(err, ErrCommitUncertain) {
+		return err
+	}
+	if !isEditExpiredErr(err) && (StatusCode(err) != http.StatusNotFound || !edit.gone()) {
+		return err
+	}
+	return &EditExpiredError{EditID: edit.ID(), ExpiresAt: edit.ExpiresAt(), Err: err}
+}
+
+// gone checks with Play if edit no longer exists
+func (e *Edit) gone() bool {
+	// publish context may be cancelled already, check gets its own
+	ctx, cancel := context.WithTimeout(context.Background(), abortCleanupTimeout)
+	defer cancel()
+	_, err := e.gs.getEdit(ctx, e.packageName, e.ID())
+	return StatusCode(err) == http.StatusNotFound || isEditExpiredErr(err)
+}
+
+// isEditExpiredErr checks if Play refused a call because edit expired
+func isEditExpiredErr(err error) bool {
+	var gErr *googleapi.Error
+	if !errors.As(err, &gErr) {
+		return false
+	}
+	for _, item := range gErr.Errors {
+		if item.Reason == "editExpired" {
+			return true
+		}
+	}
+	return strings.Contains(gErr.Message, "editExpired")
+}
@@ -0,0 +1,154 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+func TestEditExpiry(t *testing.T) {
+	expiredErr := &APIError{Op: "validate edit", StatusCode: http.StatusNotFound, Err: &googleapi.Error{Code: http.StatusNotFound, Message: "edit not found"}}
+	goneErr := &googleapi.Error{Code: http.StatusNotFound, Message: "edit not found"}
+
+	t.Run("should fail with edit expired error without deleting the edit", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		gs := &mockGService{validateErrors: []error{expiredErr}, getEditError: goneErr}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ee *EditExpiredError
+		if !errors.As(err, &ee) || !errors.Is(err, ErrEditExpired) || StatusCode(err) != http.StatusNotFound {
+			t.Fatalf("want '%v' keeping 404, got: %v", ErrEditExpired, err)
+		}
+		if ee.EditID != "1" || !strings.Contains(err.Error(), "Run again") {
+			t.Errorf("want expired edit '1' with recovery guidance, got: %v", err)
+		}
+		if gs.deleteEditCount != 0 {
+			t.Errorf("want expired edit not deleted, got %d deletes", gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should upload again in new edit when allowed", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithEditRecreation())
+		gs := &mockGService{validateErrors: []error{expiredErr}, getEditError: goneErr}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.createEditCount != 2 || gs.uploadBundleCallCount != 2 || gs.validateEditCount != 2 || gs.commitEditCount != 1 {
+			t.Errorf("want upload and validation repeated in new edit, got %d edits %d uploads %d validations %d commits",
+				gs.createEditCount, gs.uploadBundleCallCount, gs.validateEditCount, gs.commitEditCount)
+		}
+		if f := publish.SoftFailures(); len(f) != 1 || f[0].Kind != SoftFailureFallback {
+			t.Errorf("want edit re-creation recorded as fallback, got %+v", f)
+		}
+	})
+
+	t.Run("should re-create expired edit once", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithEditRecreation())
+		gs := &mockGService{validateErrors: []error{expiredErr, expiredErr}, getEditError: goneErr}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if !errors.Is(err, ErrEditExpired) || gs.createEditCount != 2 {
+			t.Errorf("want '%v' after 2 edits, got %d edits: %v", ErrEditExpired, gs.createEditCount, err)
+		}
+	})
+
+	t.Run("should not take 404 for expired edit while edit still exists", func(t *testing.T) {
+		// Arrange
+		trackErr := &googleapi.Error{Code: http.StatusNotFound, Message: "track not found"}
+
+		// Act
+		err := expiredEdit(newEdit(&mockGService{}, "com.test.app", "2", time.Time{}), trackErr)
+
+		// Assert
+		if errors.Is(err, ErrEditExpired) || err != trackErr {
+			t.Errorf("want '%v' as it is, got: %v", trackErr, err)
+		}
+	})
+
+	t.Run("should not take failed commit for expired edit", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithEditRecreation())
+		gs := &mockGService{commitErrors: []error{goneErr}, getEditError: goneErr}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ae *AbortError
+		if !errors.As(err, &ae) || errors.Is(err, ErrEditExpired) || StatusCode(err) != http.StatusNotFound {
+			t.Fatalf("want 404 not taken for expired edit, got: %v", err)
+		}
+		if gs.createEditCount != 1 {
+			t.Errorf("want edit not re-created after commit, got %d edits", gs.createEditCount)
+		}
+	})
+
+	t.Run("should neither delete nor re-create edit commit may have published", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithEditRecreation())
+		gs := &mockGService{commitErrors: []error{&CommitUncertainError{EditID: "1", Err: expiredErr}}}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ae *AbortError
+		if !errors.As(err, &ae) || !errors.Is(err, ErrCommitUncertain) || errors.Is(err, ErrEditExpired) {
+			t.Fatalf("want '%v' not taken for expired edit, got: %v", ErrCommitUncertain, err)
+		}
+		if !ae.CommitUncertain || ae.EditDeleted || strings.Contains(ae.Report(), "nothing was published") {
+			t.Errorf("want report telling publish outcome is unknown, got: %s", ae.Report())
+		}
+		if gs.createEditCount != 1 || gs.uploadBundleCallCount != 1 || gs.deleteEditCount != 0 {
+			t.Errorf("want single edit neither deleted nor re-created, got %d edits %d uploads %d deletes", gs.createEditCount, gs.uploadBundleCallCount, gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should recognise editExpired reason", func(t *testing.T) {
+		// Arrange
+		err := &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "editExpired"}}}
+
+		// Act
+		expired := expiredEdit(newEdit(&mockGService{}, "com.test.app", "2", time.Time{}), err)
+
+		// Assert
+		if !errors.Is(expired, ErrEditExpired) {
+			t.Errorf("want '%v', got: %v", ErrEditExpired, expired)
+		}
+	})
+}
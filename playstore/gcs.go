@@ -0,0 +1,68 @@
+package playstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/option"
+)
+
+const (
+	// scope gs:// artifacts are read with
+	storageReadScope = "https://www.googleapis.com/auth/devstorage.read_only"
+	// host gs:// artifacts are fetched from, see parseRemote
+	storageHost = "storage.googleapis.com"
+)
+
+// NewGCSClient returns client fetching gs:// artifacts authorized with auth file, e.g. the one publish uses,
+// to set with WithDownloadClient. Only Cloud Storage requests get credentials, other remote artifacts are
+// fetched without them. Credentials need read access to the bucket e.g. Storage Object Viewer role.
+func NewGCSClient(ctx context.Context, authFile string, opts ...ServiceOption) (*http.Client, error) {
+	return newGCSClient(ctx, option.WithCredentialsFile(authFile), opts...)
+}
+
+// NewGCSClientFromJSON returns client fetching gs:// artifacts authorized with service account JSON, see NewGCSClient
+func NewGCSClientFromJSON(ctx context.Context, credentials []byte, opts ...ServiceOption) (*http.Client, error) {
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("service account credentials must not be empty")
+	}
+	return newGCSClient(ctx, option.WithCredentialsJSON(credentials), opts...)
+}
+
+// NewGCSClientWithADC returns client fetching gs:// artifacts authorized with Application Default Credentials,
+// see NewGCSClient
+func NewGCSClientWithADC(ctx context.Context, opts ...ServiceOption) (*http.Client, error) {
+	return newGCSClient(ctx, option.WithScopes(storageReadScope), opts...)
+}
+
+func newGCSClient(ctx context.Context, credentials option.ClientOption, opts ...ServiceOption) (*http.Client, error) {
+	cfg := newServiceConfig(opts...)
+	authorized, err := newScopedClient(ctx, storageReadScope, credentials, cfg)
+	if err != nil {
+		return nil, err
+	}
+	base, err := cfg.baseClient()
+	if err != nil {
+		return nil, err
+	}
+	plain := http.DefaultTransport
+	if base != nil && base.Transport != nil {
+		plain = base.Transport
+	}
+	return &http.Client{Transport: &storageTransport{authorized: authorized.Transport, plain: plain}, Timeout: authorized.Timeout}, nil
+}
+
+// storageTransport authorizes Cloud Storage requests only, so credentials never reach other artifact hosts
+type storageTransport struct {
+	authorized http.RoundTripper
+	plain      http.RoundTripper
+}
+
+func (t *storageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "https" && strings.EqualFold(req.URL.Hostname(), storageHost) {
+		return t.authorized.RoundTrip(req)
+	}
+	return t.plain.RoundTrip(req)
+}
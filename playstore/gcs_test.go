@@ -0,0 +1,39 @@
+package playstore
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestStorageTransport(t *testing.T) {
+
+	t.Run("should authorize Cloud Storage requests only", func(t *testing.T) {
+		// Arrange
+		var authorized, plain []string
+		record := func(to *[]string) http.RoundTripper {
+			return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				*to = append(*to, req.URL.String())
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+			})
+		}
+		client := &http.Client{Transport: &storageTransport{authorized: record(&authorized), plain: record(&plain)}}
+		a, _ := parseRemote("gs://ci-builds/app.aab")
+
+		// Act
+		for _, u := range []string{a.url, "https://artifacts.example/app.aab", "http://storage.googleapis.com/ci-builds/app.aab"} {
+			res, err := client.Get(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+		}
+
+		// Assert
+		if len(authorized) != 1 || authorized[0] != "https://storage.googleapis.com/ci-builds/app.aab" {
+			t.Errorf("want only https Cloud Storage request authorized, got %v", authorized)
+		}
+		if len(plain) != 2 {
+			t.Errorf("want other requests without credentials, got %v", plain)
+		}
+	})
+}
@@ -2,7 +2,12 @@ package playstore
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"google.golang.org/api/androidpublisher/v3"
@@ -31,135 +36,709 @@ const (
 	DeobfuscationFileTypeUnspecified = "deobfuscationFileTypeUnspecified"
 	DeobfuscationFileProguard        = "proguard"
 	DeobfuscationFile                = "nativeCode"
+
+	// https://developers.google.com/android-publisher/api-ref/rest/v3/edits.expansionfiles
+	ExpansionFileMain  = "main"
+	ExpansionFilePatch = "patch"
 )
 
 type IGService interface {
 	IEditsService
 	IUploadService
-	IDraftService
+	IReleaseService
+	IArtifactsService
+	ITracksService
+	IListingsService
+	IImagesService
+	IReviewsService
+	IDetailsService
 }
 
 type gService struct {
 	*editsService
 	*uploadService
-	*draftService
+	*releaseService
+	*artifactsService
+	*tracksService
+	*listingsService
+	*imagesService
+	*reviewsService
+	*detailsService
+	*resumableService
+}
+
+// serviceConfig optional Google API service settings
+type serviceConfig struct {
+	chunkRetryDeadline time.Duration
+	uploadTimeout      time.Duration
+	validateTimeout    time.Duration
+	commitTimeout      time.Duration
+	chunkSize          int
+	retry              *RetryPolicy
+	logger             Logger
+	// log every request, see TracingTransport
+	httpTracing bool
+	// transport and proxy requests are made with, defaults if not set
+	httpClient *http.Client
+	proxy      string
+	// requests per second limit, see WithRateLimit
+	qps float64
 }
 
-func NewGEditsService(authFile string) (IGService, error) {
-	edits, err := androidpublisher.NewService(context.Background(), option.WithCredentialsFile(authFile))
+// ServiceOption sets optional Google API service configuration
+type ServiceOption func(*serviceConfig)
+
+// newServiceConfig returns defaults with options applied
+func newServiceConfig(opts ...ServiceOption) *serviceConfig {
+	cfg := &serviceConfig{
+		chunkRetryDeadline: chunkRetryDeadline,
+		chunkSize:          -1,
+	}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return cfg
+}
+
+// WithChunkRetryDeadline sets how long a failed upload chunk is retried for, 60s by default. Once it expires
+// bundle and apk uploads ask Play how much of the chunk arrived and go on from there, up to 3 times per chunk,
+// instead of uploading the file from scratch. Raise it for flaky networks.
+func WithChunkRetryDeadline(d time.Duration) ServiceOption {
+	return func(c *serviceConfig) {
+		c.chunkRetryDeadline = d
+	}
+}
+
+// WithUploadTimeout limits how long a single file upload may take, no limit by default
+func WithUploadTimeout(d time.Duration) ServiceOption {
+	return func(c *serviceConfig) {
+		c.uploadTimeout = d
+	}
+}
+
+// WithEditCallTimeouts limits how long validating and committing edit may take, slow for very large apps.
+// Call running out of its timeout is made once more with the same timeout, 0 for no limit, the default.
+func WithEditCallTimeouts(validate, commit time.Duration) ServiceOption {
+	return func(c *serviceConfig) {
+		c.validateTimeout = validate
+		c.commitTimeout = commit
+	}
+}
+
+// WithChunkSize sets upload chunk size in bytes, rounded up to 256KiB by googleapi. 0 uploads in a single request.
+func WithChunkSize(size int) ServiceOption {
+	return func(c *serviceConfig) {
+		c.chunkSize = size
+	}
+}
+
+func NewGEditsService(ctx context.Context, authFile string, opts ...ServiceOption) (IGService, error) {
+	return newGEditsService(ctx, option.WithCredentialsFile(authFile), opts...)
+}
+
+// newGEditsService creates service authorized with given credentials option. Authorized client is
+// shared with resumable uploads, which talk to upload endpoint directly to keep their session URI.
+func newGEditsService(ctx context.Context, credentials option.ClientOption, opts ...ServiceOption) (IGService, error) {
+	client, err := newClient(ctx, credentials, newServiceConfig(opts...))
+	if err != nil {
+		return nil, err
+	}
+	edits, err := androidpublisher.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
 		return nil, err
 	}
-	return &gService{
-		editsService:  &editsService{edits: edits.Edits},
-		uploadService: &uploadService{edits: edits.Edits},
-		draftService:  &draftService{edits: edits.Edits},
-	}, nil
+	return newGService(edits, client, opts...), nil
+}
+
+// newGService builds IGService of androidpublisher service, client is used for resumable uploads if set
+func newGService(edits *androidpublisher.Service, client *http.Client, opts ...ServiceOption) IGService {
+	cfg := newServiceConfig(opts...)
+
+	sessions := &resumableService{client: client, basePath: edits.BasePath, cfg: cfg}
+	gs := &gService{
+		editsService:     &editsService{edits: edits.Edits, cfg: cfg},
+		uploadService:    &uploadService{media: &mediaCalls{edits: edits.Edits}, cfg: cfg, sessions: sessions},
+		releaseService:   &releaseService{edits: edits.Edits},
+		artifactsService: &artifactsService{edits: edits.Edits},
+		tracksService:    &tracksService{edits: edits.Edits},
+		listingsService:  &listingsService{edits: edits.Edits},
+		imagesService:    &imagesService{edits: edits.Edits},
+		reviewsService:   &reviewsService{reviews: edits.Reviews},
+		detailsService:   &detailsService{edits: edits.Edits},
+		resumableService: sessions,
+	}
+	if cfg.retry != nil && cfg.retry.Retries > 0 {
+		l := cfg.logger
+		if l == nil {
+			l = defaultLogger
+		}
+		return newRetryingService(gs, *cfg.retry, l)
+	}
+	return gs
 }
 
 /**
  * Google API wrapper for edit creation, validation and commit
  */
 type IEditsService interface {
-	createEdit(packageName string) (string, error)
-	validateEdit(packageName, editId string) error
-	deleteEdit(packageName, editId string) error
-	commitEdit(packageName, editId string) error
+	createEdit(ctx context.Context, packageName string) (editId string, expiresAt time.Time, err error)
+	validateEdit(ctx context.Context, packageName, editId string) error
+	deleteEdit(ctx context.Context, packageName, editId string) error
+	commitEdit(ctx context.Context, packageName, editId string, changesNotSentForReview bool) error
+	getEdit(ctx context.Context, packageName, editId string) (expiresAt time.Time, err error)
 }
 
 type editsService struct {
 	edits *androidpublisher.EditsService
+	cfg   *serviceConfig
 }
 
-// createEdit creates an edit on playstore and returns editId
-func (es *editsService) createEdit(packageName string) (string, error) {
+// createEdit creates an edit on playstore and returns editId with time edit expires at
+func (es *editsService) createEdit(ctx context.Context, packageName string) (editId string, expiresAt time.Time, err error) {
 	edit := &androidpublisher.AppEdit{}
-	e, err := es.edits.Insert(packageName, edit).Do()
+	e, err := es.edits.Insert(packageName, edit).Context(ctx).Do()
+	if err != nil {
+		return "", time.Time{}, apiError("create edit", err)
+	}
+	return e.Id, editExpiry(e), nil
+}
+
+// getEdit checks edit is still open and returns time it expires at
+func (es *editsService) getEdit(ctx context.Context, packageName, editId string) (expiresAt time.Time, err error) {
+	e, err := es.edits.Get(packageName, editId).Context(ctx).Do()
+	if err != nil {
+		return time.Time{}, apiError("get edit", err)
+	}
+	return editExpiry(e), nil
+}
+
+// editExpiry returns time edit expires at, zero if unknown as edit is usable regardless
+func editExpiry(e *androidpublisher.AppEdit) time.Time {
+	sec, err := strconv.ParseInt(e.ExpiryTimeSeconds, 10, 64)
 	if err != nil {
-		return "", err
+		return time.Time{}
 	}
-	return e.Id, nil
+	return time.Unix(sec, 0)
 }
 
 // validateEdit validates edit for a given package on a playstore and returns error if edit validation failed
-func (es *editsService) validateEdit(packageName, editId string) error {
-	_, err := es.edits.Validate(packageName, editId).Do()
-	return err
+func (es *editsService) validateEdit(ctx context.Context, packageName, editId string) error {
+	_, err := es.timedCall(ctx, "validate edit", es.timeouts().validateTimeout, func(ctx context.Context) error {
+		_, err := es.edits.Validate(packageName, editId).Context(ctx).Do()
+		return err
+	})
+	return apiError("validate edit", err)
 }
 
 // deleteEdit deletes edit on playstore
-func (es *editsService) deleteEdit(packageName, editId string) error {
-	return es.edits.Delete(packageName, editId).Do()
+func (es *editsService) deleteEdit(ctx context.Context, packageName, editId string) error {
+	return apiError("delete edit", es.edits.Delete(packageName, editId).Context(ctx).Do())
 }
 
-// commits edit on playstore
-func (es *editsService) commitEdit(packageName, editId string) error {
-	_, err := es.edits.Commit(packageName, editId).Do()
-	return err
+// commits edit on playstore, with changesNotSentForReview changes have to be sent for review from Play Console
+func (es *editsService) commitEdit(ctx context.Context, packageName, editId string, changesNotSentForReview bool) error {
+	retried, err := es.timedCall(ctx, "commit edit", es.timeouts().commitTimeout, func(ctx context.Context) error {
+		_, err := es.edits.Commit(packageName, editId).ChangesNotSentForReview(changesNotSentForReview).Context(ctx).Do()
+		return err
+	})
+	if retried && StatusCode(err) == http.StatusNotFound {
+		// Play may have finished the timed out commit, leaving no edit for the repeated one
+		return &CommitUncertainError{EditID: editId, Err: apiError("commit edit", err)}
+	}
+	return apiError("commit edit", err)
+}
+
+// timeouts returns service configuration, no timeouts if none set
+func (es *editsService) timeouts() *serviceConfig {
+	if es.cfg == nil {
+		return &serviceConfig{}
+	}
+	return es.cfg
+}
+
+// timedCall runs call with timeout if set, once more when it runs out of it while ctx is still alive.
+// Reports if call was repeated.
+func (es *editsService) timedCall(ctx context.Context, op string, timeout time.Duration, call func(ctx context.Context) error) (bool, error) {
+	if timeout <= 0 {
+		return false, call(ctx)
+	}
+	for attempt := 0; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := call(callCtx)
+		timedOut := err != nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		cancel()
+		if timedOut && attempt > 0 {
+			return true, fmt.Errorf("timed out twice after %s: %w", timeout, err)
+		}
+		if !timedOut || attempt > 0 {
+			return attempt > 0, err
+		}
+		es.log().Warnf("%s timed out after %s, trying once more: %v", op, timeout, err)
+	}
+}
+
+func (es *editsService) log() Logger {
+	if es.cfg == nil || es.cfg.logger == nil {
+		return defaultLogger
+	}
+	return es.cfg.logger
+}
+
+// isChangesNotSentForReviewErr checks if commit was rejected because changes can not be sent for review automatically
+func isChangesNotSentForReviewErr(err error) bool {
+	var gErr *googleapi.Error
+	if !errors.As(err, &gErr) || gErr.Code != http.StatusBadRequest {
+		return false
+	}
+	return strings.Contains(gErr.Message, "changesNotSentForReview")
 }
 
 /**
  * Google API wrapper for bundle and proguard mapping uploads
  */
 type IUploadService interface {
-	uploadBundle(r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error)
-	uploadApk(r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error)
-	uploadProguardMapping(r io.Reader, packageName, editId string, appVersionCode int64) error
+	uploadBundle(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error)
+	uploadApk(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error)
+	uploadProguardMapping(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error
+	uploadNativeSymbols(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error
+	uploadExpansionFile(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string) error
 }
 
 type uploadService struct {
-	edits *androidpublisher.EditsService
+	media mediaUploader
+	cfg   *serviceConfig
+	// uploads chunked bundles and apks over sessions of its own if set, so they resume after chunk retry deadline
+	sessions *resumableService
+}
+
+// config returns service configuration, defaults if none set
+func (us *uploadService) config() *serviceConfig {
+	if us.cfg == nil {
+		return &serviceConfig{chunkRetryDeadline: chunkRetryDeadline, chunkSize: -1}
+	}
+	return us.cfg
+}
+
+// mediaOptions media upload settings shared by all uploads
+func (us *uploadService) mediaOptions() []googleapi.MediaOption {
+	cfg := us.config()
+	opts := []googleapi.MediaOption{googleapi.ContentType(mediaHeader), googleapi.ChunkRetryDeadline(cfg.chunkRetryDeadline)}
+	if cfg.chunkSize >= 0 {
+		opts = append(opts, googleapi.ChunkSize(cfg.chunkSize))
+	}
+	return opts
+}
+
+// chunkedSessions reports if bundles and apks are uploaded over resumable sessions of the service, it takes an
+// authorized client and chunking on
+func (us *uploadService) chunkedSessions() bool {
+	return us.sessions != nil && us.sessions.client != nil && us.config().chunkSize != 0
+}
+
+// uploadContext applies upload timeout to context if one is configured
+func (us *uploadService) uploadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t := us.config().uploadTimeout; t > 0 {
+		return context.WithTimeout(ctx, t)
+	}
+	return context.WithCancel(ctx)
 }
 
 // uploadBundle uploads provided aab to playstore and returns upload version number and sha256 hash on success
-func (us *uploadService) uploadBundle(r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
-	uRq := us.edits.Bundles.Upload(packageName, editId)
-	uploaded, err := uRq.Media(r, googleapi.ContentType(mediaHeader), googleapi.ChunkRetryDeadline(chunkRetryDeadline)).Do()
+func (us *uploadService) uploadBundle(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
+	ctx, cancel := us.uploadContext(ctx)
+	defer cancel()
+	if us.chunkedSessions() {
+		res, err := us.sessions.uploadChunked(ctx, r, packageName, editId, false)
+		if err != nil {
+			return -1, "", apiError("upload bundle", err)
+		}
+		return res.VersionCode, res.Sha256, nil
+	}
+	uploaded, err := us.media.bundle(ctx, r, packageName, editId, us.mediaOptions()...)
 	if err != nil {
-		return -1, "", err
+		return -1, "", apiError("upload bundle", err)
 	}
 	return uploaded.VersionCode, uploaded.Sha256, nil
 }
 
 // uploadApk uploads provided apk to playstore and returns upload version number and sha256 hash on success
-func (us *uploadService) uploadApk(r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
-	uRq := us.edits.Apks.Upload(packageName, editId)
-	uploaded, err := uRq.Media(r, googleapi.ContentType(mediaHeader), googleapi.ChunkRetryDeadline(chunkRetryDeadline)).Do()
+func (us *uploadService) uploadApk(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
+	ctx, cancel := us.uploadContext(ctx)
+	defer cancel()
+	if us.chunkedSessions() {
+		res, err := us.sessions.uploadChunked(ctx, r, packageName, editId, true)
+		if err != nil {
+			return -1, "", apiError("upload apk", err)
+		}
+		if res.Sha256 == "" {
+			return -1, "", fmt.Errorf("apk with appVersion '%d' uploaded, but playstore returned no binary details", res.VersionCode)
+		}
+		return res.VersionCode, res.Sha256, nil
+	}
+	uploaded, err := us.media.apk(ctx, r, packageName, editId, us.mediaOptions()...)
 	if err != nil {
-		return -1, "", err
+		return -1, "", apiError("upload apk", err)
+	}
+	if uploaded.Binary == nil {
+		return -1, "", fmt.Errorf("apk with appVersion '%d' uploaded, but playstore returned no binary details", uploaded.VersionCode)
 	}
 	return uploaded.VersionCode, uploaded.Binary.Sha256, nil
 }
 
 // uploadProguardMapping uploads provided mappings file to playstore
-func (us *uploadService) uploadProguardMapping(r io.Reader, packageName, editId string, appVersionCode int64) error {
-	uRq := us.edits.Deobfuscationfiles.Upload(packageName, editId, appVersionCode, DeobfuscationFileProguard)
-	_, err := uRq.Media(r, googleapi.ContentType(mediaHeader)).Do()
+func (us *uploadService) uploadProguardMapping(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error {
+	ctx, cancel := us.uploadContext(ctx)
+	defer cancel()
+	return apiError("upload mapping", us.media.deobfuscation(ctx, r, packageName, editId, appVersionCode, DeobfuscationFileProguard, googleapi.ContentType(mediaHeader)))
+}
+
+func (us *uploadService) uploadNativeSymbols(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error {
+	ctx, cancel := us.uploadContext(ctx)
+	defer cancel()
+	return apiError("upload native symbols", us.media.deobfuscation(ctx, r, packageName, editId, appVersionCode, DeobfuscationFile, googleapi.ContentType(mediaHeader)))
+}
+
+/**
+ * googleapi media call plumbing, kept behind interface so upload handling can be tested without Google APIs
+ */
+type mediaUploader interface {
+	bundle(ctx context.Context, r io.Reader, packageName, editId string, opts ...googleapi.MediaOption) (*androidpublisher.Bundle, error)
+	apk(ctx context.Context, r io.Reader, packageName, editId string, opts ...googleapi.MediaOption) (*androidpublisher.Apk, error)
+	deobfuscation(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string, opts ...googleapi.MediaOption) error
+	expansion(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string, opts ...googleapi.MediaOption) error
+}
+
+type mediaCalls struct {
+	edits *androidpublisher.EditsService
+}
+
+func (mc *mediaCalls) bundle(ctx context.Context, r io.Reader, packageName, editId string, opts ...googleapi.MediaOption) (*androidpublisher.Bundle, error) {
+	return mc.edits.Bundles.Upload(packageName, editId).Media(r, opts...).Context(ctx).Do()
+}
+
+func (mc *mediaCalls) apk(ctx context.Context, r io.Reader, packageName, editId string, opts ...googleapi.MediaOption) (*androidpublisher.Apk, error) {
+	return mc.edits.Apks.Upload(packageName, editId).Media(r, opts...).Context(ctx).Do()
+}
+
+func (mc *mediaCalls) expansion(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string, opts ...googleapi.MediaOption) error {
+	_, err := mc.edits.Expansionfiles.Upload(packageName, editId, appVersionCode, fileType).Media(r, opts...).Context(ctx).Do()
+	return err
+}
+
+func (mc *mediaCalls) deobfuscation(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string, opts ...googleapi.MediaOption) error {
+	_, err := mc.edits.Deobfuscationfiles.Upload(packageName, editId, appVersionCode, fileType).Media(r, opts...).Context(ctx).Do()
 	return err
 }
 
+// uploadExpansionFile uploads main or patch obb expansion file for an apk appVersionCode
+func (us *uploadService) uploadExpansionFile(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string) error {
+	ctx, cancel := us.uploadContext(ctx)
+	defer cancel()
+	return apiError("upload expansion file", us.media.expansion(ctx, r, packageName, editId, appVersionCode, fileType, us.mediaOptions()...))
+}
+
 /**
- * Google API wrapper to create a simple draft
+ * Google API wrapper to create a track release
  */
-type IDraftService interface {
-	createDraft(packageName, editId, trackName string, appVersionCodes []int64) error
+type IReleaseService interface {
+	createRelease(ctx context.Context, packageName, editId, trackName string, release *androidpublisher.TrackRelease) error
 }
 
-type draftService struct {
+type releaseService struct {
 	edits *androidpublisher.EditsService
 }
 
-// createDraft creats a draft for given track and assigns appversions to it
-func (ds *draftService) createDraft(packageName, editId, trackName string, appVersionCodes []int64) error {
+// createRelease creates a release for given track e.g. draft or staged rollout with appversions assigned to it
+func (rs *releaseService) createRelease(ctx context.Context, packageName, editId, trackName string, release *androidpublisher.TrackRelease) error {
 	track := &androidpublisher.Track{
-		Releases: []*androidpublisher.TrackRelease{
-			{
-				Status:       StatusDraft,
-				VersionCodes: appVersionCodes,
-			},
-		},
-		Track: trackName,
-	}
-	_, err := ds.edits.Tracks.Update(packageName, editId, trackName, track).Do()
-	return err
+		Releases: []*androidpublisher.TrackRelease{release},
+		Track:    trackName,
+	}
+	_, err := rs.edits.Tracks.Update(packageName, editId, trackName, track).Context(ctx).Do()
+	return apiError("create release", err)
+}
+
+/**
+ * Google API wrapper for reading already uploaded bundles and apks
+ */
+type IArtifactsService interface {
+	versionHashes(ctx context.Context, packageName, editId string) (map[int64]Hashes, error)
+}
+
+type artifactsService struct {
+	edits *androidpublisher.EditsService
+}
+
+// versionHashes lists all bundles and apks uploaded for the app and returns their hashes by appVersionCode
+func (as *artifactsService) versionHashes(ctx context.Context, packageName, editId string) (map[int64]Hashes, error) {
+	hashes := make(map[int64]Hashes)
+
+	bundles, err := as.edits.Bundles.List(packageName, editId).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("list binaries", err)
+	}
+	for _, b := range bundles.Bundles {
+		hashes[b.VersionCode] = Hashes{Sha256: b.Sha256, Sha1: b.Sha1}
+	}
+
+	apks, err := as.edits.Apks.List(packageName, editId).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("list binaries", err)
+	}
+	for _, a := range apks.Apks {
+		if a.Binary == nil {
+			continue
+		}
+		hashes[a.VersionCode] = Hashes{Sha256: a.Binary.Sha256, Sha1: a.Binary.Sha1}
+	}
+	return hashes, nil
+}
+
+/**
+ * Google API wrapper for reading and updating track releases
+ */
+type ITracksService interface {
+	listTracks(ctx context.Context, packageName, editId string) ([]*androidpublisher.Track, error)
+	getTrack(ctx context.Context, packageName, editId, trackName string) (*androidpublisher.Track, error)
+	updateTrack(ctx context.Context, packageName, editId string, track *androidpublisher.Track) error
+}
+
+type tracksService struct {
+	edits *androidpublisher.EditsService
+}
+
+// listTracks returns all tracks with their releases
+func (ts *tracksService) listTracks(ctx context.Context, packageName, editId string) ([]*androidpublisher.Track, error) {
+	res, err := ts.edits.Tracks.List(packageName, editId).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("list tracks", err)
+	}
+	return res.Tracks, nil
+}
+
+// getTrack returns track with all its releases
+func (ts *tracksService) getTrack(ctx context.Context, packageName, editId, trackName string) (*androidpublisher.Track, error) {
+	track, err := ts.edits.Tracks.Get(packageName, editId, trackName).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("get track", err)
+	}
+	return track, nil
+}
+
+// updateTrack replaces track releases with the ones provided
+func (ts *tracksService) updateTrack(ctx context.Context, packageName, editId string, track *androidpublisher.Track) error {
+	_, err := ts.edits.Tracks.Update(packageName, editId, track.Track, track).Context(ctx).Do()
+	return apiError("update track", err)
+}
+
+/**
+ * Google API wrapper for localized store listings
+ */
+type IListingsService interface {
+	listListings(ctx context.Context, packageName, editId string) ([]Listing, error)
+	updateListing(ctx context.Context, packageName, editId string, listing Listing) error
+	deleteListing(ctx context.Context, packageName, editId, locale string) error
+}
+
+type listingsService struct {
+	edits *androidpublisher.EditsService
+}
+
+// listListings returns store listings for every locale
+func (ls *listingsService) listListings(ctx context.Context, packageName, editId string) ([]Listing, error) {
+	res, err := ls.edits.Listings.List(packageName, editId).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("list listings", err)
+	}
+	listings := make([]Listing, 0, len(res.Listings))
+	for _, l := range res.Listings {
+		listings = append(listings, Listing{
+			Locale:           l.Language,
+			Title:            l.Title,
+			ShortDescription: l.ShortDescription,
+			FullDescription:  l.FullDescription,
+			Video:            l.Video,
+		})
+	}
+	return listings, nil
+}
+
+// updateListing creates or replaces store listing for listing locale
+func (ls *listingsService) updateListing(ctx context.Context, packageName, editId string, listing Listing) error {
+	l := &androidpublisher.Listing{
+		Language:         listing.Locale,
+		Title:            listing.Title,
+		ShortDescription: listing.ShortDescription,
+		FullDescription:  listing.FullDescription,
+		Video:            listing.Video,
+	}
+	_, err := ls.edits.Listings.Update(packageName, editId, listing.Locale, l).Context(ctx).Do()
+	return apiError("update listing", err)
+}
+
+// deleteListing deletes store listing for a locale
+func (ls *listingsService) deleteListing(ctx context.Context, packageName, editId, locale string) error {
+	return apiError("delete listing", ls.edits.Listings.Delete(packageName, editId, locale).Context(ctx).Do())
+}
+
+/**
+ * Google API wrapper for store listing images
+ */
+type IImagesService interface {
+	deleteAllImages(ctx context.Context, packageName, editId, locale, imageType string) (deleted int, err error)
+	uploadImage(ctx context.Context, r io.Reader, packageName, editId, locale, imageType string) error
+	listImages(ctx context.Context, packageName, editId, locale, imageType string) (sha256 []string, err error)
+}
+
+type imagesService struct {
+	edits *androidpublisher.EditsService
+}
+
+// deleteAllImages deletes all images of a type for a locale and returns how many were deleted
+func (is *imagesService) deleteAllImages(ctx context.Context, packageName, editId, locale, imageType string) (deleted int, err error) {
+	res, err := is.edits.Images.Deleteall(packageName, editId, locale, imageType).Context(ctx).Do()
+	if err != nil {
+		return 0, apiError("delete images", err)
+	}
+	return len(res.Deleted), nil
+}
+
+// listImages returns sha256 of every image of a type for a locale, in display order
+func (is *imagesService) listImages(ctx context.Context, packageName, editId, locale, imageType string) (sha256 []string, err error) {
+	res, err := is.edits.Images.List(packageName, editId, locale, imageType).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("list images", err)
+	}
+	sha256 = make([]string, 0, len(res.Images))
+	for _, i := range res.Images {
+		sha256 = append(sha256, i.Sha256)
+	}
+	return sha256, nil
+}
+
+// uploadImage uploads png or jpeg image of a type for a locale, content type is detected from the image
+func (is *imagesService) uploadImage(ctx context.Context, r io.Reader, packageName, editId, locale, imageType string) error {
+	_, err := is.edits.Images.Upload(packageName, editId, locale, imageType).Media(r).Context(ctx).Do()
+	return apiError("upload image", err)
+}
+
+/**
+ * Google API wrapper for user reviews
+ */
+type IReviewsService interface {
+	listReviews(ctx context.Context, packageName string) ([]Review, error)
+	getReview(ctx context.Context, packageName, reviewId string) (*Review, error)
+	replyReview(ctx context.Context, packageName, reviewId, text string) error
+}
+
+type reviewsService struct {
+	reviews *androidpublisher.ReviewsService
+}
+
+// listReviews returns every review Play lists, following page tokens. Play only lists reviews
+// with comments modified during the last week.
+func (rs *reviewsService) listReviews(ctx context.Context, packageName string) ([]Review, error) {
+	reviews := make([]Review, 0)
+	token := ""
+	for {
+		call := rs.reviews.List(packageName).Context(ctx)
+		if token != "" {
+			call = call.Token(token)
+		}
+		res, err := call.Do()
+		if err != nil {
+			return nil, apiError("list reviews", err)
+		}
+		for _, r := range res.Reviews {
+			if review, ok := userReview(r); ok {
+				reviews = append(reviews, review)
+			}
+		}
+		if res.TokenPagination == nil || res.TokenPagination.NextPageToken == "" {
+			return reviews, nil
+		}
+		token = res.TokenPagination.NextPageToken
+	}
+}
+
+func (rs *reviewsService) getReview(ctx context.Context, packageName, reviewId string) (*Review, error) {
+	res, err := rs.reviews.Get(packageName, reviewId).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("get review", err)
+	}
+	review, ok := userReview(res)
+	if !ok {
+		return nil, fmt.Errorf("review '%s' has no user comment", reviewId)
+	}
+	return &review, nil
+}
+
+func (rs *reviewsService) replyReview(ctx context.Context, packageName, reviewId, text string) error {
+	_, err := rs.reviews.Reply(packageName, reviewId, &androidpublisher.ReviewsReplyRequest{ReplyText: text}).Context(ctx).Do()
+	return apiError("reply to review", err)
+}
+
+// userReview takes the user comment on review with developer reply to it, if any
+func userReview(r *androidpublisher.Review) (Review, bool) {
+	review := Review{ID: r.ReviewId, Author: r.AuthorName}
+	found := false
+	for _, c := range r.Comments {
+		switch {
+		case c == nil:
+		case c.UserComment != nil && !found:
+			found = true
+			review.StarRating = c.UserComment.StarRating
+			review.Text = strings.TrimSpace(c.UserComment.Text)
+			review.Language = c.UserComment.ReviewerLanguage
+			review.AppVersionCode = c.UserComment.AppVersionCode
+			review.LastModified = timestamp(c.UserComment.LastModified)
+		case c.DeveloperComment != nil:
+			review.Reply = c.DeveloperComment.Text
+			if m := c.DeveloperComment.LastModified; m != nil {
+				at := timestamp(m)
+				review.RepliedAt = &at
+			}
+		}
+	}
+	return review, found
+}
+
+// timestamp converts Play timestamp, zero time for none
+func timestamp(t *androidpublisher.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return time.Unix(t.Seconds, t.Nanos).UTC()
+}
+
+/**
+ * Google API wrapper for app details
+ */
+type IDetailsService interface {
+	getDetails(ctx context.Context, packageName, editId string) (*AppDetails, error)
+	updateDetails(ctx context.Context, packageName, editId string, details AppDetails) error
+}
+
+type detailsService struct {
+	edits *androidpublisher.EditsService
+}
+
+func (ds *detailsService) getDetails(ctx context.Context, packageName, editId string) (*AppDetails, error) {
+	res, err := ds.edits.Details.Get(packageName, editId).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("get app details", err)
+	}
+	return &AppDetails{DefaultLanguage: res.DefaultLanguage, ContactEmail: res.ContactEmail, ContactPhone: res.ContactPhone, ContactWebsite: res.ContactWebsite}, nil
+}
+
+// updateDetails replaces app details, fields left empty are cleared
+func (ds *detailsService) updateDetails(ctx context.Context, packageName, editId string, details AppDetails) error {
+	d := &androidpublisher.AppDetails{
+		DefaultLanguage: details.DefaultLanguage,
+		ContactEmail:    details.ContactEmail,
+		ContactPhone:    details.ContactPhone,
+		ContactWebsite:  details.ContactWebsite,
+	}
+	_, err := ds.edits.Details.Update(packageName, editId, d).Context(ctx).Do()
+	return apiError("update app details", err)
 }
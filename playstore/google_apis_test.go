@@ -0,0 +1,286 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+func TestUploadService(t *testing.T) {
+
+	t.Run("should return bundle version code and hash", func(t *testing.T) {
+		// Arrange
+		media := &stubMedia{bundleRes: &androidpublisher.Bundle{VersionCode: 7, Sha256: "abc"}}
+		us := &uploadService{media: media}
+
+		// Act
+		v, sha, err := us.uploadBundle(context.Background(), strings.NewReader("aab"), "com.test.app", "1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if v != 7 || sha != "abc" {
+			t.Errorf("want version 7 with 'abc' hash, got %d with '%s'", v, sha)
+		}
+		if string(media.body) != "aab" {
+			t.Errorf("want 'aab' uploaded, got '%s'", media.body)
+		}
+		if media.optCount != 2 {
+			t.Errorf("want content type and chunk retry deadline media options, got %d options", media.optCount)
+		}
+	})
+
+	t.Run("should apply configured chunk size and upload timeout", func(t *testing.T) {
+		// Arrange
+		media := &stubMedia{bundleRes: &androidpublisher.Bundle{VersionCode: 7}}
+		cfg := &serviceConfig{}
+		WithUploadTimeout(time.Minute)(cfg)
+		WithChunkSize(1 << 20)(cfg)
+		WithChunkRetryDeadline(time.Second)(cfg)
+		us := &uploadService{media: media, cfg: cfg}
+
+		// Act
+		_, _, err := us.uploadBundle(context.Background(), strings.NewReader("aab"), "com.test.app", "1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if media.optCount != 3 {
+			t.Errorf("want content type, chunk retry deadline and chunk size media options, got %d options", media.optCount)
+		}
+		if !media.hasDeadline {
+			t.Error("want upload context with deadline, got none")
+		}
+	})
+
+	t.Run("should fail when stream breaks mid upload", func(t *testing.T) {
+		// Arrange
+		media := &stubMedia{bundleRes: &androidpublisher.Bundle{VersionCode: 7}}
+		us := &uploadService{media: media}
+		r := io.MultiReader(strings.NewReader("part"), &failingReader{err: errors.New("connection reset")})
+
+		// Act
+		v, _, err := us.uploadBundle(context.Background(), r, "com.test.app", "1")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if v != -1 {
+			t.Errorf("want -1 version on failure, got %d", v)
+		}
+	})
+
+	t.Run("should pass through api errors", func(t *testing.T) {
+		// Arrange
+		apiErr := &googleapi.Error{Code: 503}
+		us := &uploadService{media: &stubMedia{err: apiErr}}
+
+		// Act
+		_, _, err := us.uploadApk(context.Background(), strings.NewReader("apk"), "com.test.app", "1")
+
+		// Assert
+		if !errors.Is(err, apiErr) {
+			t.Errorf("want '%v', got '%v'", apiErr, err)
+		}
+	})
+
+	t.Run("should fail apk upload without binary details", func(t *testing.T) {
+		// Arrange
+		us := &uploadService{media: &stubMedia{apkRes: &androidpublisher.Apk{VersionCode: 3}}}
+
+		// Act
+		_, _, err := us.uploadApk(context.Background(), strings.NewReader("apk"), "com.test.app", "1")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should upload mapping as proguard file", func(t *testing.T) {
+		// Arrange
+		media := &stubMedia{}
+		us := &uploadService{media: media}
+
+		// Act
+		err := us.uploadProguardMapping(context.Background(), strings.NewReader("mapping"), "com.test.app", "1", 3)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if media.fileType != DeobfuscationFileProguard {
+			t.Errorf("want '%s' file type, got '%s'", DeobfuscationFileProguard, media.fileType)
+		}
+	})
+}
+
+// stubMedia reads whole body like media upload would and returns programmed responses
+type stubMedia struct {
+	bundleRes *androidpublisher.Bundle
+	apkRes    *androidpublisher.Apk
+	err       error
+	body      []byte
+	optCount  int
+	// upload context had deadline set
+	hasDeadline bool
+	fileType    string
+}
+
+func (sm *stubMedia) read(r io.Reader, opts []googleapi.MediaOption) error {
+	sm.optCount = len(opts)
+	b, err := io.ReadAll(r)
+	sm.body = b
+	if err != nil {
+		return err
+	}
+	return sm.err
+}
+
+func (sm *stubMedia) bundle(ctx context.Context, r io.Reader, packageName, editId string, opts ...googleapi.MediaOption) (*androidpublisher.Bundle, error) {
+	_, sm.hasDeadline = ctx.Deadline()
+	if err := sm.read(r, opts); err != nil {
+		return nil, err
+	}
+	return sm.bundleRes, nil
+}
+
+func (sm *stubMedia) apk(ctx context.Context, r io.Reader, packageName, editId string, opts ...googleapi.MediaOption) (*androidpublisher.Apk, error) {
+	if err := sm.read(r, opts); err != nil {
+		return nil, err
+	}
+	return sm.apkRes, nil
+}
+
+func (sm *stubMedia) expansion(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string, opts ...googleapi.MediaOption) error {
+	sm.fileType = fileType
+	return sm.read(r, opts)
+}
+
+func (sm *stubMedia) deobfuscation(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string, opts ...googleapi.MediaOption) error {
+	sm.fileType = fileType
+	return sm.read(r, opts)
+}
+
+type failingReader struct {
+	err error
+}
+
+func (fr *failingReader) Read(p []byte) (int, error) {
+	return 0, fr.err
+}
+
+func TestEditsService(t *testing.T) {
+
+	// call blocking until its context is done for the first given number of calls
+	blocking := func(blocked int, calls *int) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			*calls++
+			if *calls > blocked {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	}
+
+	t.Run("should make call timing out once more", func(t *testing.T) {
+		// Arrange
+		l := &recordingLogger{}
+		es := &editsService{cfg: &serviceConfig{logger: l}}
+		calls := 0
+
+		// Act
+		retried, err := es.timedCall(context.Background(), "commit edit", time.Millisecond, blocking(1, &calls))
+
+		// Assert
+		if err != nil || !retried || calls != 2 {
+			t.Errorf("want commit made twice without error, got %d calls: %v", calls, err)
+		}
+		if !l.has("WARN commit edit timed out after 1ms, trying once more") {
+			t.Errorf("want timeout logged, got %v", l.lines)
+		}
+	})
+
+	t.Run("should fail once repeated call times out too", func(t *testing.T) {
+		// Arrange
+		es := &editsService{cfg: &serviceConfig{logger: &recordingLogger{}}}
+		calls := 0
+
+		// Act
+		_, err := es.timedCall(context.Background(), "validate edit", time.Millisecond, blocking(2, &calls))
+
+		// Assert
+		if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out twice after 1ms") {
+			t.Errorf("want timed out twice error, got: %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("want 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("should report commit uncertain once edit is gone after commit timed out", func(t *testing.T) {
+		// Arrange
+		commits := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			commits++
+			if commits == 1 {
+				// Play finishes the commit after client gave up on it
+				<-r.Context().Done()
+				return
+			}
+			http.Error(w, `{"error": {"code": 404, "message": "edit not found"}}`, http.StatusNotFound)
+		}))
+		defer srv.Close()
+		edits, err := androidpublisher.NewService(context.Background(), option.WithEndpoint(srv.URL), option.WithHTTPClient(srv.Client()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		es := &editsService{edits: edits.Edits, cfg: &serviceConfig{commitTimeout: 50 * time.Millisecond, logger: &recordingLogger{}}}
+
+		// Act
+		err = es.commitEdit(context.Background(), "com.test.app", "1", false)
+
+		// Assert
+		var ce *CommitUncertainError
+		if !errors.As(err, &ce) || ce.EditID != "1" || StatusCode(err) != http.StatusNotFound {
+			t.Fatalf("want CommitUncertainError of edit 1, got: %v", err)
+		}
+		if errors.Is(expiredEdit(&Edit{id: "1"}, err), ErrEditExpired) {
+			t.Error("want uncertain commit not taken for expired edit")
+		}
+		if commits != 2 {
+			t.Errorf("want commit made twice, got %d", commits)
+		}
+	})
+
+	t.Run("should not repeat call failing otherwise", func(t *testing.T) {
+		// Arrange
+		es := &editsService{cfg: &serviceConfig{}}
+		calls := 0
+		refused := &googleapi.Error{Code: http.StatusBadRequest}
+
+		// Act
+		retried, err := es.timedCall(context.Background(), "commit edit", time.Second, func(ctx context.Context) error {
+			calls++
+			return refused
+		})
+
+		// Assert
+		if err != refused || retried || calls != 1 {
+			t.Errorf("want single failed call, got %d calls: %v", calls, err)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package playstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// WithHTTPClient makes Google API and token requests with transport of given client e.g. one with custom TLS
+// config trusting a corporate MITM proxy. Client is authorized on top of its transport, its timeout applies
+// to every request, uploads included.
+func WithHTTPClient(c *http.Client) ServiceOption {
+	return func(cfg *serviceConfig) {
+		cfg.httpClient = c
+	}
+}
+
+// WithProxy sends Google API and token requests through proxy at given URL e.g. http://proxy.corp:3128,
+// instead of one set with HTTPS_PROXY environment variable
+func WithProxy(proxyURL string) ServiceOption {
+	return func(cfg *serviceConfig) {
+		cfg.proxy = proxyURL
+	}
+}
+
+// clientKey tells clients of configuration apart from default ones, empty for defaults
+func (cfg *serviceConfig) clientKey() string {
+	key := ""
+	if cfg.httpClient != nil {
+		key += fmt.Sprintf("+client=%p", cfg.httpClient)
+	}
+	if cfg.proxy != "" {
+		key += "+proxy=" + cfg.proxy
+	}
+	if cfg.qps > 0 {
+		key += fmt.Sprintf("+qps=%g", cfg.qps)
+	}
+	if cfg.httpTracing {
+		key += "+trace"
+	}
+	return key
+}
+
+// newClient returns client authorized with credentials, over configured transport, rate limited and tracing
+// requests if set
+func newClient(ctx context.Context, credentials option.ClientOption, cfg *serviceConfig) (*http.Client, error) {
+	return newScopedClient(ctx, androidpublisher.AndroidpublisherScope, credentials, cfg)
+}
+
+// newScopedClient returns client of newClient authorized for given scope
+func newScopedClient(ctx context.Context, scope string, credentials option.ClientOption, cfg *serviceConfig) (*http.Client, error) {
+	scopes := option.WithScopes(scope)
+	base, err := cfg.baseClient()
+	if err != nil {
+		return nil, err
+	}
+	var client *http.Client
+	if base == nil {
+		if client, _, err = htransport.NewClient(ctx, scopes, credentials); err != nil {
+			return nil, err
+		}
+	} else {
+		// tokens are fetched with client oauth2 finds in context
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, base)
+		rt := base.Transport
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		trans, err := htransport.NewTransport(ctx, rt, scopes, credentials)
+		if err != nil {
+			return nil, err
+		}
+		client = &http.Client{Transport: trans, Timeout: base.Timeout, CheckRedirect: base.CheckRedirect, Jar: base.Jar}
+	}
+	if cfg.qps > 0 {
+		client.Transport = &rateLimitedTransport{base: client.Transport, limiter: newRateLimiter(cfg.qps)}
+	}
+	if cfg.httpTracing {
+		client.Transport = &TracingTransport{Base: client.Transport, Logger: cfg.logger}
+	}
+	return client, nil
+}
+
+// baseClient returns client of configured transport and proxy, nil for defaults
+func (cfg *serviceConfig) baseClient() (*http.Client, error) {
+	if cfg.proxy == "" {
+		return cfg.httpClient, nil
+	}
+	u, err := url.Parse(cfg.proxy)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+		return nil, fmt.Errorf("proxy '%s' must be http, https or socks5 URL e.g. http://proxy:3128", cfg.proxy)
+	}
+	base := &http.Client{}
+	if cfg.httpClient != nil {
+		*base = *cfg.httpClient
+	}
+	var trans *http.Transport
+	switch t := base.Transport.(type) {
+	case nil:
+		trans = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		trans = t.Clone()
+	default:
+		return nil, fmt.Errorf("proxy can't be set on custom transport %T of given http client", t)
+	}
+	trans.Proxy = http.ProxyURL(u)
+	base.Transport = trans
+	return base, nil
+}
@@ -0,0 +1,95 @@
+package playstore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestNewClient(t *testing.T) {
+
+	t.Run("should send requests through proxy", func(t *testing.T) {
+		// Arrange
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+		}))
+		defer proxy.Close()
+		cfg := newServiceConfig(WithProxy(proxy.URL))
+		client, err := newClient(context.Background(), option.WithoutAuthentication(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		res, err := client.Get("http://androidpublisher.example/edits")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if proxied != "http://androidpublisher.example/edits" {
+			t.Errorf("want request through proxy, got '%s'", proxied)
+		}
+	})
+
+	t.Run("should make requests with transport of given client", func(t *testing.T) {
+		// Arrange
+		var used bool
+		base := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			used = true
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+		})}
+		cfg := newServiceConfig(WithHTTPClient(base))
+		client, err := newClient(context.Background(), option.WithoutAuthentication(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		res, err := client.Get("https://androidpublisher.example/edits")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if !used {
+			t.Error("want given client transport used")
+		}
+	})
+
+	t.Run("should refuse invalid proxy", func(t *testing.T) {
+		for _, proxy := range []string{"proxy:3128", "ftp://proxy", "://"} {
+			// Arrange
+			cfg := newServiceConfig(WithProxy(proxy))
+
+			// Act
+			_, err := newClient(context.Background(), option.WithoutAuthentication(), cfg)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), "proxy '"+proxy+"'") {
+				t.Errorf("want '%s' refused, got: %v", proxy, err)
+			}
+		}
+	})
+
+	t.Run("should refuse proxy on custom transport", func(t *testing.T) {
+		// Arrange
+		base := &http.Client{Transport: roundTripFunc(nil)}
+		cfg := newServiceConfig(WithHTTPClient(base), WithProxy("http://proxy:3128"))
+
+		// Act
+		_, err := newClient(context.Background(), option.WithoutAuthentication(), cfg)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "custom transport") {
+			t.Errorf("want proxy refused, got: %v", err)
+		}
+	})
+}
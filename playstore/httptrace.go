@@ -0,0 +1,61 @@
+package playstore
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// query parameters logged with their values, others are redacted as they may identify upload sessions
+var tracedParams = map[string]bool{"uploadType": true, "alt": true, "changesNotSentForReview": true}
+
+// TracingTransport logs method, URL, response status and latency of every request made through it, e.g. to
+// find out where uploads fail in locked-down networks. Bodies and headers, carrying binaries and credentials,
+// are never logged and query values are redacted.
+type TracingTransport struct {
+	Base   http.RoundTripper // http.DefaultTransport if not set
+	Logger Logger            // stderr if not set
+}
+
+func (t *TracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base, l := t.Base, t.Logger
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if l == nil {
+		l = defaultLogger
+	}
+	started := time.Now()
+	res, err := base.RoundTrip(req)
+	took := time.Since(started).Round(time.Millisecond)
+	if err != nil {
+		l.Debugf("HTTP %s %s failed after %s: %v", req.Method, redactedURL(req.URL), took, err)
+		return res, err
+	}
+	l.Debugf("HTTP %s %s -> %s in %s", req.Method, redactedURL(req.URL), res.Status, took)
+	return res, err
+}
+
+// redactedURL returns URL with values of query parameters not in tracedParams redacted
+func redactedURL(u *url.URL) string {
+	r := *u
+	r.User = nil
+	q := r.Query()
+	for k, vs := range q {
+		if tracedParams[k] {
+			continue
+		}
+		for i := range vs {
+			vs[i] = "REDACTED"
+		}
+	}
+	r.RawQuery = q.Encode()
+	return r.String()
+}
+
+// WithHTTPTracing logs every Google API request with TracingTransport to service logger
+func WithHTTPTracing() ServiceOption {
+	return func(c *serviceConfig) {
+		c.httpTracing = true
+	}
+}
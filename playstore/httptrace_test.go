@@ -0,0 +1,69 @@
+package playstore
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc RoundTripper of a func
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTracingTransport(t *testing.T) {
+
+	t.Run("should log request with redacted query and response status", func(t *testing.T) {
+		// Arrange
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+		l := &recordingLogger{}
+		client := &http.Client{Transport: &TracingTransport{Logger: l}}
+		req, _ := http.NewRequest(http.MethodPut, srv.URL+"/upload?uploadType=resumable&upload_id=secret", strings.NewReader("binary"))
+		req.Header.Set("Authorization", "Bearer token")
+
+		// Act
+		res, err := client.Do(req)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		want := "DEBUG HTTP PUT " + srv.URL + "/upload?uploadType=resumable&upload_id=REDACTED -> 404 Not Found in "
+		if !l.has(want) {
+			t.Errorf("want '%s' logged, got %v", want, l.lines)
+		}
+		for _, line := range l.lines {
+			if strings.Contains(line, "secret") || strings.Contains(line, "token") || strings.Contains(line, "binary") {
+				t.Errorf("want no secrets or body logged, got '%s'", line)
+			}
+		}
+	})
+
+	t.Run("should log failed request", func(t *testing.T) {
+		// Arrange
+		l := &recordingLogger{}
+		tt := &TracingTransport{Logger: l, Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("proxy refused connection")
+		})}
+		req, _ := http.NewRequest(http.MethodGet, "https://androidpublisher.googleapis.com/edits", nil)
+
+		// Act
+		_, err := tt.RoundTrip(req)
+
+		// Assert
+		if err == nil {
+			t.Fatal("want error, got nil")
+		}
+		if !l.has("DEBUG HTTP GET https://androidpublisher.googleapis.com/edits failed after ") || !strings.Contains(l.lines[0], "proxy refused connection") {
+			t.Errorf("want failure logged, got %v", l.lines)
+		}
+	})
+}
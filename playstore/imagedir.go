@@ -0,0 +1,289 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// LocaleImages images of one type for a locale, in upload order
+type LocaleImages struct {
+	Locale    string
+	ImageType string
+	Files     []string
+}
+
+// ImageReport images pushed to Play by locale and image type
+type ImageReport struct {
+	Uploaded  map[string]map[string]int `json:"uploaded"`  // locale -> image type -> images uploaded
+	Deleted   map[string]map[string]int `json:"deleted"`   // locale -> image type -> images replaced
+	Unchanged map[string]map[string]int `json:"unchanged"` // locale -> image type -> images already on Play
+}
+
+func newImageReport() *ImageReport {
+	return &ImageReport{
+		Uploaded:  make(map[string]map[string]int),
+		Deleted:   make(map[string]map[string]int),
+		Unchanged: make(map[string]map[string]int),
+	}
+}
+
+// fastlane style images directory within metadata <locale> directory
+const metadataImagesDir = "images"
+
+// ReadImageDir reads <dir>/<locale>/<imageType>/*.png|jpg layout, inferring image type from directory name
+// e.g. images/en-US/phoneScreenshots/1.png. Single image types may also be given as <dir>/<locale>/<imageType>.png
+// e.g. images/en-US/icon.png. Files are ordered by name and validated against Play requirements.
+func ReadImageDir(fs afero.Fs, dir string) ([]LocaleImages, error) {
+	locales, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading images directory '%s': %w", dir, err)
+	}
+
+	all := make([]LocaleImages, 0)
+	for _, l := range locales {
+		if !l.IsDir() {
+			continue
+		}
+		images, err := readLocaleImages(fs, filepath.Join(dir, l.Name()), l.Name())
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, images...)
+	}
+	return all, nil
+}
+
+// ReadMetadataImages reads images of fastlane style metadata directory, laid out as
+// <metadataDir>/<locale>/images/ with the same structure as ReadImageDir locale directories
+func ReadMetadataImages(fs afero.Fs, metadataDir string) ([]LocaleImages, error) {
+	locales, err := afero.ReadDir(fs, metadataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading metadata directory '%s': %w", metadataDir, err)
+	}
+
+	all := make([]LocaleImages, 0)
+	for _, l := range locales {
+		dir := filepath.Join(metadataDir, l.Name(), metadataImagesDir)
+		if ok, _ := afero.DirExists(fs, dir); !l.IsDir() || !ok {
+			continue
+		}
+		images, err := readLocaleImages(fs, dir, l.Name())
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, images...)
+	}
+	return all, nil
+}
+
+// readLocaleImages reads <imageType>/ directories and <imageType>.png files of a single locale
+func readLocaleImages(fs afero.Fs, dir, locale string) ([]LocaleImages, error) {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string]*LocaleImages)
+	types := make([]string, 0)
+	add := func(imageType, path string) error {
+		if err := validateImage(fs, path, imageType); err != nil {
+			return err
+		}
+		li, ok := byType[imageType]
+		if !ok {
+			li = &LocaleImages{Locale: locale, ImageType: imageType}
+			byType[imageType] = li
+			types = append(types, imageType)
+		}
+		li.Files = append(li.Files, path)
+		return nil
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			imageType := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+			if _, known := imageSpecs[imageType]; !known || !isImageFile(e.Name()) {
+				continue
+			}
+			if err := add(imageType, filepath.Join(dir, e.Name())); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := validateImageType(e.Name()); err != nil {
+			return nil, fmt.Errorf("'%s' in '%s': %w", e.Name(), dir, err)
+		}
+		files, err := afero.ReadDir(fs, filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if f.IsDir() || !isImageFile(f.Name()) {
+				continue
+			}
+			if err := add(e.Name(), filepath.Join(dir, e.Name(), f.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Strings(types)
+	images := make([]LocaleImages, 0, len(types))
+	for _, t := range types {
+		li := byType[t]
+		if err := validateImageCount(li.ImageType, len(li.Files)); err != nil {
+			return nil, fmt.Errorf("'%s' locale: %w", li.Locale, err)
+		}
+		sort.Strings(li.Files)
+		images = append(images, *li)
+	}
+	return images, nil
+}
+
+// isImageFile checks file extension is one Play accepts
+func isImageFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+/**
+ * PushImages uploads images directory to Play in a single edit
+ *
+ * imagesDir - <locale>/<imageType>/*.png directory layout, see ReadImageDir
+ *
+ * Images already on Play for every locale and type present locally are replaced, other types are left as is.
+ * Types whose images on Play already match local files, in order, are left unchanged.
+ */
+func PushImages(ctx context.Context, gs IGService, fs afero.Fs, packageName, imagesDir string) (*ImageReport, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	images, err := ReadImageDir(fs, imagesDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images found in '%s'", imagesDir)
+	}
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := pushImages(ctx, gs, fs, name, edit, images, false)
+	if err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if len(report.Uploaded) == 0 {
+		gs.deleteEdit(ctx, name, edit)
+		return report, nil
+	}
+
+	if err := gs.validateEdit(ctx, name, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if err := gs.commitEdit(ctx, name, edit, false); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	return report, nil
+}
+
+// pushImages replaces images of every locale and type within edit, unless Play images match local files.
+// dryRun only reports images that would be uploaded.
+func pushImages(ctx context.Context, gs IGService, fs afero.Fs, packageName, editId string, images []LocaleImages, dryRun bool) (*ImageReport, error) {
+	report := newImageReport()
+	for _, li := range images {
+		same, err := sameImages(ctx, gs, fs, packageName, editId, li)
+		if err != nil {
+			return nil, err
+		}
+		if same {
+			addCount(report.Unchanged, li.Locale, li.ImageType, len(li.Files))
+			continue
+		}
+		if dryRun {
+			addCount(report.Uploaded, li.Locale, li.ImageType, len(li.Files))
+			continue
+		}
+		deleted, err := gs.deleteAllImages(ctx, packageName, editId, li.Locale, li.ImageType)
+		if err != nil {
+			return nil, fmt.Errorf("failed deleting '%s' '%s' images: %w", li.Locale, li.ImageType, err)
+		}
+		if err := uploadImages(ctx, gs, fs, packageName, editId, li); err != nil {
+			return nil, err
+		}
+		addCount(report.Deleted, li.Locale, li.ImageType, deleted)
+		addCount(report.Uploaded, li.Locale, li.ImageType, len(li.Files))
+	}
+	return report, nil
+}
+
+// sameImages checks images on Play are the local files in the same order, compared by sha256
+func sameImages(ctx context.Context, gs IGService, fs afero.Fs, packageName, editId string, li LocaleImages) (bool, error) {
+	remote, err := gs.listImages(ctx, packageName, editId, li.Locale, li.ImageType)
+	if err != nil {
+		return false, fmt.Errorf("failed listing '%s' '%s' images: %w", li.Locale, li.ImageType, err)
+	}
+	if len(remote) != len(li.Files) {
+		return false, nil
+	}
+	for i, path := range li.Files {
+		f, err := fs.Open(path)
+		if err != nil {
+			return false, err
+		}
+		h, err := fileHashes(f)
+		f.Close()
+		if err != nil {
+			return false, fmt.Errorf("failed hashing '%s': %w", path, err)
+		}
+		if !strings.EqualFold(h.Sha256, remote[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// uploadImages uploads every file of locale images in order
+func uploadImages(ctx context.Context, us IImagesService, fs afero.Fs, packageName, editId string, li LocaleImages) error {
+	for _, path := range li.Files {
+		f, err := fs.Open(path)
+		if err != nil {
+			return err
+		}
+		err = us.uploadImage(ctx, f, packageName, editId, li.Locale, li.ImageType)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed uploading '%s' image '%s': %w", li.ImageType, path, err)
+		}
+	}
+	return nil
+}
+
+// addCount adds n images to locale and image type count
+func addCount(counts map[string]map[string]int, locale, imageType string, n int) {
+	if n == 0 {
+		return
+	}
+	if counts[locale] == nil {
+		counts[locale] = make(map[string]int)
+	}
+	counts[locale][imageType] += n
+}
@@ -0,0 +1,123 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestPushImages(t *testing.T) {
+
+	t.Run("should upload images with type inferred from directory", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "images/en-US/phoneScreenshots/1.png", 1080, 1920)
+		createTestImage(t, fs, "images/en-US/phoneScreenshots/2.png", 1080, 1920)
+		createTestImage(t, fs, "images/de-DE/tvBanner/banner.png", 1280, 720)
+		createTestFile(t, fs, "images/en-US/phoneScreenshots/notes.txt", 10)
+		gs := &mockGService{}
+
+		// Act
+		r, err := PushImages(context.Background(), gs, fs, "com.test.app", "images")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		want := map[string]map[string]int{"en-US": {ImageTypePhoneScreenshots: 2}, "de-DE": {ImageTypeTvBanner: 1}}
+		if !reflect.DeepEqual(r.Uploaded, want) {
+			t.Errorf("want %v uploaded, got %v", want, r.Uploaded)
+		}
+		if len(gs.uploadedImages) != 3 || len(gs.deletedImages) != 2 {
+			t.Errorf("want 3 uploads replacing 2 image types, got %v uploads %v deletes", gs.uploadedImages, gs.deletedImages)
+		}
+		if gs.createEditCount != 1 || gs.commitEditCount != 1 {
+			t.Errorf("want single committed edit, got %d created %d committed", gs.createEditCount, gs.commitEditCount)
+		}
+	})
+
+	t.Run("should fail on unknown image type directory before creating edit", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "images/en-US/autoScreenshots/1.png", 800, 480)
+		gs := &mockGService{}
+
+		// Act
+		_, err := PushImages(context.Background(), gs, fs, "com.test.app", "images")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.createEditCount != 0 {
+			t.Errorf("want no edit created, got %d", gs.createEditCount)
+		}
+	})
+
+	t.Run("should fail on image not matching its type", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "images/en-US/icon/icon.png", 256, 256)
+
+		// Act
+		_, err := PushImages(context.Background(), &mockGService{}, fs, "com.test.app", "images")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should leave image types matching Play unchanged", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "images/en-US/icon.png", 512, 512)
+		createTestImage(t, fs, "images/en-US/phoneScreenshots/1.png", 1080, 1920)
+		f, _ := fs.Open("images/en-US/icon.png")
+		h, _ := fileHashes(f)
+		f.Close()
+		gs := &mockGService{images: map[string][]string{"en-US/" + ImageTypeIcon: {h.Sha256}}}
+
+		// Act
+		r, err := PushImages(context.Background(), gs, fs, "com.test.app", "images")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(gs.uploadedImages, []string{"en-US/" + ImageTypePhoneScreenshots}) {
+			t.Errorf("want only screenshots uploaded, got %v", gs.uploadedImages)
+		}
+		if r.Unchanged["en-US"][ImageTypeIcon] != 1 {
+			t.Errorf("want icon unchanged, got %v", r.Unchanged)
+		}
+	})
+}
+
+func TestReadMetadataImages(t *testing.T) {
+
+	t.Run("should read images directory of every metadata locale", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "metadata/en-US/title.txt", []byte("Sample"), 0o644)
+		createTestImage(t, fs, "metadata/en-US/images/featureGraphic.png", 1024, 500)
+		createTestImage(t, fs, "metadata/lt-LT/images/phoneScreenshots/1.png", 1080, 1920)
+
+		// Act
+		images, err := ReadMetadataImages(fs, "metadata")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		want := []LocaleImages{
+			{Locale: "en-US", ImageType: ImageTypeFeatureGraphic, Files: []string{"metadata/en-US/images/featureGraphic.png"}},
+			{Locale: "lt-LT", ImageType: ImageTypePhoneScreenshots, Files: []string{"metadata/lt-LT/images/phoneScreenshots/1.png"}},
+		}
+		if !reflect.DeepEqual(images, want) {
+			t.Errorf("want %v, got %v", want, images)
+		}
+	})
+}
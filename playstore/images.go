@@ -0,0 +1,126 @@
+package playstore
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// https://developers.google.com/android-publisher/api-ref/rest/v3/AppImageType
+	ImageTypePhoneScreenshots     = "phoneScreenshots"
+	ImageTypeSevenInchScreenshots = "sevenInchScreenshots"
+	ImageTypeTenInchScreenshots   = "tenInchScreenshots"
+	ImageTypeTvScreenshots        = "tvScreenshots"
+	ImageTypeWearScreenshots      = "wearScreenshots"
+	ImageTypeIcon                 = "icon"
+	ImageTypeFeatureGraphic       = "featureGraphic"
+	ImageTypeTvBanner             = "tvBanner"
+
+	imageMaxScreenshots = 8
+)
+
+// imageSpec holds Play Console requirements for a single image type
+//
+// Android Auto has no image types of its own, Auto listings reuse phone screenshots
+type imageSpec struct {
+	maxCount int
+	maxBytes int64
+	formats  []string // decoded image formats as reported by image.DecodeConfig
+	// exact dimensions, 0 if not fixed
+	width, height int
+	// side length bounds for screenshots, 0 if not set
+	minSide, maxSide int
+	// max long side to short side ratio, 0 if any
+	maxAspect float64
+	// require square images
+	square bool
+}
+
+var imageSpecs = map[string]imageSpec{
+	ImageTypePhoneScreenshots:     {maxCount: imageMaxScreenshots, maxBytes: 8 << 20, formats: []string{"png", "jpeg"}, minSide: 320, maxSide: 3840, maxAspect: 2},
+	ImageTypeSevenInchScreenshots: {maxCount: imageMaxScreenshots, maxBytes: 8 << 20, formats: []string{"png", "jpeg"}, minSide: 320, maxSide: 3840, maxAspect: 2},
+	ImageTypeTenInchScreenshots:   {maxCount: imageMaxScreenshots, maxBytes: 8 << 20, formats: []string{"png", "jpeg"}, minSide: 1080, maxSide: 7680, maxAspect: 2},
+	ImageTypeTvScreenshots:        {maxCount: imageMaxScreenshots, maxBytes: 8 << 20, formats: []string{"png", "jpeg"}, minSide: 720, maxSide: 3840, maxAspect: 16.0 / 9.0},
+	ImageTypeWearScreenshots:      {maxCount: imageMaxScreenshots, maxBytes: 8 << 20, formats: []string{"png", "jpeg"}, minSide: 384, maxSide: 3840, square: true},
+	ImageTypeIcon:                 {maxCount: 1, maxBytes: 1 << 20, formats: []string{"png"}, width: 512, height: 512},
+	ImageTypeFeatureGraphic:       {maxCount: 1, maxBytes: 15 << 20, formats: []string{"png", "jpeg"}, width: 1024, height: 500},
+	ImageTypeTvBanner:             {maxCount: 1, maxBytes: 15 << 20, formats: []string{"png", "jpeg"}, width: 1280, height: 720},
+}
+
+// validateImageType returns error if image type is not known to Play
+func validateImageType(imageType string) error {
+	if _, ok := imageSpecs[imageType]; !ok {
+		return fmt.Errorf("image type '%s' not supported", imageType)
+	}
+	return nil
+}
+
+// validateImageCount returns error when more images provided than Play allows for given type
+func validateImageCount(imageType string, count int) error {
+	spec, ok := imageSpecs[imageType]
+	if !ok {
+		return fmt.Errorf("image type '%s' not supported", imageType)
+	}
+	if count > spec.maxCount {
+		return fmt.Errorf("%d '%s' images provided, at most %d allowed", count, imageType, spec.maxCount)
+	}
+	return nil
+}
+
+// validateImage checks file format, size and dimensions are acceptable for a given image type
+func validateImage(fs afero.Fs, filePath, imageType string) error {
+	spec, ok := imageSpecs[imageType]
+	if !ok {
+		return fmt.Errorf("image type '%s' not supported", imageType)
+	}
+
+	s, err := fs.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	if s.Size() > spec.maxBytes {
+		return fmt.Errorf("'%s' image '%s' is %d bytes, at most %d allowed", imageType, filePath, s.Size(), spec.maxBytes)
+	}
+
+	f, err := fs.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return fmt.Errorf("failed reading image '%s': %w", filePath, err)
+	}
+	if !contains(spec.formats, format) {
+		return fmt.Errorf("'%s' image '%s' is %s, only %s allowed", imageType, filePath, format, strings.Join(spec.formats, ", "))
+	}
+
+	w, h := cfg.Width, cfg.Height
+	if spec.width != 0 && (w != spec.width || h != spec.height) {
+		return fmt.Errorf("'%s' image '%s' is %dx%d, must be %dx%d", imageType, filePath, w, h, spec.width, spec.height)
+	}
+
+	short, long := w, h
+	if short > long {
+		short, long = long, short
+	}
+	if spec.minSide != 0 && short < spec.minSide {
+		return fmt.Errorf("'%s' image '%s' is %dx%d, sides must be at least %dpx", imageType, filePath, w, h, spec.minSide)
+	}
+	if spec.maxSide != 0 && long > spec.maxSide {
+		return fmt.Errorf("'%s' image '%s' is %dx%d, sides must be at most %dpx", imageType, filePath, w, h, spec.maxSide)
+	}
+	if spec.square && w != h {
+		return fmt.Errorf("'%s' image '%s' is %dx%d, must be square", imageType, filePath, w, h)
+	}
+	if spec.maxAspect != 0 && float64(long)/float64(short) > spec.maxAspect+0.01 {
+		return fmt.Errorf("'%s' image '%s' is %dx%d, aspect ratio must not exceed %.2f", imageType, filePath, w, h, spec.maxAspect)
+	}
+	return nil
+}
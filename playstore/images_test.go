@@ -0,0 +1,106 @@
+package playstore
+
+import (
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestValidateImage(t *testing.T) {
+
+	t.Run("should accept tv banner of expected size", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "banner.png", 1280, 720)
+
+		// Act
+		err := validateImage(fs, "banner.png", ImageTypeTvBanner)
+
+		// Assert
+		if err != nil {
+			t.Errorf("want no error, got: %v", err)
+		}
+	})
+
+	t.Run("should reject tv banner of wrong size", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "banner.png", 1024, 500)
+
+		// Act
+		err := validateImage(fs, "banner.png", ImageTypeTvBanner)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should reject tv screenshot with non tv aspect ratio", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "tv.png", 1920, 800)
+
+		// Act
+		err := validateImage(fs, "tv.png", ImageTypeTvScreenshots)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should reject non square wear screenshot", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "wear.png", 400, 384)
+
+		// Act
+		err := validateImage(fs, "wear.png", ImageTypeWearScreenshots)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should reject unknown image type", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestImage(t, fs, "auto.png", 800, 480)
+
+		// Act
+		err := validateImage(fs, "auto.png", "autoScreenshots")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should reject more than one tv banner", func(t *testing.T) {
+		// Act
+		err := validateImageCount(ImageTypeTvBanner, 2)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
+
+func createTestImage(t testing.TB, fs afero.Fs, file string, width, height int) {
+	t.Helper()
+
+	f, err := fs.Create(file)
+	if err != nil {
+		t.Fatalf("failed creating '%s' test image: %s", file, err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("failed encoding '%s' test image: %s", file, err)
+	}
+}
@@ -0,0 +1,50 @@
+package playstore
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// IntegrityError is returned when digest Play reports for uploaded binary doesn't match local file
+type IntegrityError struct {
+	Path         string
+	LocalSha256  string
+	RemoteSha256 string
+	Size         int64 // local file size in bytes
+	BytesSent    int64 // bytes read for the last upload attempt
+	Attempts     int
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("failed integrity verification of '%s' with local file hash '%s' and remote '%s', %d of %d bytes sent after %d attempt(s)",
+		e.Path, e.LocalSha256, e.RemoteSha256, e.BytesSent, e.Size, e.Attempts)
+}
+
+func (e *IntegrityError) Is(target error) bool {
+	return target == ErrIntegrityMismatch
+}
+
+// WithIntegrityRetry uploads binary once more when Play reports different sha256 than the local file has,
+// before failing. Binary of the failed attempt stays in the edit, but isn't released to any track.
+func WithIntegrityRetry() Option {
+	return func(p *publish) {
+		p.integrityRetry = true
+	}
+}
+
+// countingReader counts bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(b []byte) (int, error) {
+	n, err := cr.r.Read(b)
+	atomic.AddInt64(&cr.n, int64(n))
+	return n, err
+}
+
+func (cr *countingReader) count() int64 {
+	return atomic.LoadInt64(&cr.n)
+}
@@ -0,0 +1,165 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/afero"
+)
+
+// files of listing fields within <locale> directory
+func (l *Listing) files() map[string]*string {
+	return map[string]*string{
+		listingTitleFile:            &l.Title,
+		listingShortDescriptionFile: &l.ShortDescription,
+		listingFullDescriptionFile:  &l.FullDescription,
+		listingVideoFile:            &l.Video,
+	}
+}
+
+const (
+	// fastlane style metadata files within <locale> directory
+	listingTitleFile            = "title.txt"
+	listingShortDescriptionFile = "short_description.txt"
+	listingFullDescriptionFile  = "full_description.txt"
+	listingVideoFile            = "video.txt"
+
+	// Play limits for listing fields
+	listingTitleMaxLength            = 30
+	listingShortDescriptionMaxLength = 80
+	listingFullDescriptionMaxLength  = 4000
+)
+
+// Listing store listing of a single locale
+type Listing struct {
+	Locale           string `json:"locale"`
+	Title            string `json:"title"`
+	ShortDescription string `json:"shortDescription"`
+	FullDescription  string `json:"fullDescription"`
+	Video            string `json:"video,omitempty"`
+}
+
+func (l Listing) validate() error {
+	if strings.TrimSpace(l.Locale) == "" {
+		return fmt.Errorf("listing locale must not be empty")
+	}
+	if n := utf8.RuneCountInString(l.Title); n > listingTitleMaxLength {
+		return fmt.Errorf("'%s' listing title is %d characters, at most %d allowed", l.Locale, n, listingTitleMaxLength)
+	}
+	if n := utf8.RuneCountInString(l.ShortDescription); n > listingShortDescriptionMaxLength {
+		return fmt.Errorf("'%s' listing short description is %d characters, at most %d allowed", l.Locale, n, listingShortDescriptionMaxLength)
+	}
+	if n := utf8.RuneCountInString(l.FullDescription); n > listingFullDescriptionMaxLength {
+		return fmt.Errorf("'%s' listing full description is %d characters, at most %d allowed", l.Locale, n, listingFullDescriptionMaxLength)
+	}
+	return nil
+}
+
+// ReadListings reads fastlane style metadata directory with <locale>/title.txt, short_description.txt,
+// full_description.txt and video.txt files. Locale directories without any of these files are ignored.
+func ReadListings(fs afero.Fs, dir string) ([]Listing, error) {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading metadata directory '%s': %w", dir, err)
+	}
+
+	listings := make([]Listing, 0)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		l := Listing{Locale: e.Name()}
+		found := false
+		for file, field := range l.files() {
+			path := filepath.Join(dir, e.Name(), file)
+			if ok, _ := afero.Exists(fs, path); !ok {
+				continue
+			}
+			b, err := afero.ReadFile(fs, path)
+			if err != nil {
+				return nil, err
+			}
+			*field = strings.TrimSpace(string(b))
+			found = true
+		}
+		if !found {
+			continue
+		}
+		if err := l.validate(); err != nil {
+			return nil, err
+		}
+		listings = append(listings, l)
+	}
+
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].Locale < listings[j].Locale
+	})
+	return listings, nil
+}
+
+// WriteListings writes listings to fastlane style metadata directory readable by ReadListings.
+// Files of empty fields are removed, so directory mirrors listings exactly.
+func WriteListings(fs afero.Fs, dir string, listings []Listing) error {
+	for _, l := range listings {
+		if err := l.validate(); err != nil {
+			return err
+		}
+		localeDir := filepath.Join(dir, l.Locale)
+		if err := fs.MkdirAll(localeDir, 0o755); err != nil {
+			return fmt.Errorf("failed creating '%s' directory: %w", localeDir, err)
+		}
+		for file, field := range l.files() {
+			path := filepath.Join(localeDir, file)
+			if *field == "" {
+				if err := fs.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return fmt.Errorf("failed removing '%s': %w", path, err)
+				}
+				continue
+			}
+			if err := afero.WriteFile(fs, path, []byte(*field+"\n"), 0o644); err != nil {
+				return fmt.Errorf("failed writing '%s': %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+/**
+ * PullListings downloads Play store listings of every locale to metadata directory, see WriteListings
+ *
+ * returns listings written
+ */
+func PullListings(ctx context.Context, gs IGService, fs afero.Fs, packageName, metadataDir string) ([]Listing, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// nothing is changed, edit is only needed for reading
+	defer gs.deleteEdit(ctx, name, edit)
+
+	listings, err := gs.listListings(ctx, name, edit)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].Locale < listings[j].Locale
+	})
+	if err := WriteListings(fs, metadataDir, listings); err != nil {
+		return nil, err
+	}
+	return listings, nil
+}
@@ -0,0 +1,57 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestPullListings(t *testing.T) {
+
+	t.Run("should write listings readable as metadata directory", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		gs := &mockGService{listings: []Listing{
+			{Locale: "lt-LT", Title: "Pavyzdys"},
+			{Locale: "en-US", Title: "Sample", ShortDescription: "Short", Video: "https://youtu.be/x"},
+		}}
+
+		// Act
+		pulled, err := PullListings(context.Background(), gs, fs, "com.test.app", "metadata")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		read, err := ReadListings(fs, "metadata")
+		if err != nil {
+			t.Fatalf("want no error reading pulled metadata, got: %v", err)
+		}
+		if !reflect.DeepEqual(read, pulled) {
+			t.Errorf("want '%v' read back, got '%v'", pulled, read)
+		}
+		if gs.deleteEditCount != 1 || gs.commitEditCount != 0 {
+			t.Errorf("want edit deleted without commit, got %d deletes and %d commits", gs.deleteEditCount, gs.commitEditCount)
+		}
+	})
+
+	t.Run("should remove files of fields empty on Play", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "metadata/en-US/video.txt", []byte("https://youtu.be/old"), 0o644)
+		gs := &mockGService{listings: []Listing{{Locale: "en-US", Title: "Sample"}}}
+
+		// Act
+		_, err := PullListings(context.Background(), gs, fs, "com.test.app", "metadata")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if ok, _ := afero.Exists(fs, "metadata/en-US/video.txt"); ok {
+			t.Error("want stale video.txt removed, got it kept")
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package playstore
+
+import (
+	"log"
+	"os"
+)
+
+// Logger receives publish progress and diagnostics, debug messages are only sent in verbose mode
+type Logger interface {
+	Debugf(format string, v ...any)
+	Infof(format string, v ...any)
+	Warnf(format string, v ...any)
+	Errorf(format string, v ...any)
+}
+
+// stdLogger default logger writing to stderr with standard log formatting
+type stdLogger struct {
+	l *log.Logger
+}
+
+// NewStdLogger returns logger writing to stderr, the one used when none is set
+func NewStdLogger() Logger {
+	return &stdLogger{l: log.New(os.Stderr, "", log.LstdFlags)}
+}
+
+func (sl *stdLogger) Debugf(format string, v ...any) {
+	sl.l.Printf(format, v...)
+}
+
+func (sl *stdLogger) Infof(format string, v ...any) {
+	sl.l.Printf(format, v...)
+}
+
+func (sl *stdLogger) Warnf(format string, v ...any) {
+	sl.l.Printf("WARNING: "+format, v...)
+}
+
+func (sl *stdLogger) Errorf(format string, v ...any) {
+	sl.l.Printf("ERROR: "+format, v...)
+}
+
+// WithLogger sends publish logging to given logger instead of stderr
+func WithLogger(l Logger) Option {
+	return func(p *publish) {
+		p.logger = l
+	}
+}
+
+// WithServiceLogger sends Google API service logging e.g. retries to given logger instead of stderr
+func WithServiceLogger(l Logger) ServiceOption {
+	return func(c *serviceConfig) {
+		c.logger = l
+	}
+}
+
+// defaultLogger used by publish when no logger is set
+var defaultLogger = NewStdLogger()
+
+// parallel reports if files are uploaded at once, lines logged about them are prefixed with file path then
+func (p *publish) parallel() bool {
+	return p.concurrency.Uploads > 1 || p.concurrency.Mappings > 1
+}
+
+// fileDebugf logs debug message about upload of file, prefixed with its path when uploads run in parallel
+// so their interleaved lines can be told apart and grepped
+func (p *publish) fileDebugf(filePath, format string, v ...any) {
+	if !p.parallel() {
+		p.Debugf(format, v...)
+		return
+	}
+	p.Debugf("[%s] "+format, append([]any{filePath}, v...)...)
+}
+
+// fileInfof logs message about upload of file, prefixed as fileDebugf does
+func (p *publish) fileInfof(filePath, format string, v ...any) {
+	if !p.parallel() {
+		p.Infof(format, v...)
+		return
+	}
+	p.Infof("[%s] "+format, append([]any{filePath}, v...)...)
+}
+
+// log returns configured logger or stderr one if none set
+func (p *publish) log() Logger {
+	if p.logger == nil {
+		return defaultLogger
+	}
+	return p.logger
+}
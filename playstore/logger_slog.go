@@ -0,0 +1,35 @@
+//go:build go1.21
+
+package playstore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// slogLogger adapts slog.Logger to Logger, formatting messages before logging them at matching level
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// SlogLogger returns Logger writing to given slog.Logger
+func SlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+func (sl *slogLogger) Debugf(format string, v ...any) {
+	sl.l.Log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, v...))
+}
+
+func (sl *slogLogger) Infof(format string, v ...any) {
+	sl.l.Log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, v...))
+}
+
+func (sl *slogLogger) Warnf(format string, v ...any) {
+	sl.l.Log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, v...))
+}
+
+func (sl *slogLogger) Errorf(format string, v ...any) {
+	sl.l.Log(context.Background(), slog.LevelError, fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,27 @@
+//go:build go1.21
+
+package playstore
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+
+	t.Run("should log formatted message at matching level", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		l := SlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+		// Act
+		l.Warnf("edit '%s' expires soon", "1")
+
+		// Assert
+		if !strings.Contains(buf.String(), "level=WARN") || !strings.Contains(buf.String(), "edit '1' expires soon") {
+			t.Errorf("want warning with formatted message, got '%s'", buf.String())
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package playstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestLogger(t *testing.T) {
+
+	t.Run("should send publish logging to provided logger", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		l := &recordingLogger{}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithLogger(l))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), &mockGService{}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if !l.has("INFO All files uploaded successfully.") {
+			t.Errorf("want success logged, got %v", l.lines)
+		}
+		if l.has("DEBUG") {
+			t.Errorf("want no debug messages without verbose, got %v", l.lines)
+		}
+	})
+
+	t.Run("should prefix lines about files with their path when uploading in parallel", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "arm.apk", 10)
+		createTestFile(t, fs, "x86.apk", 10)
+		createTestFile(t, fs, "x86.txt", 10)
+		l := &recordingLogger{}
+		bins := []binary{Binary("arm.apk"), BinaryWithMapping("x86.apk", "x86.txt")}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", bins, true, true,
+			WithLogger(l), WithMaxParallelUploads(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), &mockVersionedGService{}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		for _, prefix := range []string{"DEBUG [arm.apk] File successfully uploaded", "DEBUG [x86.apk] File successfully uploaded",
+			"DEBUG [x86.txt] Mapping", "INFO [arm.apk] 10 B/10 B", "INFO [x86.apk] 10 B/10 B"} {
+			if !l.has(prefix) {
+				t.Errorf("want '%s' logged, got %v", prefix, l.lines)
+			}
+		}
+	})
+
+	t.Run("should return error instead of exiting when file size can not be read", func(t *testing.T) {
+		// Arrange
+		p := &publish{fs: afero.NewMemMapFs()}
+
+		// Act
+		_, err := p.fileSize("missing.aab")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
+
+// recordingLogger keeps every logged line prefixed with its level
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (rl *recordingLogger) record(level, format string, v ...any) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.lines = append(rl.lines, level+" "+fmt.Sprintf(format, v...))
+}
+
+func (rl *recordingLogger) has(prefix string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for _, l := range rl.lines {
+		if strings.HasPrefix(l, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rl *recordingLogger) Debugf(format string, v ...any) { rl.record("DEBUG", format, v...) }
+func (rl *recordingLogger) Infof(format string, v ...any)  { rl.record("INFO", format, v...) }
+func (rl *recordingLogger) Warnf(format string, v ...any)  { rl.record("WARN", format, v...) }
+func (rl *recordingLogger) Errorf(format string, v ...any) { rl.record("ERROR", format, v...) }
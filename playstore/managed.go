@@ -0,0 +1,81 @@
+package playstore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// managed publishing settings, Play API doesn't expose which one app uses
+const (
+	ManagedPublishingOn  = "on"
+	ManagedPublishingOff = "off"
+)
+
+// ManagedPublishingError commit was refused because app managed publishing differs from the expected one
+type ManagedPublishingError struct {
+	Expected string
+	Err      error
+}
+
+func (e *ManagedPublishingError) Error() string {
+	return fmt.Sprintf("managed publishing is on in Play Console, but expected '%s': %v", e.Expected, e.Err)
+}
+
+func (e *ManagedPublishingError) Unwrap() error {
+	return e.Err
+}
+
+// WithManagedPublishing declares app managed publishing setting, 'on' or 'off'. With it on, committed
+// changes are reported as submitted for review rather than live. Play has no API for the setting, so it
+// can only be detected from commit errors: strict fails with ManagedPublishingError when such error
+// contradicts the declared setting, instead of the plain commit error.
+func WithManagedPublishing(setting string, strict bool) Option {
+	return func(p *publish) {
+		p.managedPublishing = setting
+		p.managedPublishingStrict = strict
+	}
+}
+
+func validateManagedPublishing(setting string) error {
+	switch setting {
+	case "", ManagedPublishingOn, ManagedPublishingOff:
+		return nil
+	}
+	return fmt.Errorf("managed publishing setting '%s' not supported, use '%s' or '%s'", setting, ManagedPublishingOn, ManagedPublishingOff)
+}
+
+// isManagedPublishingErr checks whether Play refused commit because of managed publishing
+func isManagedPublishingErr(err error) bool {
+	var gErr *googleapi.Error
+	if !errors.As(err, &gErr) || (gErr.Code != http.StatusBadRequest && gErr.Code != http.StatusForbidden) {
+		return false
+	}
+	return strings.Contains(strings.ToLower(gErr.Message), "managed publishing")
+}
+
+// managedCommitErr explains commit error caused by managed publishing
+func (p *publish) managedCommitErr(err error) error {
+	if !isManagedPublishingErr(err) {
+		return err
+	}
+	if p.managedPublishingStrict && p.managedPublishing == ManagedPublishingOff {
+		return &ManagedPublishingError{Expected: ManagedPublishingOff, Err: err}
+	}
+	return fmt.Errorf("commit refused by managed publishing, publish or discard pending changes in Play Console first: %w", err)
+}
+
+// reportCommitted tells where committed changes went, depending on managed publishing setting
+func (p *publish) reportCommitted(sentForReview bool) {
+	switch {
+	case p.managedPublishing == ManagedPublishingOn && sentForReview:
+		p.Infof("Changes submitted for review, not live: managed publishing is on, publish them in Play Console once approved.")
+	case p.managedPublishing == ManagedPublishingOn:
+		p.Infof("Edit committed with changes not sent for review, not live: send them for review and publish in Play Console.")
+	case !sentForReview:
+		p.Infof("Edit committed with changes not sent for review. Send them for review in Play Console.")
+	}
+}
@@ -0,0 +1,88 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+func TestManagedPublishing(t *testing.T) {
+	managedErr := &googleapi.Error{Code: 400, Message: "This app has Managed Publishing enabled and has changes pending publishing."}
+
+	publishWith := func(t *testing.T, opts ...Option) *publish {
+		t.Helper()
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		p, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	t.Run("should report changes as not live with managed publishing on", func(t *testing.T) {
+		// Arrange
+		l := &recordingLogger{}
+		p := publishWith(t, WithLogger(l), WithManagedPublishing(ManagedPublishingOn, false))
+
+		// Act
+		_, err := p.UploadFiles(context.Background(), &mockGService{})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !l.has("INFO Changes submitted for review, not live") {
+			t.Errorf("want managed publishing message, got %v", l.lines)
+		}
+	})
+
+	t.Run("should fail with mismatch when managed publishing unexpectedly on", func(t *testing.T) {
+		// Arrange
+		p := publishWith(t, WithManagedPublishing(ManagedPublishingOff, true))
+		gs := &mockGService{commitErrors: []error{managedErr}}
+
+		// Act
+		_, err := p.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var mErr *ManagedPublishingError
+		if !errors.As(err, &mErr) || !errors.Is(err, managedErr) {
+			t.Errorf("want managed publishing mismatch wrapping commit error, got: %v", err)
+		}
+	})
+
+	t.Run("should explain managed publishing commit error when not strict", func(t *testing.T) {
+		// Arrange
+		p := publishWith(t)
+		gs := &mockGService{commitErrors: []error{managedErr}}
+
+		// Act
+		_, err := p.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var mErr *ManagedPublishingError
+		if err == nil || errors.As(err, &mErr) || !errors.Is(err, managedErr) {
+			t.Errorf("want plain commit error, got: %v", err)
+		}
+	})
+
+	t.Run("should reject unknown managed publishing setting", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithManagedPublishing("maybe", false))
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
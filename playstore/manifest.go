@@ -0,0 +1,34 @@
+package playstore
+
+import (
+	"github.com/sigitas-plk/playstore/playstore/apkparse"
+)
+
+// readManifest reads package name and version of binary, warning when package name differs from the app
+// it's published to. Binaries without readable manifest are left for Play to judge.
+func (p *publish) readManifest(filePath, packageName string) {
+	f, err := p.fs.Open(filePath)
+	if err != nil {
+		p.Debugf("failed opening '%s' to read its manifest: %v", filePath, err)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		p.Debugf("failed reading '%s' size: %v", filePath, err)
+		return
+	}
+	m, err := apkparse.Parse(f, info.Size())
+	if err != nil {
+		p.Debugf("failed reading '%s' manifest: %v", filePath, err)
+		return
+	}
+	p.Debugf("'%s' is '%s' versionCode %d versionName '%s'", filePath, m.PackageName, m.VersionCode, m.VersionName)
+	if m.PackageName != packageName {
+		p.Warnf("binary '%s' package name is '%s', not '%s' it's published to, Play will refuse it", filePath, m.PackageName, packageName)
+	}
+	if p.manifests == nil {
+		p.manifests = make(map[string]*apkparse.Manifest)
+	}
+	p.manifests[filePath] = m
+}
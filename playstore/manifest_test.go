@@ -0,0 +1,87 @@
+package playstore
+
+import (
+	"archive/zip"
+	"context"
+	encbin "encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestReadManifest(t *testing.T) {
+
+	t.Run("should warn about package name other than published app", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createBundle(t, fs, "test.aab", "com.other.app", 42)
+		l := &recordingLogger{}
+
+		// Act
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithLogger(l))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !l.has("WARN binary 'test.aab' package name is 'com.other.app'") {
+			t.Errorf("want package name mismatch warned, got %v", l.lines)
+		}
+		if m := publish.manifests["test.aab"]; m == nil || m.VersionCode != 42 {
+			t.Errorf("want versionCode 42 read, got %+v", m)
+		}
+	})
+
+	t.Run("should fail before upload when versionCode is taken by another binary", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createBundle(t, fs, "test.aab", "com.test.app", 42)
+		gs := &mockGService{hashes: map[int64]Hashes{42: {Sha256: "other"}}}
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithSkipExisting())
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ve *VersionExistsError
+		if !errors.As(err, &ve) || !ve.Different || ve.VersionCode != 42 {
+			t.Errorf("want versionCode 42 reported taken, got: %v", err)
+		}
+		if gs.uploadBundleCallCount != 0 {
+			t.Errorf("want no uploads, got %d", gs.uploadBundleCallCount)
+		}
+	})
+}
+
+// createBundle writes app bundle with only base manifest of given package name and versionCode
+func createBundle(t *testing.T, fs afero.Fs, file, packageName string, versionCode uint64) {
+	t.Helper()
+	field := func(num int, data []byte) []byte {
+		b := encbin.AppendUvarint(nil, uint64(num<<3|2))
+		b = encbin.AppendUvarint(b, uint64(len(data)))
+		return append(b, data...)
+	}
+	// XmlNode{element: XmlElement{name, attribute: XmlAttribute{name, compiled_item: Item{prim: Primitive{int_decimal_value}}}}}
+	pkg := append(field(2, []byte("package")), field(3, []byte(packageName))...)
+	version := append(field(2, []byte("versionCode")), field(6, field(7, encbin.AppendUvarint([]byte{6 << 3}, versionCode)))...)
+	element := append(field(3, []byte("manifest")), field(4, pkg)...)
+	element = append(element, field(4, version)...)
+
+	f, err := fs.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("base/manifest/AndroidManifest.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(field(1, element))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
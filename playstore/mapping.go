@@ -0,0 +1,85 @@
+package playstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// SharedMapping sets mapping of every binary without its own, e.g. multi-apk ABI splits built from the same
+// code. It's uploaded once for each version code it applies to.
+func SharedMapping(path string) Option {
+	return func(p *publish) {
+		p.sharedMapping = strings.TrimSpace(path)
+	}
+}
+
+// withSharedMapping returns copy of files with shared mapping set for binaries without mapping
+func (p *publish) withSharedMapping(files []binary) []binary {
+	if p.sharedMapping == "" {
+		return files
+	}
+	shared := make([]binary, len(files))
+	for i, f := range files {
+		if f.mappingPath == "" {
+			f.mappingPath = p.sharedMapping
+		}
+		shared[i] = f
+	}
+	return shared
+}
+
+// openMapping opens mapping file for upload. Gzipped mappings e.g. mapping.txt.gz are decompressed to
+// publish workspace first, as Play expects plain text mappings.
+func (p *publish) openMapping(filePath string) (afero.File, error) {
+	f, err := p.fs.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, len(gzipMagic))
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !bytes.Equal(head[:n], gzipMagic) {
+		return f, nil
+	}
+	defer f.Close()
+
+	if p.ws == nil {
+		p.ws = newWorkspace(p.fs)
+	}
+	p.Debugf("decompressing gzipped mappings '%s'", filePath)
+	out, err := p.ws.createTemp("*-" + strings.TrimSuffix(filepath.Base(filePath), ".gz"))
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		out.Close()
+		return nil, fmt.Errorf("failed reading gzipped mappings '%s': %w", filePath, err)
+	}
+	defer zr.Close()
+	if _, err := io.Copy(out, zr); err != nil {
+		out.Close()
+		return nil, fmt.Errorf("failed decompressing mappings '%s': %w", filePath, err)
+	}
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		out.Close()
+		return nil, err
+	}
+	return out, nil
+}
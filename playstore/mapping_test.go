@@ -0,0 +1,63 @@
+package playstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestGzippedMapping(t *testing.T) {
+
+	t.Run("should upload decompressed mapping and clean it up", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 10)
+		mapping := []byte("com.sample.App -> a:\n")
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		zw.Write(mapping)
+		zw.Close()
+		afero.WriteFile(fs, "mapping.txt.gz", gz.Bytes(), 0o644)
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{BinaryWithMapping("test.aab", "mapping.txt.gz")}, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if !bytes.Equal(gs.mappingBytes, mapping) {
+			t.Errorf("want '%s' mapping uploaded, got '%s'", mapping, gs.mappingBytes)
+		}
+		if publish.ws.dir != "" {
+			t.Errorf("want workspace cleaned up, got '%s'", publish.ws.dir)
+		}
+	})
+
+	t.Run("should upload plain mapping as is", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, mapping := createMockBinary(t, fs, "test.aab", "mapping.txt")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if !bytes.Equal(gs.mappingBytes, mapping) {
+			t.Errorf("want '%s' mapping uploaded, got '%s'", mapping, gs.mappingBytes)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// MetadataReport what PushMetadata changed, or would change on dry run
+type MetadataReport struct {
+	// listings and images difference, nil without metadata directory
+	Listings *ApplyReport `json:"listings,omitempty"`
+	// track and appVersions of the release whose notes were set, empty without track
+	Track        string   `json:"track,omitempty"`
+	VersionCodes []int64  `json:"versionCodes,omitempty"`
+	NoteLocales  []string `json:"noteLocales,omitempty"`
+	DryRun       bool     `json:"dryRun"`
+}
+
+/**
+ * PushMetadata updates store listings, images and release notes in a single edit without uploading binaries
+ *
+ * metadataDir - fastlane style metadata directory, see Apply, '' to only set release notes
+ * track - track whose latest release gets release notes from ReleaseNotes(...) or WithChangelogs(...), '' to leave releases as is
+ * opts - DryRun() only reports changes, WithNotSentForReviewFallback() and WithManagedPublishing(...) apply to commit
+ */
+func PushMetadata(ctx context.Context, gs IGService, fs afero.Fs, packageName, metadataDir, track string, opts ...Option) (*MetadataReport, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+
+	p := &publish{fs: fs}
+	for _, o := range opts {
+		o(p)
+	}
+	pr := p.profile
+	if pr == nil {
+		pr = &ProfileDefault
+	}
+
+	name, err := validatePackageName(packageName, p.anyPackageName)
+	if err != nil {
+		return nil, err
+	}
+	p.packageName = name
+	if metadataDir == "" && track == "" {
+		return nil, fmt.Errorf("metadata directory or track to set release notes on is required")
+	}
+	track = strings.TrimSpace(strings.ToLower(track))
+	if track != "" {
+		if len(p.releaseNotes) == 0 && p.changelogsDir == "" {
+			return nil, fmt.Errorf("release notes or changelogs directory are required to update '%s' track release", track)
+		}
+		track = pr.trackName(track)
+	}
+	if err := p.validateRelease(); err != nil {
+		return nil, err
+	}
+
+	var local []Listing
+	var images []LocaleImages
+	if metadataDir != "" {
+		var err error
+		if local, err = ReadListings(fs, metadataDir); err != nil {
+			return nil, err
+		}
+		if images, err = ReadMetadataImages(fs, metadataDir); err != nil {
+			return nil, err
+		}
+		if len(local) == 0 && len(images) == 0 {
+			return nil, fmt.Errorf("no listings found in '%s'", metadataDir)
+		}
+	}
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MetadataReport{DryRun: p.dryRun}
+	changed := false
+	if metadataDir != "" {
+		report.Listings, changed, err = applyMetadata(ctx, gs, fs, name, edit, local, images, false, p.dryRun)
+		if err != nil {
+			gs.deleteEdit(ctx, name, edit)
+			return nil, err
+		}
+	}
+	if track != "" {
+		if err := p.setReleaseNotes(ctx, gs, edit, track, report); err != nil {
+			gs.deleteEdit(ctx, name, edit)
+			return nil, err
+		}
+		changed = changed || !p.dryRun
+	}
+	if p.dryRun || !changed {
+		gs.deleteEdit(ctx, name, edit)
+		return report, nil
+	}
+
+	if err := gs.validateEdit(ctx, name, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if err := p.commit(ctx, gs, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	return report, nil
+}
+
+// setReleaseNotes replaces release notes of the latest release on track, keeping the rest of the track as is
+func (p *publish) setReleaseNotes(ctx context.Context, gs IGService, edit, trackName string, report *MetadataReport) error {
+	track, err := gs.getTrack(ctx, p.packageName, edit, trackName)
+	if err != nil {
+		return fmt.Errorf("failed reading '%s' track: %w", trackName, err)
+	}
+	release := latestRelease(track)
+	if release == nil {
+		return fmt.Errorf("'%s' track has no release to set release notes on", trackName)
+	}
+	notes, err := p.trackReleaseNotes(release.VersionCodes)
+	if err != nil {
+		return err
+	}
+	report.Track = trackName
+	report.VersionCodes = release.VersionCodes
+	for _, n := range notes {
+		report.NoteLocales = append(report.NoteLocales, n.Language)
+	}
+	if p.dryRun {
+		return nil
+	}
+	release.ReleaseNotes = notes
+	if err := gs.updateTrack(ctx, p.packageName, edit, track); err != nil {
+		return fmt.Errorf("failed updating '%s' track release notes: %w", trackName, err)
+	}
+	return nil
+}
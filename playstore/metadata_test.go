@@ -0,0 +1,86 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestPushMetadata(t *testing.T) {
+
+	beta := func() *androidpublisher.Track {
+		return &androidpublisher.Track{
+			Track: TrackBeta,
+			Releases: []*androidpublisher.TrackRelease{
+				{Name: "1.0", Status: StatusCompleted, VersionCodes: []int64{10}},
+				{Name: "1.1", Status: StatusInProgress, UserFraction: 0.1, VersionCodes: []int64{11}},
+			},
+		}
+	}
+
+	t.Run("should update listings and latest release notes in one edit without binaries", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "metadata/en-US/title.txt", []byte("Sample"), 0o644)
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: beta()}}
+
+		// Act
+		r, err := PushMetadata(context.Background(), gs, fs, "com.test.app", "metadata", TrackBeta, ReleaseNotes(map[string]string{"en-US": "Fixes"}))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(gs.updatedListings, []Listing{{Locale: "en-US", Title: "Sample"}}) {
+			t.Errorf("want en-US listing updated, got %v", gs.updatedListings)
+		}
+		if len(gs.updatedTracks) != 1 {
+			t.Fatalf("want beta track updated, got %d updates", len(gs.updatedTracks))
+		}
+		release := gs.updatedTracks[0].Releases[1]
+		if len(release.ReleaseNotes) != 1 || release.ReleaseNotes[0].Text != "Fixes" || release.UserFraction != 0.1 {
+			t.Errorf("want only notes of the latest release changed, got %+v", release)
+		}
+		if !reflect.DeepEqual(r.VersionCodes, []int64{11}) || gs.uploadBundleCallCount != 0 || gs.commitEditCount != 1 {
+			t.Errorf("want appVersion 11 notes committed without uploads, got %+v", r)
+		}
+	})
+
+	t.Run("should not change anything on dry run", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: beta()}}
+
+		// Act
+		r, err := PushMetadata(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", "", TrackBeta, ReleaseNotes(map[string]string{"en-US": "Fixes"}), DryRun())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !r.DryRun || !reflect.DeepEqual(r.NoteLocales, []string{"en-US"}) {
+			t.Errorf("want en-US notes reported on dry run, got %+v", r)
+		}
+		if len(gs.updatedTracks) != 0 || gs.commitEditCount != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted without changes, got %d track updates %d commits", len(gs.updatedTracks), gs.commitEditCount)
+		}
+	})
+
+	t.Run("should require release notes to update track", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		_, err := PushMetadata(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", "", TrackBeta)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.createEditCount != 0 {
+			t.Errorf("want no edit created, got %d", gs.createEditCount)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package playstore
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrNotesLint release notes break lint rules of WithNotesLint in strict mode
+var ErrNotesLint = errors.New("release notes failed lint")
+
+// NotesLint rules release notes are checked against before publish opens an edit
+type NotesLint struct {
+	// words notes must not contain, matched as whole words ignoring case e.g. TODO
+	Markers []string
+	// internal ticket IDs notes must not mention, none checked if nil
+	TicketPattern *regexp.Regexp
+	// locales notes must be given for e.g. en-US
+	RequiredLocales []string
+}
+
+// DefaultNotesLint refuses TODO, WIP, FIXME and TBD markers and JIRA style ticket IDs e.g. APP-123
+var DefaultNotesLint = NotesLint{
+	Markers:       []string{"TODO", "WIP", "FIXME", "TBD"},
+	TicketPattern: regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`),
+}
+
+// WithNotesLint checks release notes of the release, changelogs and binary notes included, against rules
+// before an edit is opened. Violations are logged as warnings, strict fails Publish with ErrNotesLint
+// instead. Changelogs are picked by the highest versionCode read from local binaries.
+func WithNotesLint(rules NotesLint, strict bool) Option {
+	return func(p *publish) {
+		p.notesLint = &rules
+		p.notesLintStrict = strict
+	}
+}
+
+// lintNotes checks release notes files would be released with against lint rules
+func (p *publish) lintNotes(files []binary) error {
+	rules := p.notesLint
+	if rules == nil {
+		return nil
+	}
+	var versions []int64
+	for _, f := range files {
+		if m, ok := p.manifests[f.filePath]; ok {
+			versions = append(versions, m.VersionCode)
+		}
+	}
+	notes, err := p.sharedReleaseNotes(versions)
+	if err != nil {
+		return err
+	}
+
+	violations := rules.check("", notes)
+	locales := make(map[string]bool, len(notes))
+	for l := range notes {
+		locales[l] = true
+	}
+	for _, f := range files {
+		violations = append(violations, rules.check(fmt.Sprintf("binary '%s' ", f.filePath), f.notes)...)
+		for l, text := range f.notes {
+			if strings.TrimSpace(text) != "" {
+				locales[l] = true
+			}
+		}
+	}
+	for _, l := range rules.RequiredLocales {
+		if !locales[l] {
+			violations = append(violations, fmt.Sprintf("release notes of required locale '%s' missing", l))
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	if p.notesLintStrict {
+		return errorOf(ErrNotesLint, "%s: %s", ErrNotesLint, strings.Join(violations, "; "))
+	}
+	for _, v := range violations {
+		p.Warnf("%s", v)
+	}
+	return nil
+}
+
+// check returns violations of notes by locale, sorted by locale
+func (rules *NotesLint) check(of string, notes map[string]string) []string {
+	locales := make([]string, 0, len(notes))
+	for l := range notes {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+
+	var violations []string
+	for _, l := range locales {
+		text := notes[l]
+		for _, m := range rules.Markers {
+			if m == "" {
+				continue
+			}
+			if regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(m) + `\b`).MatchString(text) {
+				violations = append(violations, fmt.Sprintf("%s'%s' release notes contain '%s' marker", of, l, m))
+			}
+		}
+		if rules.TicketPattern == nil {
+			continue
+		}
+		for _, id := range rules.TicketPattern.FindAllString(text, -1) {
+			violations = append(violations, fmt.Sprintf("%s'%s' release notes mention ticket '%s'", of, l, id))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,97 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestNotesLint(t *testing.T) {
+
+	publishLinted := func(t *testing.T, fs afero.Fs, files []binary, opts ...Option) (*publish, error) {
+		t.Helper()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 20)
+		return Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", files, false, false, opts...)
+	}
+
+	t.Run("should fail strict publish on markers and ticket IDs", func(t *testing.T) {
+		// Arrange
+		notes := map[string]string{"en-US": "Fixes crash (APP-123), wip dark mode"}
+
+		// Act
+		_, err := publishLinted(t, afero.NewMemMapFs(), []binary{Binary("test.aab")}, ReleaseNotes(notes), WithNotesLint(DefaultNotesLint, true))
+
+		// Assert
+		if !errors.Is(err, ErrNotesLint) {
+			t.Fatalf("want ErrNotesLint, got: %v", err)
+		}
+		for _, want := range []string{"'en-US' release notes contain 'WIP' marker", "'en-US' release notes mention ticket 'APP-123'"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("want '%s' in '%v'", want, err)
+			}
+		}
+	})
+
+	t.Run("should only warn about violations when not strict", func(t *testing.T) {
+		// Arrange
+		l := &recordingLogger{}
+		notes := map[string]string{"en-US": "TODO"}
+
+		// Act
+		_, err := publishLinted(t, afero.NewMemMapFs(), []binary{Binary("test.aab")}, ReleaseNotes(notes), WithLogger(l), WithNotesLint(DefaultNotesLint, false))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !l.has("WARN 'en-US' release notes contain 'TODO' marker") {
+			t.Error("want marker warning")
+		}
+	})
+
+	t.Run("should require locales of changelogs and binary notes", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "changelogs/default/en-US.txt", []byte("Bug fixes"), 0o644)
+		bin := Noted(Binary("test.aab"), map[string]string{"de-DE": "Fehlerbehebungen"})
+		rules := NotesLint{RequiredLocales: []string{"en-US", "de-DE", "fr-FR"}}
+
+		// Act
+		_, err := publishLinted(t, fs, []binary{bin}, WithChangelogs("changelogs"), WithNotesLint(rules, true))
+
+		// Assert
+		if err == nil || err.Error() != "release notes failed lint: release notes of required locale 'fr-FR' missing" {
+			t.Errorf("want only fr-FR missing, got: %v", err)
+		}
+	})
+
+	t.Run("should lint notes of binaries", func(t *testing.T) {
+		// Arrange
+		bin := Noted(Binary("test.aab"), map[string]string{"en-US": "FIXME"})
+
+		// Act
+		_, err := publishLinted(t, afero.NewMemMapFs(), []binary{bin}, WithNotesLint(DefaultNotesLint, true))
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "binary 'test.aab' 'en-US' release notes contain 'FIXME' marker") {
+			t.Errorf("want binary notes violation, got: %v", err)
+		}
+	})
+
+	t.Run("should not match markers within words", func(t *testing.T) {
+		// Arrange
+		notes := map[string]string{"en-US": "Todos list and swipe gestures"}
+
+		// Act
+		_, err := publishLinted(t, afero.NewMemMapFs(), []binary{Binary("test.aab")}, ReleaseNotes(notes), WithNotesLint(DefaultNotesLint, true))
+
+		// Assert
+		if err != nil {
+			t.Errorf("want no error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package playstore
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+const (
+	// Play limit for release notes of a single locale
+	releaseNotesMaxLength = 500
+	// changelog directory used when none matches the uploaded appVersionCode
+	changelogsDefaultDir = "default"
+	// Play limit for release name
+	releaseNameMaxLength = 50
+)
+
+// ReleaseNotes attaches release notes by locale e.g. {"en-US": "Bug fixes"} to the track release
+func ReleaseNotes(notes map[string]string) Option {
+	return func(p *publish) {
+		p.releaseNotes = notes
+	}
+}
+
+// WithReleaseName names the track release e.g. '2.3.0 (231)', Play names it after the highest appVersion
+// if not set. Promote copies name of the source release unless one is set.
+func WithReleaseName(name string) Option {
+	return func(p *publish) {
+		p.releaseName = strings.TrimSpace(name)
+	}
+}
+
+// WithChangelogs reads release notes from changelogs/<versionCode>/<locale>.txt directory layout,
+// for the highest uploaded appVersionCode or changelogs/default/ if there is none.
+// Notes set with ReleaseNotes take precedence for the same locale.
+func WithChangelogs(dir string) Option {
+	return func(p *publish) {
+		p.changelogsDir = dir
+	}
+}
+
+// Noted appends lines by locale e.g. {"en-US": "Wear: fixes complication crash"} to release notes of the
+// release binary lands in, after notes shared by all binaries. Lines not fitting Play limit are left out.
+func Noted(b binary, notes map[string]string) binary {
+	b.notes = notes
+	return b
+}
+
+func validateReleaseNotes(notes map[string]string) error {
+	for locale, text := range notes {
+		if strings.TrimSpace(locale) == "" {
+			return fmt.Errorf("release notes locale must not be empty")
+		}
+		if n := utf8.RuneCountInString(text); n > releaseNotesMaxLength {
+			return fmt.Errorf("'%s' release notes are %d characters, at most %d allowed", locale, n, releaseNotesMaxLength)
+		}
+	}
+	return nil
+}
+
+// trackReleaseNotes collects release notes for uploaded appVersions sorted by locale
+func (p *publish) trackReleaseNotes(versions []int64) ([]*androidpublisher.LocalizedText, error) {
+	notes, err := p.sharedReleaseNotes(versions)
+	if err != nil {
+		return nil, err
+	}
+	p.appendBinaryNotes(notes)
+
+	locales := make([]string, 0, len(notes))
+	for l := range notes {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+
+	texts := make([]*androidpublisher.LocalizedText, 0, len(locales))
+	for _, l := range locales {
+		texts = append(texts, &androidpublisher.LocalizedText{Language: l, Text: notes[l]})
+	}
+	return texts, nil
+}
+
+// sharedReleaseNotes collects release notes shared by all binaries of appVersions, from changelogs and ReleaseNotes
+func (p *publish) sharedReleaseNotes(versions []int64) (map[string]string, error) {
+	notes := make(map[string]string)
+	if p.changelogsDir != "" {
+		var latest int64
+		for _, v := range versions {
+			if v > latest {
+				latest = v
+			}
+		}
+		n, err := readChangelogs(p.fs, p.changelogsDir, latest)
+		if err != nil {
+			return nil, err
+		}
+		notes = n
+	}
+	for locale, text := range p.releaseNotes {
+		notes[locale] = text
+	}
+	if err := validateReleaseNotes(notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// appendBinaryNotes appends release notes lines of binaries in the order they were given, leaving out
+// lines that would make notes of their locale exceed Play limit
+func (p *publish) appendBinaryNotes(notes map[string]string) {
+	for _, f := range p.files {
+		locales := make([]string, 0, len(f.notes))
+		for l := range f.notes {
+			locales = append(locales, l)
+		}
+		sort.Strings(locales)
+		for _, l := range locales {
+			line := strings.TrimSpace(f.notes[l])
+			if line == "" {
+				continue
+			}
+			text := line
+			if notes[l] != "" {
+				text = notes[l] + "\n" + line
+			}
+			if n := utf8.RuneCountInString(text); n > releaseNotesMaxLength {
+				p.Warnf("'%s' release notes line of '%s' left out, notes would be %d characters, at most %d allowed", l, f.filePath, n, releaseNotesMaxLength)
+				continue
+			}
+			notes[l] = text
+		}
+	}
+}
+
+// readChangelogs reads <dir>/<versionCode>/<locale>.txt files, falling back to <dir>/default/<locale>.txt
+func readChangelogs(fs afero.Fs, dir string, versionCode int64) (map[string]string, error) {
+	notesDir := filepath.Join(dir, fmt.Sprintf("%d", versionCode))
+	if ok, _ := afero.DirExists(fs, notesDir); !ok {
+		notesDir = filepath.Join(dir, changelogsDefaultDir)
+		if ok, _ := afero.DirExists(fs, notesDir); !ok {
+			return map[string]string{}, nil
+		}
+	}
+
+	entries, err := afero.ReadDir(fs, notesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading changelogs '%s': %w", notesDir, err)
+	}
+	notes := make(map[string]string)
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".txt" {
+			continue
+		}
+		b, err := afero.ReadFile(fs, filepath.Join(notesDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		notes[strings.TrimSuffix(e.Name(), ".txt")] = strings.TrimSpace(string(b))
+	}
+	return notes, nil
+}
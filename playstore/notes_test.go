@@ -0,0 +1,128 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestReleaseNotes(t *testing.T) {
+
+	t.Run("should attach release notes sorted by locale", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false,
+			ReleaseNotes(map[string]string{"lt-LT": "Pataisymai", "en-US": "Fixes"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		expected := []*androidpublisher.LocalizedText{{Language: "en-US", Text: "Fixes"}, {Language: "lt-LT", Text: "Pataisymai"}}
+		if !reflect.DeepEqual(gs.releases[0].ReleaseNotes, expected) {
+			t.Errorf("want %+v, got %+v", expected, gs.releases[0].ReleaseNotes)
+		}
+	})
+
+	t.Run("should read changelogs for uploaded version code", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		afero.WriteFile(fs, "changelogs/12/en-US.txt", []byte("Version 12\n"), 0o644)
+		afero.WriteFile(fs, "changelogs/default/en-US.txt", []byte("Default"), 0o644)
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, WithChangelogs("changelogs"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{AppVersionCode: 12}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		expected := []*androidpublisher.LocalizedText{{Language: "en-US", Text: "Version 12"}}
+		if !reflect.DeepEqual(gs.releases[0].ReleaseNotes, expected) {
+			t.Errorf("want %+v, got %+v", expected, gs.releases[0].ReleaseNotes)
+		}
+	})
+
+	t.Run("should fall back to default changelogs", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "changelogs/default/en-US.txt", []byte("Default"), 0o644)
+
+		// Act
+		notes, err := readChangelogs(fs, "changelogs", 3)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(notes, map[string]string{"en-US": "Default"}) {
+			t.Errorf("want default notes, got %v", notes)
+		}
+	})
+
+	t.Run("should reject release notes over length limit", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false,
+			ReleaseNotes(map[string]string{"en-US": strings.Repeat("a", releaseNotesMaxLength+1)}))
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should append binary notes lines fitting length limit", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "phone.apk", 10)
+		createTestFile(t, fs, "wear.apk", 10)
+		bins := []binary{
+			Noted(Binary("phone.apk"), map[string]string{"en-US": strings.Repeat("a", releaseNotesMaxLength-6)}),
+			Noted(Binary("wear.apk"), map[string]string{"en-US": "Wear: fixes complication crash", "lt-LT": "Wear: pataisymai"}),
+		}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", bins, true, false,
+			ReleaseNotes(map[string]string{"en-US": "Fixes"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		expected := []*androidpublisher.LocalizedText{
+			{Language: "en-US", Text: "Fixes\n" + strings.Repeat("a", releaseNotesMaxLength-6)},
+			{Language: "lt-LT", Text: "Wear: pataisymai"},
+		}
+		if !reflect.DeepEqual(gs.releases[0].ReleaseNotes, expected) {
+			t.Errorf("want %+v, got %+v", expected, gs.releases[0].ReleaseNotes)
+		}
+	})
+}
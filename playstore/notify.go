@@ -0,0 +1,119 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// how long sending a notification may take, publish is done by then so it's not waited for longer
+const notifyTimeout = 10 * time.Second
+
+// statuses of publish notification
+const (
+	NotifySucceeded = "succeeded"
+	NotifyFailed    = "failed"
+)
+
+// Notification outcome of UploadFiles sent to Notifier
+type Notification struct {
+	// summary of the outcome, shown by Slack compatible webhooks
+	Text         string  `json:"text"`
+	RunID        string  `json:"runId"`
+	PackageName  string  `json:"packageName"`
+	Track        string  `json:"track"`
+	VersionCodes []int64 `json:"versionCodes"`
+	Status       string  `json:"status"`
+	DryRun       bool    `json:"dryRun,omitempty"`
+	Error        string  `json:"error,omitempty"`
+	// time UploadFiles took, in seconds
+	Duration float64 `json:"durationSeconds"`
+}
+
+// Notifier is told outcome of every UploadFiles call, e.g. to post it to a chat channel
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// WithNotifier sends outcome of UploadFiles to notifier once it succeeded or failed. Notifier failures
+// are logged as warnings, they never fail publish.
+func WithNotifier(n Notifier) Option {
+	return func(p *publish) {
+		p.notifier = n
+	}
+}
+
+// WebhookNotifier posts notification as JSON to URL, Slack incoming webhooks included
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client // http.DefaultClient if not set
+}
+
+func (wn *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := wn.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", res.Status)
+	}
+	return nil
+}
+
+// notify sends outcome of UploadFiles to notifier if set. It gets a context of its own, so failure notice
+// goes out after publish was cancelled too.
+func (p *publish) notify(results []UploadResult, err error, took time.Duration) {
+	if p.notifier == nil {
+		return
+	}
+	n := Notification{RunID: p.runID, PackageName: p.packageName, Track: p.track, VersionCodes: make([]int64, 0, len(results)),
+		Status: NotifySucceeded, DryRun: p.dryRun, Duration: took.Seconds()}
+	for _, r := range results {
+		n.VersionCodes = append(n.VersionCodes, r.VersionCode)
+	}
+	if err != nil {
+		n.Status, n.Error = NotifyFailed, err.Error()
+	}
+	n.Text = n.summary(took)
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	if nerr := p.notifier.Notify(ctx, n); nerr != nil {
+		p.Warnf("failed sending publish notification: %v", nerr)
+	}
+}
+
+// summary one line description of the outcome
+func (n *Notification) summary(took time.Duration) string {
+	took = took.Round(time.Second)
+	what := "Publish"
+	if n.DryRun {
+		what = "Dry run"
+	}
+	if n.Status == NotifyFailed {
+		return fmt.Sprintf("%s of %s to %s failed after %s: %s (run %s)", what, n.PackageName, n.Track, took, n.Error, n.RunID)
+	}
+	versions := make([]string, 0, len(n.VersionCodes))
+	for _, v := range n.VersionCodes {
+		versions = append(versions, fmt.Sprintf("%d", v))
+	}
+	return fmt.Sprintf("%s of %s to %s succeeded in %s with versionCodes %s (run %s)", what, n.PackageName, n.Track, took, strings.Join(versions, ", "), n.RunID)
+}
@@ -0,0 +1,137 @@
+package playstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+func TestNotify(t *testing.T) {
+
+	webhook := func(t *testing.T, status int) (*httptest.Server, *[]Notification) {
+		t.Helper()
+		received := &[]Notification{}
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var n Notification
+			if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("want JSON POST, got %s %s", r.Method, r.Header.Get("Content-Type"))
+			}
+			if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+				t.Errorf("want JSON notification, got: %v", err)
+			}
+			*received = append(*received, n)
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(srv.Close)
+		return srv, received
+	}
+
+	publishTo := func(t *testing.T, url string, extra ...Option) *publish {
+		t.Helper()
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 20)
+		opts := append([]Option{WithRunID("run-1"), WithNotifier(&WebhookNotifier{URL: url}), WithProgressFunc(func(string, int64, int64) {})}, extra...)
+		p, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	t.Run("should post version codes once publish succeeds", func(t *testing.T) {
+		// Arrange
+		srv, received := webhook(t, http.StatusOK)
+		p := publishTo(t, srv.URL)
+
+		// Act
+		_, err := p.UploadFiles(context.Background(), &mockGService{AppVersionCode: 7})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(*received) != 1 {
+			t.Fatalf("want one notification, got %d", len(*received))
+		}
+		n := (*received)[0]
+		if n.Status != NotifySucceeded || n.PackageName != "com.test.app" || n.Track != TrackInternal || n.RunID != "run-1" || n.Error != "" {
+			t.Errorf("want succeeded publish of com.test.app to internal, got %+v", n)
+		}
+		if !reflect.DeepEqual(n.VersionCodes, []int64{7}) {
+			t.Errorf("want versionCodes [7], got %v", n.VersionCodes)
+		}
+		if !strings.Contains(n.Text, "com.test.app") || !strings.Contains(n.Text, "7") {
+			t.Errorf("want text naming app and version code, got '%s'", n.Text)
+		}
+	})
+
+	t.Run("should post error once publish fails", func(t *testing.T) {
+		// Arrange
+		srv, received := webhook(t, http.StatusOK)
+		p := publishTo(t, srv.URL)
+		gs := &mockGService{commitErrors: []error{&googleapi.Error{Code: 400, Message: "refused"}}}
+
+		// Act
+		_, err := p.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err == nil {
+			t.Fatal("want publish error")
+		}
+		if len(*received) != 1 {
+			t.Fatalf("want one notification, got %d", len(*received))
+		}
+		n := (*received)[0]
+		if n.Status != NotifyFailed || n.Error != err.Error() || len(n.VersionCodes) != 0 {
+			t.Errorf("want failed publish with '%v', got %+v", err, n)
+		}
+		if !strings.Contains(n.Text, "failed") {
+			t.Errorf("want text telling publish failed, got '%s'", n.Text)
+		}
+	})
+
+	t.Run("should only warn when webhook refuses notification", func(t *testing.T) {
+		// Arrange
+		srv, _ := webhook(t, http.StatusInternalServerError)
+		l := &recordingLogger{}
+		p := publishTo(t, srv.URL, WithLogger(l))
+
+		// Act
+		_, err := p.UploadFiles(context.Background(), &mockGService{})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !l.has("WARN failed sending publish notification") {
+			t.Error("want notification failure warned")
+		}
+	})
+
+	t.Run("should fail non 2xx webhook response", func(t *testing.T) {
+		// Arrange
+		srv, _ := webhook(t, http.StatusNotFound)
+		wn := &WebhookNotifier{URL: srv.URL}
+
+		// Act
+		err := wn.Notify(context.Background(), Notification{Text: "hi"})
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "404") {
+			t.Errorf("want 404 error, got: %v", err)
+		}
+		var ae *APIError
+		if errors.As(err, &ae) {
+			t.Errorf("want webhook error not to be APIError, got %v", ae)
+		}
+	})
+}
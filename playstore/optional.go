@@ -0,0 +1,48 @@
+package playstore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+// isUnavailableErr checks if call failed as its API isn't enabled or credentials lack scope for it, as
+// opposed to the app or request being at fault
+func isUnavailableErr(err error) bool {
+	var ge *googleapi.Error
+	return errors.As(err, &ge) && ge.Code == http.StatusForbidden &&
+		hasReason(ge, "accessNotConfigured", "SERVICE_DISABLED", "insufficientPermissions", "ACCESS_TOKEN_SCOPE_INSUFFICIENT")
+}
+
+// skippedFeature records optional feature publish carried on without, as its call failed with err. Features
+// whose API is unavailable are reported with what to fix, other failures as skipped checks.
+func (p *publish) skippedFeature(feature string, err error) {
+	if !isUnavailableErr(err) {
+		p.skippedCheckf(true, "%s skipped: %v", feature, err)
+		return
+	}
+	p.log().Warnf("%s skipped, its Google API isn't enabled for the project or credentials lack scope for it: %v", feature, err)
+	p.noteSoftFailure(SoftFailureSkippedFeature, "%s", feature)
+}
+
+// SkippedFeatures returns optional features publish skipped as their Google API was unavailable, in order
+// they were skipped
+func (p *publish) SkippedFeatures() []string {
+	features := make([]string, 0)
+	for _, f := range p.SoftFailures() {
+		if f.Kind == SoftFailureSkippedFeature {
+			features = append(features, f.Message)
+		}
+	}
+	return features
+}
+
+// unavailableError error of optional feature that can't run, for callers failing without it
+func unavailableError(feature string, err error) error {
+	if !isUnavailableErr(err) {
+		return err
+	}
+	return fmt.Errorf("%s unavailable, enable its Google API for the project or grant credentials its scope: %w", feature, err)
+}
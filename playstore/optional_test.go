@@ -0,0 +1,96 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+// hashesFailingService fails listing binaries on Play only
+type hashesFailingService struct {
+	*mockGService
+	err error
+}
+
+func (gs *hashesFailingService) versionHashes(ctx context.Context, packageName, editId string) (map[int64]Hashes, error) {
+	return nil, gs.err
+}
+
+func TestOptionalFeatures(t *testing.T) {
+
+	disabled := &googleapi.Error{Code: http.StatusForbidden, Message: "API not enabled", Errors: []googleapi.ErrorItem{{Reason: "accessNotConfigured"}}}
+
+	t.Run("should publish without feature of disabled API and report it skipped", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 20)
+		l := &recordingLogger{}
+		p, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false,
+			WithLogger(l), WithProgressFunc(func(string, int64, int64) {}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &hashesFailingService{mockGService: &mockGService{AppVersionCode: 3}, err: disabled}
+
+		// Act
+		results, err := p.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		receipt := p.Receipt(results)
+		if len(receipt.SkippedFeatures) != 1 || receipt.SkippedFeatures[0] != "duplicate check of binaries on Play" {
+			t.Errorf("want duplicate check skipped, got %v", receipt.SkippedFeatures)
+		}
+		if !l.has("WARN duplicate check of binaries on Play skipped, its Google API isn't enabled") {
+			t.Error("want warning naming what to fix")
+		}
+	})
+
+	t.Run("should report other failures as skipped checks", func(t *testing.T) {
+		// Arrange
+		p := &publish{}
+
+		// Act
+		p.skippedFeature("duplicate check", &googleapi.Error{Code: http.StatusInternalServerError})
+
+		// Assert
+		if len(p.SkippedFeatures()) != 0 || len(p.SoftFailures()) != 1 || p.SoftFailures()[0].Kind != SoftFailureSkippedCheck {
+			t.Errorf("want skipped check only, got %+v", p.SoftFailures())
+		}
+	})
+
+	t.Run("should tell disabled API from reason in error details", func(t *testing.T) {
+		// Arrange
+		err := &googleapi.Error{Code: http.StatusForbidden, Details: []any{map[string]any{"reason": "SERVICE_DISABLED"}}}
+
+		// Act
+		unavailable := isUnavailableErr(err)
+
+		// Assert
+		if !unavailable {
+			t.Error("want SERVICE_DISABLED unavailable")
+		}
+	})
+
+	t.Run("should explain unavailable reviews", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{Error: disabled}
+
+		// Act
+		_, err := ListReviews(context.Background(), gs, "com.test.app", time.Time{})
+
+		// Assert
+		if !errors.Is(err, disabled) || !strings.Contains(err.Error(), "reviews unavailable, enable its Google API") {
+			t.Errorf("want unavailable reviews error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package playstore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// upload binaries in order they were provided
+	UploadOrderGiven = "given"
+	// upload smallest binaries first to fail fast
+	UploadOrderSmallestFirst = "smallest"
+	// upload highest priority binaries first, see Prioritized
+	UploadOrderPriority = "priority"
+)
+
+// Prioritized sets binary upload priority, higher priority binaries upload first with UploadOrderPriority
+func Prioritized(b binary, priority int) binary {
+	b.priority = priority
+	return b
+}
+
+// WithUploadOrder sets order binaries are uploaded in e.g. UploadOrderSmallestFirst
+func WithUploadOrder(order string) Option {
+	return func(p *publish) {
+		p.uploadOrder = order
+	}
+}
+
+func validateUploadOrder(order string) error {
+	switch order {
+	case "", UploadOrderGiven, UploadOrderSmallestFirst, UploadOrderPriority:
+		return nil
+	}
+	return fmt.Errorf("upload order '%s' not supported. Only supported orders are '%s' '%s' '%s'", order, UploadOrderGiven, UploadOrderSmallestFirst, UploadOrderPriority)
+}
+
+// orderedFiles returns binaries in order they should be uploaded, leaving p.files as provided
+func (p *publish) orderedFiles() []binary {
+	files := make([]binary, len(p.files))
+	copy(files, p.files)
+
+	switch p.uploadOrder {
+	case UploadOrderSmallestFirst:
+		sizes := make(map[string]int64, len(files))
+		for _, f := range files {
+			if f.stream != nil {
+				// unknown size of -1 uploads first
+				sizes[f.filePath] = f.stream.size
+				continue
+			}
+			size, err := p.fileSize(f.filePath)
+			if err != nil {
+				p.fallbackf("failed reading '%s' size, uploading it first: %v", f.filePath, err)
+			}
+			sizes[f.filePath] = size
+		}
+		sort.SliceStable(files, func(i, j int) bool {
+			return sizes[files[i].filePath] < sizes[files[j].filePath]
+		})
+	case UploadOrderPriority:
+		sort.SliceStable(files, func(i, j int) bool {
+			return files[i].priority > files[j].priority
+		})
+	}
+
+	if p.uploadOrder != "" && p.uploadOrder != UploadOrderGiven {
+		names := make([]string, 0, len(files))
+		for _, f := range files {
+			names = append(names, f.filePath)
+		}
+		p.Debugf("upload order '%s': %s", p.uploadOrder, strings.Join(names, ", "))
+	}
+	return files
+}
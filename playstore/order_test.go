@@ -0,0 +1,106 @@
+package playstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestUploadOrder(t *testing.T) {
+
+	t.Run("should upload binaries of a map in path order", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "c.aab", 30)
+		createTestFile(t, fs, "a.aab", 10)
+		createTestFile(t, fs, "b.aab", 20)
+		bins := Binaries(map[string]string{"c.aab": "", "a.aab": "", "b.aab": ""})
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", bins, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{}
+
+		// Act
+		results, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range []string{"a.aab", "b.aab", "c.aab"} {
+			if results[i].Path != want || len(gs.uploads[i]) != (i+1)*10 {
+				t.Errorf("want '%s' uploaded and reported as %d, got '%s' of %d bytes", want, i, results[i].Path, len(gs.uploads[i]))
+			}
+		}
+	})
+
+	t.Run("should upload smallest binary first", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "large.aab", 30)
+		createTestFile(t, fs, "small.aab", 10)
+		createTestFile(t, fs, "medium.aab", 20)
+		bins := []binary{Binary("large.aab"), Binary("small.aab"), Binary("medium.aab")}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", bins, false, false, WithUploadOrder(UploadOrderSmallestFirst))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		want := []int{10, 20, 30}
+		for i, u := range gs.uploads {
+			if len(u) != want[i] {
+				t.Errorf("want upload %d to be %d bytes, got %d", i, want[i], len(u))
+			}
+		}
+	})
+
+	t.Run("should upload highest priority binary first", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "low.aab", 10)
+		createTestFile(t, fs, "high.aab", 20)
+		bins := []binary{Binary("low.aab"), Prioritized(Binary("high.aab"), 10)}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", bins, false, false, WithUploadOrder(UploadOrderPriority))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if len(gs.uploads) != 2 || len(gs.uploads[0]) != 20 {
+			t.Errorf("want high priority binary uploaded first, got uploads of %d and %d bytes", len(gs.uploads[0]), len(gs.uploads[1]))
+		}
+	})
+
+	t.Run("should reject unknown order", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.aab", 10)
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("app.aab")}, false, false, WithUploadOrder("random"))
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
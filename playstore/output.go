@@ -0,0 +1,19 @@
+package playstore
+
+import "github.com/spf13/afero"
+
+// WithOutputFs writes state files to fs instead of one binaries are read from, e.g. to keep them in memory.
+// Receipts and pins are written with WriteReceipt and WritePin, taking fs of their own.
+func WithOutputFs(fs afero.Fs) Option {
+	return func(p *publish) {
+		p.outputFs = fs
+	}
+}
+
+// outFs returns fs outputs are written to
+func (p *publish) outFs() afero.Fs {
+	if p.outputFs == nil {
+		return p.fs
+	}
+	return p.outputFs
+}
@@ -0,0 +1,35 @@
+package playstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestOutputFs(t *testing.T) {
+
+	t.Run("should write resume state to output fs", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		out := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 20)
+		gs := &mockResumableGService{sessions: map[string][]byte{}, failAt: 8}
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithResume("state.json"), WithOutputFs(out))
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err == nil {
+			t.Fatal("want interrupted upload error, got nil")
+		}
+		if ok, _ := afero.Exists(out, "state.json"); !ok {
+			t.Error("want resume state in output fs, got none")
+		}
+		if ok, _ := afero.Exists(fs, "state.json"); ok {
+			t.Error("want no resume state next to binaries, got one")
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package playstore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithAnyPackageName accepts package names not following Android application ID rules, e.g. legacy apps
+// Play still serves. Only empty package names are refused then.
+func WithAnyPackageName() Option {
+	return func(p *publish) {
+		p.anyPackageName = true
+	}
+}
+
+// validatePackageName trims package name and checks it's an Android application ID: at least two dot
+// separated segments of letters, digits and underscores, each starting with a letter. anyName skips the rules.
+func validatePackageName(packageName string, anyName bool) (string, error) {
+	name := strings.TrimSpace(packageName)
+	if name == "" {
+		return "", fmt.Errorf("package name must not be empty")
+	}
+	if anyName {
+		return name, nil
+	}
+	segments := strings.Split(name, ".")
+	if len(segments) < 2 {
+		return "", errorOf(ErrInvalidPackageName, "package name '%s' needs at least two segments e.g. 'com.sample.app'", name)
+	}
+	for _, s := range segments {
+		if s == "" {
+			return "", errorOf(ErrInvalidPackageName, "package name '%s' has empty segment", name)
+		}
+		if !isASCIILetter(s[0]) {
+			return "", errorOf(ErrInvalidPackageName, "package name '%s' segment '%s' must start with a letter", name, s)
+		}
+		for i := 0; i < len(s); i++ {
+			if c := s[i]; !isASCIILetter(c) && (c < '0' || c > '9') && c != '_' {
+				return "", errorOf(ErrInvalidPackageName, "package name '%s' has '%c', only letters, digits and underscores are allowed", name, c)
+			}
+		}
+	}
+	return name, nil
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
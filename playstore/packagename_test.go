@@ -0,0 +1,73 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestValidatePackageName(t *testing.T) {
+
+	t.Run("should accept application IDs", func(t *testing.T) {
+		for _, name := range []string{"com.sample.app", " com.Sample.app_2 ", "io.a"} {
+			// Act
+			_, err := validatePackageName(name, false)
+
+			// Assert
+			if err != nil {
+				t.Errorf("want '%s' accepted, got: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("should refuse names breaking application ID rules", func(t *testing.T) {
+		for _, name := range []string{"app", "com..app", "com.1app", "com.sample-app", "com.sample.app.", "com.smp.äpp"} {
+			// Act
+			_, err := validatePackageName(name, false)
+
+			// Assert
+			if !errors.Is(err, ErrInvalidPackageName) {
+				t.Errorf("want '%s' refused with '%v', got: %v", name, ErrInvalidPackageName, err)
+			}
+		}
+	})
+
+	t.Run("should accept any non empty name when allowed", func(t *testing.T) {
+		// Act
+		name, err := validatePackageName(" com.1app ", true)
+		_, emptyErr := validatePackageName(" ", true)
+
+		// Assert
+		if err != nil || name != "com.1app" {
+			t.Errorf("want 'com.1app' accepted, got '%s': %v", name, err)
+		}
+		if emptyErr == nil {
+			t.Error("want empty name refused")
+		}
+	})
+
+	t.Run("should refuse invalid package name before any API call", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.aab", 10)
+		gs := &mockGService{}
+
+		// Act
+		_, err := Publish(context.Background(), fs, "sample-app", TrackInternal, "auth.json", []binary{Binary("app.aab")}, false, false)
+		p, anyErr := Publish(context.Background(), fs, "com.1app", TrackInternal, "auth.json", []binary{Binary("app.aab")}, false, false, WithAnyPackageName())
+		if anyErr == nil {
+			_, anyErr = p.UploadFiles(context.Background(), gs)
+		}
+
+		// Assert
+		if !errors.Is(err, ErrInvalidPackageName) {
+			t.Errorf("want '%v', got: %v", ErrInvalidPackageName, err)
+		}
+		if anyErr != nil || gs.packageName != "com.1app" {
+			t.Errorf("want 'com.1app' published with WithAnyPackageName, got '%s': %v", gs.packageName, anyErr)
+		}
+	})
+}
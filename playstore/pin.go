@@ -0,0 +1,110 @@
+package playstore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/afero"
+)
+
+// Pin records exact binaries released to a track, so they can be promoted later even if the track moved on
+type Pin struct {
+	PackageName string           `json:"packageName"`
+	Track       string           `json:"track"`
+	Artifacts   []PinnedArtifact `json:"artifacts"`
+}
+
+// PinnedArtifact appVersion with digests it must still have on Play when promoted
+type PinnedArtifact struct {
+	VersionCode int64  `json:"versionCode"`
+	Sha256      string `json:"sha256"`
+	Sha1        string `json:"sha1,omitempty"`
+}
+
+// NewPin pins binaries returned by UploadFiles to track they were released to
+func NewPin(packageName, track string, results []UploadResult) *Pin {
+	pin := &Pin{PackageName: packageName, Track: track, Artifacts: make([]PinnedArtifact, 0, len(results))}
+	for _, r := range results {
+		pin.Artifacts = append(pin.Artifacts, PinnedArtifact{VersionCode: r.VersionCode, Sha256: r.Sha256, Sha1: r.Sha1})
+	}
+	return pin
+}
+
+// WritePin writes pin as JSON file
+func WritePin(fs afero.Fs, path string, pin *Pin) error {
+	b, err := json.MarshalIndent(pin, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(fs, path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed writing pin file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// ReadPin reads pin file written by WritePin
+func ReadPin(fs afero.Fs, path string) (*Pin, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading pin file '%s': %w", path, err)
+	}
+	pin := &Pin{}
+	if err := json.Unmarshal(b, pin); err != nil {
+		return nil, fmt.Errorf("pin file '%s' is not valid: %w", path, err)
+	}
+	if err := pin.validate(); err != nil {
+		return nil, fmt.Errorf("pin file '%s' is not valid: %w", path, err)
+	}
+	return pin, nil
+}
+
+// WithPin makes Promote release exactly the pinned appVersions, taken from the track they were pinned on,
+// failing if any of them is missing on Play or has different sha256
+func WithPin(pin *Pin) Option {
+	return func(p *publish) {
+		p.pin = pin
+	}
+}
+
+func (pin *Pin) validate() error {
+	if pin.PackageName == "" || pin.Track == "" {
+		return fmt.Errorf("package name and track are required")
+	}
+	if len(pin.Artifacts) == 0 {
+		return fmt.Errorf("at least one pinned artifact is required")
+	}
+	for _, a := range pin.Artifacts {
+		if a.VersionCode <= 0 || a.Sha256 == "" {
+			return fmt.Errorf("pinned artifact needs appVersionCode and sha256, got %+v", a)
+		}
+	}
+	return nil
+}
+
+// versionCodes pinned appVersion codes
+func (pin *Pin) versionCodes() []int64 {
+	versions := make([]int64, 0, len(pin.Artifacts))
+	for _, a := range pin.Artifacts {
+		versions = append(versions, a.VersionCode)
+	}
+	return versions
+}
+
+// verify checks pinned binaries are on Play with the same digests
+func (pin *Pin) verify(hashes map[int64]Hashes) error {
+	for _, a := range pin.Artifacts {
+		h, ok := hashes[a.VersionCode]
+		if !ok {
+			return fmt.Errorf("pinned appVersion '%d' not found on playstore", a.VersionCode)
+		}
+		if h.Sha256 != a.Sha256 {
+			return fmt.Errorf("pinned appVersion '%d' sha256 '%s' doesn't match playstore '%s'", a.VersionCode, a.Sha256, h.Sha256)
+		}
+	}
+	return nil
+}
+
+// Pin pins binaries returned by UploadFiles to the app and track they were released to
+func (p *publish) Pin(results []UploadResult) *Pin {
+	return NewPin(p.packageName, p.track, results)
+}
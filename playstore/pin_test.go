@@ -0,0 +1,106 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestPin(t *testing.T) {
+
+	pin := &Pin{PackageName: "com.test.app", Track: TrackInternal, Artifacts: []PinnedArtifact{{VersionCode: 11, Sha256: "a"}, {VersionCode: 12, Sha256: "b"}}}
+
+	t.Run("should read pin it has written", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		written := NewPin("com.test.app", TrackInternal, []UploadResult{{Path: "app.aab", VersionCode: 11, Sha256: "a", Sha1: "c"}})
+
+		// Act
+		if err := WritePin(fs, "pin.json", written); err != nil {
+			t.Fatal(err)
+		}
+		read, err := ReadPin(fs, "pin.json")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(written, read) {
+			t.Errorf("want %+v, got %+v", written, read)
+		}
+	})
+
+	t.Run("should promote pinned version codes even if track moved on", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{
+			tracks: map[string]*androidpublisher.Track{TrackInternal: {Track: TrackInternal, Releases: []*androidpublisher.TrackRelease{
+				{Name: "1.2", Status: StatusCompleted, VersionCodes: []int64{13}},
+			}}},
+			hashes: map[int64]Hashes{11: {Sha256: "a"}, 12: {Sha256: "b"}, 13: {Sha256: "c"}},
+		}
+
+		// Act
+		versions, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", "", TrackBeta, WithPin(pin))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(versions, []int64{11, 12}) {
+			t.Errorf("want pinned [11 12] promoted, got %v", versions)
+		}
+		if len(gs.releases) != 1 || gs.releases[0].Name != "" {
+			t.Errorf("want 1 release without source name, got %+v", gs.releases)
+		}
+	})
+
+	t.Run("should copy release name when pinned release is still on track", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{
+			tracks: map[string]*androidpublisher.Track{TrackInternal: {Track: TrackInternal, Releases: []*androidpublisher.TrackRelease{
+				{Name: "1.1", Status: StatusCompleted, VersionCodes: []int64{12, 11}},
+			}}},
+			hashes: map[int64]Hashes{11: {Sha256: "a"}, 12: {Sha256: "b"}},
+		}
+
+		// Act
+		_, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", "", TrackBeta, WithPin(pin))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.releases[0].Name != "1.1" {
+			t.Errorf("want '1.1' release name, got '%s'", gs.releases[0].Name)
+		}
+	})
+
+	t.Run("should fail when pinned binary digest changed", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{hashes: map[int64]Hashes{11: {Sha256: "a"}, 12: {Sha256: "other"}}}
+
+		// Act
+		_, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", "", TrackBeta, WithPin(pin))
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.commitEditCount != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted without commit, got %d commit %d delete calls", gs.commitEditCount, gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should fail pin of another app", func(t *testing.T) {
+		// Act
+		_, err := Promote(context.Background(), &mockGService{}, afero.NewMemMapFs(), "com.other.app", "", TrackBeta, WithPin(pin))
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package playstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// preflight checks every local input can be read and the temporary file system has room for
+// decompressed mappings, so publish fails before an edit is opened instead of halfway through uploads.
+// Missing inputs are left for Publish to report.
+func (p *publish) preflight(files []binary) error {
+	var (
+		errs []error
+		need int64
+	)
+	seen := make(map[string]bool)
+	for _, f := range files {
+		for _, path := range []string{f.filePath, f.mappingPath, f.mainObb, f.patchObb} {
+			if path == "" || seen[path] || !p.fileExits(path) {
+				continue
+			}
+			seen[path] = true
+			if err := p.checkReadable(path); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if path != f.mappingPath {
+				continue
+			}
+			n, err := p.gzipSize(path)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			need += n
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if need == 0 {
+		return nil
+	}
+
+	dir := os.TempDir()
+	free, ok := diskFree(p.fs, dir)
+	if !ok {
+		p.Debugf("free disk space of '%s' unknown, skipping workspace space check", dir)
+		return nil
+	}
+	p.Debugf("decompressed mappings need %d bytes, %d bytes free in '%s'", need, free, dir)
+	if need > free {
+		return fmt.Errorf("decompressed mappings need %d bytes, but only %d bytes are free in '%s'", need, free, dir)
+	}
+	return nil
+}
+
+// checkReadable fails when file can't be opened and read
+func (p *publish) checkReadable(path string) error {
+	f, err := p.fs.Open(path)
+	if err != nil {
+		return fmt.Errorf("input '%s' is not readable: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := f.Read(make([]byte, 1)); err != nil && err != io.EOF {
+		return fmt.Errorf("input '%s' is not readable: %w", path, err)
+	}
+	return nil
+}
+
+// gzipSize returns decompressed size gzip trailer records, 0 for files that aren't gzipped
+func (p *publish) gzipSize(path string) (int64, error) {
+	f, err := p.fs.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	head := make([]byte, len(gzipMagic))
+	if _, err := io.ReadFull(f, head); err != nil || !bytes.Equal(head, gzipMagic) {
+		return 0, nil
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	// ISIZE, decompressed size modulo 2^32, is the last 4 bytes
+	trailer := make([]byte, 4)
+	if _, err := f.ReadAt(trailer, info.Size()-4); err != nil {
+		return 0, fmt.Errorf("failed reading gzipped mappings '%s' size: %w", path, err)
+	}
+	return int64(trailer[0]) | int64(trailer[1])<<8 | int64(trailer[2])<<16 | int64(trailer[3])<<24, nil
+}
@@ -0,0 +1,87 @@
+package playstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// unreadableFs refuses opening given files, as if their permissions didn't allow reading
+type unreadableFs struct {
+	afero.Fs
+	unreadable map[string]bool
+}
+
+func (fs *unreadableFs) Open(name string) (afero.File, error) {
+	if fs.unreadable[name] {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrPermission}
+	}
+	return fs.Fs.Open(name)
+}
+
+func TestPreflight(t *testing.T) {
+
+	t.Run("should report every unreadable input", func(t *testing.T) {
+		// Arrange
+		mem := afero.NewMemMapFs()
+		mem.Create("auth.json")
+		createMockBinary(t, mem, "test.aab", "mapping.txt")
+		fs := &unreadableFs{Fs: mem, unreadable: map[string]bool{"test.aab": true, "mapping.txt": true}}
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{BinaryWithMapping("test.aab", "mapping.txt")}, false, false)
+
+		// Assert
+		if !errors.Is(err, os.ErrPermission) {
+			t.Fatalf("want permission error, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), "'test.aab'") || !strings.Contains(err.Error(), "'mapping.txt'") {
+			t.Errorf("want both inputs reported, got: %v", err)
+		}
+	})
+
+	t.Run("should fail without room for decompressed mappings", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 10)
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		zw.Write(bytes.Repeat([]byte("com.sample.App -> a:\n"), 100))
+		zw.Close()
+		afero.WriteFile(fs, "mapping.txt.gz", gz.Bytes(), 0o644)
+		diskFree = func(afero.Fs, string) (int64, bool) { return 1000, true }
+		t.Cleanup(func() { diskFree = freeDiskSpace })
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{BinaryWithMapping("test.aab", "mapping.txt.gz")}, false, false)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "need 2100 bytes") {
+			t.Errorf("want disk space error, got: %v", err)
+		}
+	})
+
+	t.Run("should pass readable plain inputs", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "mapping.txt")
+		diskFree = func(afero.Fs, string) (int64, bool) { return 0, true }
+		t.Cleanup(func() { diskFree = freeDiskSpace })
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+
+		// Assert
+		if err != nil {
+			t.Errorf("want no error, got: %v", err)
+		}
+	})
+}
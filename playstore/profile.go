@@ -0,0 +1,82 @@
+package playstore
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// https://developer.android.com/training/wearables/packaging
+	wearTrackPrefix = "wear:"
+
+	// Play rejects apk uploads above this size
+	apkMaxFileSize = 100 << 20
+	// watch installs go over a slow paired connection, anything above this is worth flagging
+	wearWarnFileSize = 20 << 20
+)
+
+// Profile is a preset of publish settings for a specific form factor
+type Profile struct {
+	Name         string
+	TrackPrefix  string // form factor track prefix e.g. 'wear:'
+	Apk          bool   // binaries are published as apk
+	MaxFileSize  int64  // hard binary size limit in bytes, 0 for no limit
+	WarnFileSize int64  // binary size in bytes above which a warning is logged, 0 for none
+}
+
+var (
+	ProfileDefault = Profile{Name: "default"}
+
+	// Wear OS standalone apk published to 'wear:' form factor tracks
+	ProfileWear = Profile{
+		Name:         "wear",
+		TrackPrefix:  wearTrackPrefix,
+		Apk:          true,
+		MaxFileSize:  apkMaxFileSize,
+		WarnFileSize: wearWarnFileSize,
+	}
+)
+
+// ProfileByName returns profile preset for given name e.g. 'wear'
+func ProfileByName(name string) (Profile, error) {
+	switch strings.TrimSpace(strings.ToLower(name)) {
+	case "", ProfileDefault.Name:
+		return ProfileDefault, nil
+	case ProfileWear.Name:
+		return ProfileWear, nil
+	}
+	return Profile{}, fmt.Errorf("profile '%s' not supported. Only supported profiles are '%s' '%s'", name, ProfileDefault.Name, ProfileWear.Name)
+}
+
+// trackName returns track with profile prefix applied, track may already contain the prefix
+func (pr *Profile) trackName(track string) string {
+	return pr.TrackPrefix + strings.TrimPrefix(track, pr.TrackPrefix)
+}
+
+// baseTrack strips profile prefix from track name e.g. 'wear:beta' -> 'beta'
+func (pr *Profile) baseTrack(track string) string {
+	return strings.TrimPrefix(track, pr.TrackPrefix)
+}
+
+// checkFileSize fails if binary goes over profile limit and warns if it goes over recommended size
+func (p *publish) checkFileSize(pr *Profile, filePath string) error {
+	size, err := p.fileSize(filePath)
+	if err != nil {
+		return err
+	}
+	return p.checkSize(pr, filePath, size)
+}
+
+// checkSize checks binary size against max size and profile limits, unknown size of -1 passes
+func (p *publish) checkSize(pr *Profile, filePath string, size int64) error {
+	if p.maxSize > 0 && size > p.maxSize {
+		return fmt.Errorf("binary file '%s' is %d bytes, exceeding max size of %d bytes", filePath, size, p.maxSize)
+	}
+	if pr.MaxFileSize > 0 && size > pr.MaxFileSize {
+		return fmt.Errorf("binary file '%s' is %d bytes, exceeding '%s' profile limit of %d bytes", filePath, size, pr.Name, pr.MaxFileSize)
+	}
+	if pr.WarnFileSize > 0 && size > pr.WarnFileSize {
+		p.Warnf("binary file '%s' is %d bytes, '%s' profile recommends staying below %d bytes", filePath, size, pr.Name, pr.WarnFileSize)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package playstore
+
+import (
+	"io"
+	"log"
+	"time"
+
+	"github.com/mitchellh/ioprogress"
+)
+
+// ProgressFunc receives upload progress of a file, sent and total in bytes
+type ProgressFunc func(filePath string, sent, total int64)
+
+// WithProgressFunc reports upload progress of binaries, mappings and expansion files to fn instead of drawing
+// it to terminal, e.g. for GUI tools and CI plugins to render their own. fn is called from parallel uploads.
+func WithProgressFunc(fn ProgressFunc) Option {
+	return func(p *publish) {
+		p.progressFunc = fn
+	}
+}
+
+// WithProgressInterval sets how often upload progress is reported, 3s by default, and how many bytes
+// have to be sent since last report for a new one, 0 for any. Finished upload is always reported.
+func WithProgressInterval(interval time.Duration, minBytes int64) Option {
+	return func(p *publish) {
+		p.progressInterval = interval
+		p.progressMinBytes = minBytes
+	}
+}
+
+// reportProgress reports progress of upload which isn't read through progressReader, e.g. resumable one
+// acknowledged chunk by chunk
+func (p *publish) reportProgress(filePath string, sent, total int64) {
+	p.reportStatus(filePath, sent, total)
+	if p.progressFunc != nil {
+		p.progressFunc(filePath, sent, total)
+	}
+}
+
+// progressReader reports progress of reading file content for upload
+func (p *publish) progressReader(filePath string, r io.Reader, size int64) io.Reader {
+	interval := p.progressInterval
+	if interval <= 0 {
+		interval = uploadProgressDrawInterval
+	}
+
+	draw := ioprogress.DrawTerminalf(log.Writer(), ioprogress.DrawTextFormatBytes)
+	if p.parallel() {
+		// progress bars of parallel uploads overwrite each other, so each report gets a line of its own
+		draw = func(progress, total int64) error {
+			if progress >= 0 {
+				p.fileInfof(filePath, "%s", ioprogress.DrawTextFormatBytes(progress, total))
+			}
+			return nil
+		}
+	}
+	if p.progressFunc != nil {
+		draw = func(progress, total int64) error {
+			// -1 marks end of progress bar, nothing to report
+			if progress >= 0 {
+				p.progressFunc(filePath, progress, total)
+			}
+			return nil
+		}
+	}
+
+	var reported int64
+	return &ioprogress.Reader{
+		Reader: r,
+		Size:   size,
+		DrawFunc: func(progress, total int64) error {
+			if progress >= 0 && progress != total && progress != 0 && progress-reported < p.progressMinBytes {
+				return nil
+			}
+			if progress >= 0 {
+				reported = progress
+				p.reportStatus(filePath, progress, total)
+			}
+			return draw(progress, total)
+		},
+		DrawInterval: interval,
+	}
+}
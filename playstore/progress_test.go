@@ -0,0 +1,81 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestProgressReader(t *testing.T) {
+
+	t.Run("should report progress to callback skipping small deltas", func(t *testing.T) {
+		// Arrange
+		reports := make([]int64, 0)
+		p := &publish{}
+		WithProgressFunc(func(filePath string, sent, total int64) {
+			reports = append(reports, sent)
+		})(p)
+		WithProgressInterval(time.Nanosecond, 50)(p)
+		r := p.progressReader("app.aab", io.LimitReader(bytes.NewReader(make([]byte, 100)), 100), 100)
+
+		// Act
+		buf := make([]byte, 10)
+		for {
+			if _, err := r.Read(buf); err != nil {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		// Assert
+		if len(reports) == 0 || reports[len(reports)-1] != 100 {
+			t.Fatalf("want finished upload reported, got %v", reports)
+		}
+		for i := 1; i < len(reports)-1; i++ {
+			if reports[i]-reports[i-1] < 50 {
+				t.Errorf("want at least 50 bytes between reports, got %v", reports)
+			}
+		}
+	})
+
+	t.Run("should report finished binary, mapping and expansion uploads to callback", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.apk", 10)
+		createTestFile(t, fs, "mapping.txt", 20)
+		createTestFile(t, fs, "main.obb", 30)
+		var mu sync.Mutex
+		finished := make(map[string]int64)
+		bin := Expanded(BinaryWithMapping("app.apk", "mapping.txt"), "main.obb", "")
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, true, false,
+			WithProgressFunc(func(filePath string, sent, total int64) {
+				mu.Lock()
+				defer mu.Unlock()
+				if sent == total {
+					finished[filePath] = total
+				}
+			}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		_, err = publish.UploadFiles(context.Background(), &mockGService{AppVersionCode: 7})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		for file, size := range map[string]int64{"app.apk": 10, "mapping.txt": 20, "main.obb": 30} {
+			if finished[file] != size {
+				t.Errorf("want '%s' %d bytes reported, got %v", file, size, finished)
+			}
+		}
+	})
+}
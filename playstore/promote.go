@@ -0,0 +1,179 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+/**
+ * Promote assigns appVersions of the latest release on one track to another, without uploading anything
+ *
+ * fs - file system changelogs are read from, see WithChangelogs
+ * fromTrack - track to take release from e.g. 'internal'
+ * toTrack - track to release to e.g. 'beta', production needs AllowProduction()
+ * opts - release options e.g. WithRolloutFraction(0.1), ReleaseNotes(...) or WithChangelogs(...) and WithReleaseName(...)
+ *        for fresh notes and name, source release notes and name are copied if none set.
+ *        WithPin(...) promotes pinned appVersions from the pinned track instead of latest release on fromTrack
+ *        WithCountries(...) narrows release to given countries, expanded later with ExpandCountries
+ *
+ * returns appVersion codes promoted
+ */
+func Promote(ctx context.Context, gs IGService, fs afero.Fs, packageName, fromTrack, toTrack string, opts ...Option) ([]int64, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+
+	p := &publish{fs: fs}
+	for _, o := range opts {
+		o(p)
+	}
+	pr := p.profile
+	if pr == nil {
+		pr = &ProfileDefault
+	}
+
+	name, err := validatePackageName(packageName, p.anyPackageName)
+	if err != nil {
+		return nil, err
+	}
+	if p.pin != nil {
+		if err := p.pin.validate(); err != nil {
+			return nil, err
+		}
+		if p.pin.PackageName != name {
+			return nil, fmt.Errorf("pin is for '%s', not '%s'", p.pin.PackageName, name)
+		}
+		fromTrack = p.pin.Track
+	}
+	from := strings.TrimSpace(strings.ToLower(fromTrack))
+	if from == "" {
+		return nil, fmt.Errorf("track name to promote release from is required")
+	}
+	from = pr.trackName(from)
+	to, err := p.targetTrack(pr, toTrack)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("release can't be promoted to the same '%s' track", to)
+	}
+	if err := p.validateRelease(); err != nil {
+		return nil, err
+	}
+	p.packageName = name
+	p.track = to
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkTrack(ctx, gs, edit, to); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+
+	track, err := gs.getTrack(ctx, name, edit, from)
+	if err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("failed reading '%s' track: %w", from, err)
+	}
+	var versions []int64
+	source := latestRelease(track)
+	if p.pin != nil {
+		versions, source, err = p.pinnedRelease(ctx, gs, edit, track)
+		if err != nil {
+			gs.deleteEdit(ctx, name, edit)
+			return nil, err
+		}
+	} else if source == nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("'%s' track has no release to promote", from)
+	} else {
+		versions = source.VersionCodes
+	}
+
+	release, err := p.release(versions)
+	if err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if source != nil {
+		if len(release.ReleaseNotes) == 0 {
+			release.ReleaseNotes = source.ReleaseNotes
+		}
+		if release.Name == "" {
+			release.Name = source.Name
+		}
+	}
+
+	if err := gs.createRelease(ctx, name, edit, to, release); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if err := gs.validateEdit(ctx, name, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if err := p.commit(ctx, gs, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	return versions, nil
+}
+
+// pinnedRelease verifies pinned binaries against Play and returns their appVersions with
+// the source track release they are still in, if track hasn't moved on since
+func (p *publish) pinnedRelease(ctx context.Context, gs IGService, edit string, track *androidpublisher.Track) ([]int64, *androidpublisher.TrackRelease, error) {
+	hashes, err := gs.versionHashes(ctx, p.packageName, edit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed reading uploaded binaries: %w", err)
+	}
+	if err := p.pin.verify(hashes); err != nil {
+		return nil, nil, err
+	}
+	versions := p.pin.versionCodes()
+	for _, r := range track.Releases {
+		if sameVersionCodes(r.VersionCodes, versions) {
+			return versions, r, nil
+		}
+	}
+	p.Warnf("pinned appVersions %v are no longer released on '%s' track, promoting them without its release name and notes", versions, track.Track)
+	return versions, nil, nil
+}
+
+// sameVersionCodes checks both contain the same appVersion codes in any order
+func sameVersionCodes(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[int64]int, len(a))
+	for _, v := range a {
+		seen[v]++
+	}
+	for _, v := range b {
+		if seen[v] == 0 {
+			return false
+		}
+		seen[v]--
+	}
+	return true
+}
+
+// latestRelease returns release with the highest appVersion, skipping halted releases
+func latestRelease(track *androidpublisher.Track) *androidpublisher.TrackRelease {
+	var latest *androidpublisher.TrackRelease
+	for _, r := range track.Releases {
+		if r.Status == StatusHalted || len(r.VersionCodes) == 0 {
+			continue
+		}
+		if latest == nil || maxVersionCode(r) > maxVersionCode(latest) {
+			latest = r
+		}
+	}
+	return latest
+}
@@ -0,0 +1,125 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestPromote(t *testing.T) {
+
+	internal := func() *androidpublisher.Track {
+		return &androidpublisher.Track{
+			Track: TrackInternal,
+			Releases: []*androidpublisher.TrackRelease{
+				{Name: "1.0", Status: StatusCompleted, VersionCodes: []int64{10}},
+				{Name: "1.1", Status: StatusCompleted, VersionCodes: []int64{11, 12}, ReleaseNotes: []*androidpublisher.LocalizedText{{Language: "en-US", Text: "Fixes"}}},
+			},
+		}
+	}
+
+	t.Run("should assign latest source release version codes to target track", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackInternal: internal()}}
+
+		// Act
+		versions, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", TrackInternal, TrackBeta)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(versions, []int64{11, 12}) {
+			t.Errorf("want [11 12] promoted, got %v", versions)
+		}
+		if gs.releaseTrack != TrackBeta || len(gs.releases) != 1 {
+			t.Fatalf("want 1 release on '%s', got %d on '%s'", TrackBeta, len(gs.releases), gs.releaseTrack)
+		}
+		r := gs.releases[0]
+		if r.Name != "1.1" || len(r.ReleaseNotes) != 1 || r.ReleaseNotes[0].Text != "Fixes" {
+			t.Errorf("want source release name and notes copied, got %+v", r)
+		}
+		if gs.uploadBundleCallCount != 0 || gs.uploadApkCallCount != 0 {
+			t.Error("want no uploads")
+		}
+		if gs.commitEditCount != 1 {
+			t.Errorf("want 1 commitEdit call, got %d", gs.commitEditCount)
+		}
+	})
+
+	t.Run("should release with fresh notes and name instead of copying source ones", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackInternal: internal()}}
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "changelogs/12/de-DE.txt", []byte("Fehlerbehebungen"), 0o644)
+
+		// Act
+		_, err := Promote(context.Background(), gs, fs, "com.test.app", TrackInternal, TrackBeta,
+			ReleaseNotes(map[string]string{"en-US": "New look"}), WithChangelogs("changelogs"), WithReleaseName("1.1 beta"))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		r := gs.releases[0]
+		notes := make(map[string]string)
+		for _, n := range r.ReleaseNotes {
+			notes[n.Language] = n.Text
+		}
+		if r.Name != "1.1 beta" || !reflect.DeepEqual(notes, map[string]string{"en-US": "New look", "de-DE": "Fehlerbehebungen"}) {
+			t.Errorf("want fresh name and notes, got '%s' %v", r.Name, notes)
+		}
+	})
+
+	t.Run("should refuse release name Play doesn't accept", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackInternal: internal()}}
+
+		// Act
+		_, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", TrackInternal, TrackBeta, WithReleaseName(strings.Repeat("a", 51)))
+
+		// Assert
+		if err == nil || err.Error() != "release name must be at most 50 characters, got 51" {
+			t.Errorf("want release name error, got: %v", err)
+		}
+		if gs.createEditCount != 0 {
+			t.Error("want no edit created")
+		}
+	})
+
+	t.Run("should require confirmation for production", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackInternal: internal()}}
+
+		// Act
+		_, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", TrackInternal, TrackProduction)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.createEditCount != 0 {
+			t.Errorf("want no edits created, got %d", gs.createEditCount)
+		}
+	})
+
+	t.Run("should fail when source track has no release", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		_, err := Promote(context.Background(), gs, afero.NewMemMapFs(), "com.test.app", TrackInternal, TrackBeta)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted, got %d deleteEdit calls", gs.deleteEditCount)
+		}
+	})
+}
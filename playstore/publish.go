@@ -1,24 +1,40 @@
 package playstore
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"net/http"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/mitchellh/ioprogress"
+	"github.com/sigitas-plk/playstore/playstore/apkparse"
 	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
 )
 
 const (
 	uploadProgressDrawInterval = 3 * time.Second
+	// conservative upload throughput used to estimate if uploads finish before edit expires
+	expectedUploadRate = 1 << 20 // bytes per second
+	// highest in-app update priority Play accepts
+	maxUpdatePriority = 5
 )
 
 // binary aab or apk file and its mappings path
 type binary struct {
 	filePath    string
 	mappingPath string
+	priority    int
+	// apk expansion files
+	mainObb, patchObb string
+	// release notes lines of the binary by locale, see Noted
+	notes map[string]string
+	// content streamed instead of read from filePath, see BinaryFromReader
+	stream *streamSource
 }
 
 func BinaryWithMapping(path, mappingPath string) binary {
@@ -28,6 +44,11 @@ func BinaryWithMapping(path, mappingPath string) binary {
 	}
 }
 
+// BinaryWithExpansion apk with main and/or patch obb expansion files, empty path for none
+func BinaryWithExpansion(path, mainObb, patchObb string) binary {
+	return Expanded(Binary(path), mainObb, patchObb)
+}
+
 func Binary(path string) binary {
 	return binary{
 		filePath:    path,
@@ -35,10 +56,17 @@ func Binary(path string) binary {
 	}
 }
 
+// Binaries returns binaries of binary path to mappings path map, sorted by path so repeated runs
+// upload, log and report them the same way
 func Binaries(bins map[string]string) []binary {
-	b := make([]binary, 0)
-	for k, v := range bins {
-		b = append(b, BinaryWithMapping(k, v))
+	paths := make([]string, 0, len(bins))
+	for k := range bins {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+	b := make([]binary, 0, len(paths))
+	for _, k := range paths {
+		b = append(b, BinaryWithMapping(k, bins[k]))
 	}
 	return b
 }
@@ -56,51 +84,269 @@ type publish struct {
 	apk         bool
 	verbose     bool
 	fs          afero.Fs
+	profile     *Profile
+	// retry commit with changesNotSentForReview when Play refuses to send changes for review
+	reviewFallback bool
+	// commit with changesNotSentForReview right away, changes are sent for review from Play Console
+	notSentForReview bool
+	// upload again in new edit once when edit expires before publish finishes
+	recreateEdit bool
+	// declared managed publishing setting and whether commit errors contradicting it fail publishing
+	managedPublishing       string
+	managedPublishingStrict bool
+	// production track publishing explicitly acknowledged
+	allowProduction bool
+	// order binaries are uploaded in
+	uploadOrder string
+	// share of users staged rollout release goes to, 0 for draft release
+	rolloutFraction float64
+	// release status, draft or inProgress with rollout fraction if not set
+	releaseStatus string
+	// in-app update priority of the release, 0 to 5, Play default 0 if not set
+	updatePriority int
+	// countries release is available in, everywhere if not set
+	countries []string
+	// release also becomes available in countries Play adds later, with countries only
+	restOfWorld bool
+	// only create draft release, refusing options that roll it out
+	draftOnly bool
+	// release committed by UploadFiles
+	created *androidpublisher.TrackRelease
+	// release notes by locale and changelogs directory to read them from
+	releaseNotes  map[string]string
+	changelogsDir string
+	// name of the track release, Play default if not set
+	releaseName string
+	// rules release notes are checked against, fails publish on violations if strict
+	notesLint       *NotesLint
+	notesLintStrict bool
+	// told outcome of UploadFiles, see WithNotifier
+	notifier Notifier
+	// binary size budget in bytes, 0 for none
+	maxSize int64
+	// validate edit and delete it instead of committing
+	dryRun bool
+	// upload progress reporting, terminal progress bar every 3s if not set
+	progressFunc     ProgressFunc
+	progressInterval time.Duration
+	progressMinBytes int64
+	// live upload progress kept for external systems, see WithStatusFile
+	statusFile *statusFile
+	// upload binary again when Play reports different digest
+	integrityRetry bool
+	// mapping of binaries without their own, see SharedMapping
+	sharedMapping string
+	// digests of mappings and expansion files recorded with fileDigests
+	fileDigests bool
+	digests     *fileDigests
+	// non fatal events summarized once upload is done, guarded by softFailuresMu
+	softFailures []SoftFailure
+	// release binaries Play has already instead of failing
+	skipExisting bool
+	// manifests read from binaries by path, missing ones couldn't be read
+	manifests map[string]*apkparse.Manifest
+	// workers uploading binaries and mappings
+	concurrency Concurrency
+	// binaries Promote has to release, instead of latest source track release
+	pin *Pin
+	// state file recording binary upload progress and its loaded state, edit is kept on failure with it
+	resumeFile string
+	resume     *checkpoint
+	// where state files go, fs binaries are read from if not set
+	outputFs afero.Fs
+	// accept closed testing tracks app has on Play besides the standard ones
+	customTracks bool
+	// skip Android application ID rules, see WithAnyPackageName
+	anyPackageName bool
+	// read app details before uploading, see WithAccessCheck
+	accessCheck bool
+	// identifies upload run in logs, receipts and abort reports
+	runID string
+	// edit binaries were uploaded to, set once UploadFiles opened it
+	editID string
+	// remote artifacts are fetched with download client, copies kept after upload with keepDownloads
+	downloadClient *http.Client
+	keepDownloads  bool
+	// where progress and diagnostics go, stderr if not set
+	logger Logger
+	// temporary files of a running upload, removed once it's done
+	ws *workspace
+}
+
+// Option sets optional publish configuration
+type Option func(*publish)
+
+// WithProfile applies form factor preset e.g. ProfileWear
+func WithProfile(pr Profile) Option {
+	return func(p *publish) {
+		p.profile = &pr
+	}
+}
+
+// WithNotSentForReviewFallback retries commit without sending changes for review,
+// when Play refuses to send them for review automatically
+func WithNotSentForReviewFallback() Option {
+	return func(p *publish) {
+		p.reviewFallback = true
+	}
+}
+
+// WithChangesNotSentForReview commits changes without sending them for review, e.g. when policy requires
+// submitting them manually from Play Console later
+func WithChangesNotSentForReview() Option {
+	return func(p *publish) {
+		p.notSentForReview = true
+	}
+}
+
+// AllowProduction acknowledges binaries will be published to production track, which Publish refuses otherwise
+func AllowProduction() Option {
+	return func(p *publish) {
+		p.allowProduction = true
+	}
+}
+
+// DryRun uploads binaries and validates the edit, but deletes it instead of committing
+func DryRun() Option {
+	return func(p *publish) {
+		p.dryRun = true
+	}
+}
+
+// WithRolloutFraction releases binaries as staged rollout to given share of users e.g. 0.05
+func WithRolloutFraction(fraction float64) Option {
+	return func(p *publish) {
+		p.rolloutFraction = fraction
+	}
+}
+
+// WithReleaseStatus sets status of created release: draft, completed, inProgress or halted.
+// inProgress needs WithRolloutFraction, draft and completed can't have one. Not set releases
+// a draft, or inProgress when rollout fraction is set.
+func WithReleaseStatus(status string) Option {
+	return func(p *publish) {
+		p.releaseStatus = status
+	}
+}
+
+// WithUpdatePriority sets in-app update priority of created release, from 0 default to 5 highest. Apps
+// using in-app updates read it to prompt immediate update e.g. for critical fixes.
+func WithUpdatePriority(priority int) Option {
+	return func(p *publish) {
+		p.updatePriority = priority
+	}
 }
 
 /**
  * Publish configuration of what should be uploaded
  *
+ * ctx - cancels binary validation
  * fs - file system to enable easier testing
  * packageName - binary package name e.g. com.sample.app (you'll need at least one app submition)
  * track - which track this binary should be published to e.g. 'internal'
- * files - file(s) to be uploaded
+ * files - file(s) to be uploaded, https:// or gs:// URLs are downloaded first, see WithKeepDownloads
+ * opts - optional configuration e.g. WithProfile(ProfileWear)
  */
-func Publish(fs afero.Fs, packageName, track, authFile string, files []binary, apk bool, verbose bool) (*publish, error) {
+func Publish(ctx context.Context, fs afero.Fs, packageName, track, authFile string, files []binary, apk bool, verbose bool, opts ...Option) (*publish, error) {
 
 	p := &publish{
 		verbose: verbose,
 		fs:      fs,
 	}
+	for _, o := range opts {
+		o(p)
+	}
+	pr := p.profile
+	if pr == nil {
+		pr = &ProfileDefault
+	}
 
-	if !p.fileExits(authFile) {
-		return nil, fmt.Errorf("authentication file '%s' does not exist", authFile)
+	// no auth file when credentials come from environment, see CredentialsFromEnv
+	if authFile != "" && !p.fileExits(authFile) {
+		return nil, errorOf(ErrAuthFileMissing, "authentication file '%s' does not exist", authFile)
 	}
 
-	name := strings.TrimSpace(packageName)
-	if name == "" {
-		return nil, fmt.Errorf("package name must not be empty")
+	name, err := validatePackageName(packageName, p.anyPackageName)
+	if err != nil {
+		return nil, err
 	}
 
-	t := strings.TrimSpace(strings.ToLower(track))
-	if t == "" {
-		return nil, fmt.Errorf("track name to publish binary to is required")
+	t, err := p.targetTrack(pr, track)
+	if err != nil {
+		return nil, err
 	}
-	if t != TrackBeta && t != TrackAlpha && t != TrackInternal {
-		return nil, fmt.Errorf("provided track type '%s' not supported. Only supported types are '%s' '%s' '%s'", t, TrackBeta, TrackAlpha, TrackInternal)
+
+	if pr.Apk && !apk {
+		p.Warnf("'%s' profile publishes apk binaries, treating all files as apk", pr.Name)
+		apk = true
 	}
 
 	if len(files) == 0 {
 		return nil, errors.New("no files to upload provided")
 	}
+	files = p.withSharedMapping(files)
+	if err := validateUploadOrder(p.uploadOrder); err != nil {
+		return nil, err
+	}
+	if err := p.validateRelease(); err != nil {
+		return nil, err
+	}
+
+	// downloads are removed with workspace, unless publish is ready for upload
+	ready := false
+	defer func() {
+		if !ready && p.ws != nil {
+			p.ws.cleanup()
+		}
+	}()
+	files, err = p.downloadRemote(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.preflight(files); err != nil {
+		return nil, err
+	}
 
 	for _, f := range files {
-		if !p.fileExits(f.filePath) {
-			return nil, fmt.Errorf("binary file '%s' does not exist", f.filePath)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if f.stream != nil {
+			if f.stream.reader == nil && !isRemote(f.filePath) {
+				return nil, fmt.Errorf("streamed binary '%s' must be https, http or gs URL", f.filePath)
+			}
+			if err := p.checkSize(pr, f.filePath, f.stream.size); err != nil {
+				return nil, err
+			}
+			p.skippedCheckf(false, "binary '%s' is streamed, skipping its signing and manifest checks", f.filePath)
+		} else {
+			if !p.fileExits(f.filePath) {
+				return nil, fmt.Errorf("binary file '%s' does not exist", f.filePath)
+			}
+			if err := p.checkFileSize(pr, f.filePath); err != nil {
+				return nil, err
+			}
+			debug, err := isDebugSigned(p.fs, f.filePath)
+			if err != nil {
+				return nil, err
+			}
+			if debug {
+				return nil, fmt.Errorf("binary file '%s' is signed with Android debug certificate, Play accepts only release signed binaries", f.filePath)
+			}
+			p.readManifest(f.filePath, name)
 		}
 		if f.mappingPath != "" && !p.fileExits(f.mappingPath) {
 			return nil, fmt.Errorf("mappings file '%s' does not exist", f.mappingPath)
 		}
+		if err := p.validateExpansion(f, apk); err != nil {
+			return nil, err
+		}
+		if err := validateReleaseNotes(f.notes); err != nil {
+			return nil, fmt.Errorf("binary '%s' %w", f.filePath, err)
+		}
+	}
+	if err := p.lintNotes(files); err != nil {
+		return nil, err
 	}
 
 	p.files = files
@@ -108,6 +354,7 @@ func Publish(fs afero.Fs, packageName, track, authFile string, files []binary, a
 	p.packageName = name
 	p.track = t
 	p.apk = apk
+	ready = true
 	return p, nil
 }
 
@@ -116,109 +363,458 @@ func Publish(fs afero.Fs, packageName, track, authFile string, files []binary, a
  *
  * 1. creates an edit
  * 2. runs through list of files and uploads binaries + mappings if provided
- * 3. commits an edit
+ * 3. commits an edit, or deletes it in dry run once validated
  */
-func (p *publish) UploadFiles(gs IGService) error {
+func (p *publish) UploadFiles(ctx context.Context, gs IGService) ([]UploadResult, error) {
 
 	if gs == nil {
-		return errors.New("no Google Playstore service instance provided")
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	if p.runID == "" {
+		id, err := newRunID()
+		if err != nil {
+			return nil, err
+		}
+		p.runID = id
 	}
+	p.Infof("Run ID: %s", p.runID)
 	p.Debugf("starting file upload")
-	edit, err := gs.createEdit(p.packageName)
-	if err != nil {
-		return err
+	started := time.Now()
+	countRetries := p.countRetries(gs)
+	defer func() {
+		countRetries()
+		p.printSoftFailures()
+	}()
+	// workspace of remote artifacts Publish downloaded is reused, so they are removed once upload is done
+	if p.ws == nil || p.ws.removed {
+		p.ws = newWorkspace(p.fs)
+	}
+	defer func() {
+		if err := p.ws.cleanup(); err != nil {
+			p.Warnf("failed removing temporary workspace: %v", err)
+		}
+	}()
+	if p.resumeFile != "" {
+		c, err := loadCheckpoint(p.outFs(), p.resumeFile, p.packageName)
+		if err != nil {
+			return nil, err
+		}
+		p.resume = c
+	}
+	results, err := p.uploadInEdit(ctx, gs)
+	// edit which may have been committed is never uploaded again, it could publish the release twice
+	if err != nil && p.recreateEdit && errors.Is(err, ErrEditExpired) && !errors.Is(err, ErrCommitUncertain) {
+		p.fallbackf("edit '%s' expired before publish finished, uploading again in new edit", p.editID)
+		results, err = p.uploadInEdit(ctx, gs)
 	}
-	p.Debugf("created edit on playstore with editId: %s", edit)
+	p.finishStatus(err)
+	p.notify(results, err, time.Since(started))
+	return results, err
+}
 
-	versions := make([]int64, 0)
-	for _, f := range p.files {
+// uploadInEdit opens edit and uploads files to it, committing once they are released to the track
+func (p *publish) uploadInEdit(ctx context.Context, gs IGService) ([]UploadResult, error) {
+	edit, err := p.openEdit(ctx, gs)
+	if err != nil {
+		if p.accessCheck {
+			return nil, accessError(p.packageName, err)
+		}
+		return nil, err
+	}
+	p.editID = edit.ID()
+	p.Debugf("using edit on playstore with editId: %s", edit.ID())
+	p.checkEditExpiry(edit.ExpiresAt())
+	if p.accessCheck {
+		if err := p.checkAccess(ctx, gs, edit.ID()); err != nil {
+			return nil, p.abort(edit, nil, err)
+		}
+	}
+	if err := p.checkTrack(ctx, gs, edit.ID(), p.track); err != nil {
+		return nil, p.abort(edit, nil, err)
+	}
 
-		v, err := p.upload(gs, f.filePath, edit, p.apk)
+	if p.fileDigests {
+		p.digests = &fileDigests{byPath: make(map[string]FileDigest)}
+	}
+	files := p.orderedFiles()
+	existing, err := p.existingVersions(ctx, gs, edit.ID(), files)
+	if err != nil {
+		return nil, p.abort(edit, nil, err)
+	}
+	if p.allLive(ctx, gs, edit.ID(), files, existing) {
+		return p.finishLive(ctx, edit, files, existing), nil
+	}
+	// results by file position, so release keeps upload order whatever order uploads finish in
+	results := make([]*UploadResult, len(files))
+	err = runParallel(ctx, p.concurrency.Uploads, len(files), func(ctx context.Context, i int) error {
+		if r, ok := existing[i]; ok {
+			results[i] = &r
+			return nil
+		}
+		started := time.Now()
+		upload := p.upload
+		switch {
+		case files[i].stream != nil:
+			upload = func(ctx context.Context, edit *Edit, _ string, isApk bool) (int64, Hashes, error) {
+				return p.uploadStream(ctx, edit, files[i], isApk)
+			}
+		case p.resume != nil:
+			upload = p.resumableUpload
+		}
+		v, h, err := upload(ctx, edit, files[i].filePath, p.apk)
 		if err != nil {
-			gs.deleteEdit(p.packageName, edit)
 			return err
 		}
-		versions = append(versions, v)
+		results[i] = &UploadResult{Path: files[i].filePath, VersionCode: v, Sha256: h.Sha256, Sha1: h.Sha1, Duration: time.Since(started)}
+		return nil
+	})
+
+	versions := make([]int64, 0)
+	uploaded := make([]string, 0)
+	uploadResults := make([]UploadResult, 0)
+	// mapping path -> version codes sharing it, so a mapping is read once for multi-apk releases
+	mappings := make(map[string][]int64)
+	mappingOrder := make([]string, 0)
+	for i, f := range files {
+		r := results[i]
+		if r == nil {
+			continue
+		}
+		versions = append(versions, r.VersionCode)
+		uploadResults = append(uploadResults, *r)
+		if r.Skipped {
+			continue
+		}
+		uploaded = append(uploaded, f.filePath)
 		if f.mappingPath == "" {
-			p.Debugf("No mappings provided, skipping mapping upload for this file.")
+			p.Debugf("No mappings provided, skipping mapping upload for '%s'.", f.filePath)
 			continue
 		}
+		if _, ok := mappings[f.mappingPath]; !ok {
+			mappingOrder = append(mappingOrder, f.mappingPath)
+		}
+		mappings[f.mappingPath] = append(mappings[f.mappingPath], r.VersionCode)
+	}
+	if err != nil {
+		return nil, p.abort(edit, uploaded, expiredEdit(edit, err))
+	}
 
-		if err := p.uploadMapping(gs, f.mappingPath, edit, v); err != nil {
-			gs.deleteEdit(p.packageName, edit)
+	mappingDone := make([]bool, len(mappingOrder))
+	err = runParallel(ctx, p.concurrency.Mappings, len(mappingOrder), func(ctx context.Context, i int) error {
+		if err := p.uploadMapping(ctx, gs, mappingOrder[i], edit.ID(), mappings[mappingOrder[i]]); err != nil {
 			return err
 		}
+		mappingDone[i] = true
+		return nil
+	})
+	for i, m := range mappingOrder {
+		if mappingDone[i] {
+			uploaded = append(uploaded, m)
+		}
+	}
+	if err != nil {
+		return nil, p.abort(edit, uploaded, expiredEdit(edit, err))
+	}
+
+	for i, f := range files {
+		if results[i].Skipped {
+			continue
+		}
+		done, err := p.uploadExpansion(ctx, gs, f, edit.ID(), results[i].VersionCode)
+		uploaded = append(uploaded, done...)
+		if err != nil {
+			return nil, p.abort(edit, uploaded, expiredEdit(edit, err))
+		}
+	}
+	// every binary has its result once uploads succeeded, in file order
+	for i, f := range files {
+		p.attachDigests(&uploadResults[i], f)
+	}
+
+	release, err := p.release(versions)
+	if err != nil {
+		return nil, p.abort(edit, uploaded, expiredEdit(edit, err))
+	}
+	p.Debugf("creating '%s' release on '%s' track for appVersions %v", release.Status, p.track, versions)
+	if err := edit.SetTrack(ctx, p.track, release); err != nil {
+		return nil, p.abort(edit, uploaded, expiredEdit(edit, err))
 	}
 
 	p.Debugf("validating app submittion")
-	if err := gs.validateEdit(p.packageName, edit); err != nil {
-		gs.deleteEdit(p.packageName, edit)
-		return err
+	if err := edit.Validate(ctx); err != nil {
+		return nil, p.abort(edit, uploaded, expiredEdit(edit, err))
+	}
+
+	if p.dryRun {
+		if err := edit.Delete(ctx); err != nil {
+			return nil, fmt.Errorf("dry run validated, but failed deleting edit '%s': %w", edit.ID(), err)
+		}
+		p.Infof("Dry run passed validation, edit deleted without committing.")
+		p.removeCheckpoint()
+		return uploadResults, nil
+	}
+
+	if err := p.commitWith(func(changesNotSentForReview bool) error { return edit.Commit(ctx, changesNotSentForReview) }); err != nil {
+		// once commit was made it's not known nothing was published, so its errors are never taken for expired edit
+		return nil, p.abort(edit, uploaded, err)
+	}
+	p.created = release
+	p.removeCheckpoint()
+
+	for _, r := range uploadResults {
+		p.Debugf("uploaded '%s' appVersionCode %d sha256 %s sha1 %s in %s", r.Path, r.VersionCode, r.Sha256, r.Sha1, r.Duration.Round(time.Millisecond))
+	}
+	p.Infof("All files uploaded successfully.")
+	if p.draftOnly {
+		p.Infof("Draft release on '%s' track with appVersions %v is ready to complete in Play Console.", p.track, versions)
+	}
+	return uploadResults, nil
+}
+
+// targetTrack validates track binaries are released to and returns it with profile prefix applied
+func (p *publish) targetTrack(pr *Profile, track string) (string, error) {
+	t := strings.TrimSpace(strings.ToLower(track))
+	if t == "" {
+		return "", fmt.Errorf("track name to publish binary to is required")
+	}
+	b := pr.baseTrack(t)
+	if b == TrackProduction && !p.allowProduction {
+		return "", fmt.Errorf("publishing to '%s' track requires explicit confirmation", t)
+	}
+	if !isStandardTrack(b) && !p.customTracks {
+		return "", errorOf(ErrUnsupportedTrack, "provided track type '%s' not supported. Only supported types are '%s' '%s' '%s' '%s'", t, TrackBeta, TrackAlpha, TrackInternal, TrackProduction)
 	}
+	if b == TrackProduction {
+		p.Warnf("publishing to '%s' track", t)
+	}
+	return pr.trackName(t), nil
+}
 
-	if err := gs.commitEdit(p.packageName, edit); err != nil {
-		gs.deleteEdit(p.packageName, edit)
+// validateRelease validates track release options
+func (p *publish) validateRelease() error {
+	if p.rolloutFraction < 0 || p.rolloutFraction >= 1 {
+		return fmt.Errorf("rollout fraction must be greater than 0 and less than 1, got %v", p.rolloutFraction)
+	}
+	if err := p.validateReleaseStatus(); err != nil {
+		return err
+	}
+	if p.updatePriority < 0 || p.updatePriority > maxUpdatePriority {
+		return fmt.Errorf("in-app update priority must be from 0 to %d, got %d", maxUpdatePriority, p.updatePriority)
+	}
+	if err := p.validateCountries(); err != nil {
+		return err
+	}
+	if err := validateManagedPublishing(p.managedPublishing); err != nil {
+		return err
+	}
+	if err := p.validateDraftOnly(); err != nil {
+		return err
+	}
+	if err := validateReleaseNotes(p.releaseNotes); err != nil {
 		return err
 	}
+	if n := utf8.RuneCountInString(p.releaseName); n > releaseNameMaxLength {
+		return fmt.Errorf("release name must be at most %d characters, got %d", releaseNameMaxLength, n)
+	}
+	if p.changelogsDir != "" {
+		if ok, _ := afero.DirExists(p.fs, p.changelogsDir); !ok {
+			return fmt.Errorf("changelogs directory '%s' does not exist", p.changelogsDir)
+		}
+	}
+	return nil
+}
+
+// validateReleaseStatus checks release status is known and fits rollout fraction
+func (p *publish) validateReleaseStatus() error {
+	switch p.releaseStatus {
+	case "":
+		return nil
+	case StatusDraft, StatusCompleted:
+		if p.rolloutFraction > 0 {
+			return fmt.Errorf("'%s' release can't have rollout fraction, use '%s' for staged rollout", p.releaseStatus, StatusInProgress)
+		}
+	case StatusInProgress:
+		if p.rolloutFraction == 0 {
+			return fmt.Errorf("'%s' release needs rollout fraction", StatusInProgress)
+		}
+	case StatusHalted:
+	default:
+		return fmt.Errorf("release status '%s' not supported, use one of '%s', '%s', '%s' or '%s'", p.releaseStatus, StatusDraft, StatusCompleted, StatusInProgress, StatusHalted)
+	}
+	return nil
+}
+
+// release builds track release for uploaded appVersions, a draft unless staged rollout or status requested
+func (p *publish) release(versions []int64) (*androidpublisher.TrackRelease, error) {
+	notes, err := p.trackReleaseNotes(versions)
+	if err != nil {
+		return nil, err
+	}
+	r := &androidpublisher.TrackRelease{
+		Name:                p.releaseName,
+		Status:              StatusDraft,
+		VersionCodes:        versions,
+		ReleaseNotes:        notes,
+		CountryTargeting:    p.countryTargeting(),
+		InAppUpdatePriority: int64(p.updatePriority),
+	}
+	if p.rolloutFraction > 0 {
+		r.Status = StatusInProgress
+		r.UserFraction = p.rolloutFraction
+	}
+	if p.releaseStatus != "" {
+		r.Status = p.releaseStatus
+	}
+	return r, nil
+}
+
+// checkEditExpiry logs when edit expires and warns if uploads are unlikely to finish before that
+func (p *publish) checkEditExpiry(expiresAt time.Time) {
+	if expiresAt.IsZero() {
+		p.Debugf("edit expiry time unknown")
+		return
+	}
+	remaining := time.Until(expiresAt)
+	p.Debugf("edit expires at %s (in %s)", expiresAt.Format(time.RFC3339), remaining.Round(time.Second))
+
+	var total int64
+	for _, f := range p.files {
+		for _, path := range []string{f.filePath, f.mappingPath} {
+			if path == "" {
+				continue
+			}
+			size, err := p.fileSize(path)
+			if err != nil {
+				p.Debugf("skipping upload time estimate, failed reading '%s' size: %v", path, err)
+				return
+			}
+			total += size
+		}
+	}
+	estimate := time.Duration(total/expectedUploadRate) * time.Second
+	if estimate > remaining {
+		p.Warnf("uploading %d bytes may take around %s, but edit expires in %s", total, estimate, remaining.Round(time.Second))
+	}
+}
 
-	log.Println("All files uploaded successfully.")
+// commit commits edit, falling back to changesNotSentForReview if allowed and Play requires it
+func (p *publish) commit(ctx context.Context, es IEditsService, editId string) error {
+	return p.commitWith(func(changesNotSentForReview bool) error {
+		return es.commitEdit(ctx, p.packageName, editId, changesNotSentForReview)
+	})
+}
+
+// commitWith commits sending changes for review, retrying without sending them when allowed, or
+// without sending them right away with WithChangesNotSentForReview
+func (p *publish) commitWith(commit func(changesNotSentForReview bool) error) error {
+	if p.notSentForReview {
+		if err := commit(true); err != nil {
+			return p.managedCommitErr(err)
+		}
+		p.reportCommitted(false)
+		return nil
+	}
+	err := commit(false)
+	if err == nil {
+		p.reportCommitted(true)
+		return nil
+	}
+	if !isChangesNotSentForReviewErr(err) {
+		return p.managedCommitErr(err)
+	}
+	if !p.reviewFallback {
+		return fmt.Errorf("changes can not be sent for review automatically, allow committing without sending for review to publish them: %w", err)
+	}
+	p.fallbackf("changes can not be sent for review automatically, retrying commit with changes not sent for review")
+	if err := commit(true); err != nil {
+		return p.managedCommitErr(err)
+	}
+	p.reportCommitted(false)
 	return nil
 }
 
-func (p *publish) upload(us IUploadService, filePath, editId string, isApk bool) (version int64, err error) {
+func (p *publish) upload(ctx context.Context, edit *Edit, filePath string, isApk bool) (version int64, hashes Hashes, err error) {
 
-	p.Debugf("uploading %s", filePath)
+	p.fileDebugf(filePath, "uploading %s", filePath)
 
 	f, err := p.fs.Open(filePath)
 	if err != nil {
-		return -1, err
+		return -1, Hashes{}, err
 	}
 	defer f.Close()
 
-	hash, err := fileSha256(f)
+	// sha1 is not verified by Play, but matching sha256 guarantees it's of the same content
+	local, err := fileHashes(f)
 	if err != nil {
-		return -1, fmt.Errorf("failed calculating '%s' sha256 hash: %w", filePath, err)
+		return -1, Hashes{}, fmt.Errorf("failed calculating '%s' hashes: %w", filePath, err)
 	}
 
-	pReader := &ioprogress.Reader{
-		Reader:       f,
-		Size:         p.fileSize(filePath),
-		DrawFunc:     ioprogress.DrawTerminalf(log.Writer(), ioprogress.DrawTextFormatBytes),
-		DrawInterval: uploadProgressDrawInterval,
+	info, err := f.Stat()
+	if err != nil {
+		return -1, Hashes{}, err
 	}
 
-	uplF := us.uploadBundle
+	uplF := edit.UploadBundle
 	if isApk {
-		uplF = us.uploadApk
+		uplF = edit.UploadApk
 	}
 
-	v, sha256, err := uplF(pReader, p.packageName, editId)
-	if err != nil {
-		return -1, err
+	attempts := 1
+	if p.integrityRetry {
+		attempts = 2
 	}
-	p.Debugf("File successfully uploaded with appVersion: '%d'. Verifying file integrity on playstore", v)
-	if sha256 != hash {
-		return -1, fmt.Errorf("failed integrity verification with local file hash '%s' and remote '%s'", hash, sha256)
+	ie := &IntegrityError{Path: filePath, LocalSha256: local.Sha256, Size: info.Size()}
+	for ie.Attempts < attempts {
+		ie.Attempts++
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return -1, Hashes{}, err
+		}
+		counter := &countingReader{r: f}
+		pReader := p.progressReader(filePath, counter, info.Size())
+
+		v, sha256, err := uplF(ctx, pReader)
+		if err != nil {
+			return -1, Hashes{}, err
+		}
+		p.fileDebugf(filePath, "File successfully uploaded with appVersion: '%d'. Verifying file integrity on playstore", v)
+		if sha256 == local.Sha256 {
+			p.fileDebugf(filePath, "File integrity check passed wtih sha256 '%s'", sha256)
+			return v, local, nil
+		}
+		ie.RemoteSha256 = sha256
+		ie.BytesSent = counter.count()
+		if ie.Attempts < attempts {
+			p.fallbackf("'%s' sha256 on playstore '%s' doesn't match local '%s', uploading again", filePath, sha256, local.Sha256)
+		}
 	}
-	p.Debugf("File integrity check passed wtih sha256 '%s'", sha256)
-	return v, nil
+	return -1, Hashes{}, ie
 }
 
-func (p *publish) uploadMapping(us IUploadService, filePath, editId string, appVersionCode int64) error {
-
-	p.Debugf("Uploading mappgins '%s' for upload with appVersionCode '%d'", filePath, appVersionCode)
+// uploadMapping uploads mapping file for every app version code it belongs to
+func (p *publish) uploadMapping(ctx context.Context, us IUploadService, filePath, editId string, appVersionCodes []int64) error {
 
-	f, err := p.fs.Open(filePath)
+	f, err := p.openMapping(filePath)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
-
-	err = us.uploadProguardMapping(f, p.packageName, editId, appVersionCode)
-
+	if err := p.recordDigest(filePath, DeobfuscationFileProguard, f); err != nil {
+		return err
+	}
+	info, err := f.Stat()
 	if err != nil {
 		return err
 	}
-	p.Debugf("Mapping '%s' successfully uploaded.", filePath)
+
+	for _, v := range appVersionCodes {
+		p.fileDebugf(filePath, "Uploading mappgins '%s' for upload with appVersionCode '%d'", filePath, v)
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := us.uploadProguardMapping(ctx, p.progressReader(filePath, f, info.Size()), p.packageName, editId, v); err != nil {
+			return err
+		}
+	}
+	p.fileDebugf(filePath, "Mapping '%s' successfully uploaded.", filePath)
 	return nil
 }
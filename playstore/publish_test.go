@@ -2,12 +2,21 @@ package playstore
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
 	"reflect"
+	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/googleapi"
 )
 
 func TestPublish(t *testing.T) {
@@ -24,7 +33,7 @@ func TestPublish(t *testing.T) {
 		fs.Create(binFile)
 
 		// Act
-		_, err := Publish(fs, " ", TrackInternal, authFile, mockBins, false, false)
+		_, err := Publish(context.Background(), fs, " ", TrackInternal, authFile, mockBins, false, false)
 
 		// Assert
 		if err == nil {
@@ -44,7 +53,7 @@ func TestPublish(t *testing.T) {
 		fs.Create(binFile)
 
 		// Act
-		_, err := Publish(fs, appID, TrackInternal, authFile, mockBins, false, false)
+		_, err := Publish(context.Background(), fs, appID, TrackInternal, authFile, mockBins, false, false)
 
 		// Assert
 		if err == nil {
@@ -65,7 +74,7 @@ func TestPublish(t *testing.T) {
 		fs.Create(binFile)
 
 		// Act
-		_, err := Publish(fs, appID, TrackInternal, authFile, mockBins, false, false)
+		_, err := Publish(context.Background(), fs, appID, TrackInternal, authFile, mockBins, false, false)
 
 		// Assert
 		if err == nil {
@@ -84,7 +93,7 @@ func TestPublish(t *testing.T) {
 		fs.Create(binFile)
 
 		// Act
-		_, err := Publish(fs, appID, TrackInternal, authFile, mockBins, false, false)
+		_, err := Publish(context.Background(), fs, appID, TrackInternal, authFile, mockBins, false, false)
 
 		// Assert
 		if err == nil {
@@ -105,7 +114,7 @@ func TestPublish(t *testing.T) {
 		fs.Create(binFile)
 
 		// Act
-		_, err := Publish(fs, appID, TrackProduction, authFile, mockBins, false, false)
+		_, err := Publish(context.Background(), fs, appID, TrackProduction, authFile, mockBins, false, false)
 
 		// Assert
 		if err == nil {
@@ -113,6 +122,47 @@ func TestPublish(t *testing.T) {
 		}
 	})
 
+	t.Run("should allow production track when confirmed", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		appID := "com.sample.app0"
+		authFile := "auth.json"
+		binFile := "bin.aab"
+		mockBins := Binaries(
+			map[string]string{binFile: ""},
+		)
+		fs.Create(authFile)
+		fs.Create(binFile)
+
+		// Act
+		p, err := Publish(context.Background(), fs, appID, TrackProduction, authFile, mockBins, false, false, AllowProduction())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if p.track != TrackProduction {
+			t.Errorf("want '%s' track, got '%s'", TrackProduction, p.track)
+		}
+	})
+
+	t.Run("should stop validating when cancelled", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		fs.Create("bin.aab")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		_, err := Publish(ctx, fs, "com.sample.app", TrackInternal, "auth.json", []binary{Binary("bin.aab")}, false, false)
+
+		// Assert
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want context cancelled error, got %v", err)
+		}
+	})
+
 	t.Run("given all files are present and valid, should return publish", func(t *testing.T) {
 		// Arrange
 		fs := afero.NewMemMapFs()
@@ -137,7 +187,7 @@ func TestPublish(t *testing.T) {
 		}
 
 		// Act
-		actual, err := Publish(fs, appID, TrackInternal, authFile, mockBins, false, false)
+		actual, err := Publish(context.Background(), fs, appID, TrackInternal, authFile, mockBins, false, false)
 
 		// Assert
 		if err != nil {
@@ -149,20 +199,94 @@ func TestPublish(t *testing.T) {
 	})
 }
 
+func TestPublishProfile(t *testing.T) {
+
+	t.Run("wear profile should prefix track and publish apk", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "wear.apk", "")
+
+		// Act
+		p, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, WithProfile(ProfileWear))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if p.track != "wear:beta" {
+			t.Errorf("want 'wear:beta' track, got '%s'", p.track)
+		}
+		if !p.apk {
+			t.Error("want apk publish, got bundle")
+		}
+	})
+
+	t.Run("wear profile should accept already prefixed track", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "wear.apk", "")
+
+		// Act
+		p, err := Publish(context.Background(), fs, "com.test.app", "wear:internal", "auth.json", []binary{bin}, true, false, WithProfile(ProfileWear))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if p.track != "wear:internal" {
+			t.Errorf("want 'wear:internal' track, got '%s'", p.track)
+		}
+	})
+
+	t.Run("should fail binary exceeding profile size limit", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "wear.apk", "")
+		pr := ProfileWear
+		pr.MaxFileSize = 5
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, true, false, WithProfile(pr))
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should fail binary exceeding max size", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithMaxSize(5))
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
+
 func TestUploadFiles(t *testing.T) {
 	t.Run("Should call uploadBundle with expected binary file", func(t *testing.T) {
 		// Arrange
 		fs := afero.NewMemMapFs()
 		fs.Create("auth.json")
 		bin, actual, _ := createMockBinary(t, fs, "test.aab", "")
-		publish, err := Publish(fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
 		if err != nil {
 			t.Fatal(err)
 		}
 		gs := &mockGService{}
 
 		// Act
-		if err := publish.UploadFiles(gs); err != nil {
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
 			t.Fatal(err)
 		}
 
@@ -172,18 +296,68 @@ func TestUploadFiles(t *testing.T) {
 		}
 	})
 
+	t.Run("should delete validated edit instead of committing on dry run", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, DryRun())
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{}
+
+		// Act
+		_, err = publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.validateEditCount != 1 || gs.deleteEditCount != 1 || gs.commitEditCount != 0 {
+			t.Errorf("want edit validated and deleted without commit, got %d validate %d delete %d commit calls", gs.validateEditCount, gs.deleteEditCount, gs.commitEditCount)
+		}
+	})
+
+	t.Run("should return uploaded binaries with digests", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, actual, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{AppVersionCode: 5}
+		want, _ := fileHashes(bytes.NewReader(actual))
+
+		// Act
+		a, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(a) != 1 {
+			t.Fatalf("want 1 artifact, got %d", len(a))
+		}
+		if a[0].Path != "test.aab" || a[0].VersionCode != 5 || a[0].Sha256 != want.Sha256 || a[0].Sha1 != want.Sha1 {
+			t.Errorf("want 'test.aab' appVersionCode 5 with %+v, got %+v", want, a[0])
+		}
+	})
+
 	t.Run("Should call uploadBundle with expected binary file", func(t *testing.T) {
 		// Arrange
 		isApk := false
 		fs := afero.NewMemMapFs()
 		fs.Create("auth.json")
 		bin, actual, _ := createMockBinary(t, fs, "test.aab", "")
-		publish, _ := Publish(fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, isApk, false)
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, isApk, false)
 
 		gs := &mockGService{}
 
 		// Act
-		if err := publish.UploadFiles(gs); err != nil {
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
 			t.Fatal(err)
 		}
 
@@ -202,11 +376,11 @@ func TestUploadFiles(t *testing.T) {
 		fs := afero.NewMemMapFs()
 		fs.Create("auth.json")
 		bin, actual, _ := createMockBinary(t, fs, "test.apk", "")
-		publish, _ := Publish(fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, isApk, false)
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, isApk, false)
 		gs := &mockGService{}
 
 		// Act
-		if err := publish.UploadFiles(gs); err != nil {
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
 			t.Fatal(err)
 		}
 
@@ -225,12 +399,12 @@ func TestUploadFiles(t *testing.T) {
 		fs := afero.NewMemMapFs()
 		fs.Create("auth.json")
 		bin, _, _ := createMockBinary(t, fs, "test.apk", "")
-		publish, _ := Publish(fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, isApk, false)
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, isApk, false)
 		gs := &mockGService{}
 		gs.Sha256 = "randomValue"
 
 		// Act
-		err := publish.UploadFiles(gs)
+		_, err := publish.UploadFiles(context.Background(), gs)
 
 		//Assert
 		if err == nil {
@@ -238,17 +412,61 @@ func TestUploadFiles(t *testing.T) {
 		}
 	})
 
+	t.Run("should report both hashes and bytes sent on integrity failure", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithIntegrityRetry())
+		gs := &mockGService{Sha256: "randomValue"}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ie *IntegrityError
+		if !errors.As(err, &ie) {
+			t.Fatalf("want IntegrityError, got %v", err)
+		}
+		if ie.RemoteSha256 != "randomValue" || ie.LocalSha256 == "" || ie.BytesSent != 10 || ie.Attempts != 2 {
+			t.Errorf("want both hashes, 10 bytes sent and 2 attempts, got %+v", ie)
+		}
+		if gs.uploadBundleCallCount != 2 {
+			t.Errorf("want upload retried once, got %d uploads", gs.uploadBundleCallCount)
+		}
+	})
+
+	t.Run("should succeed when re-upload after integrity failure matches", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithIntegrityRetry())
+		gs := &mockCorruptOnceGService{}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.uploadBundleCallCount != 2 {
+			t.Errorf("want 2 uploads, got %d", gs.uploadBundleCallCount)
+		}
+	})
+
 	t.Run("Should call create and commit Edit", func(t *testing.T) {
 		// Arrange
 		isApk := true
 		fs := afero.NewMemMapFs()
 		fs.Create("auth.json")
 		bin, _, _ := createMockBinary(t, fs, "test.apk", "")
-		publish, _ := Publish(fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, isApk, false)
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, isApk, false)
 		gs := &mockGService{}
 
 		// Act
-		if err := publish.UploadFiles(gs); err != nil {
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
 			t.Fatal(err)
 		}
 
@@ -268,8 +486,360 @@ func TestUploadFiles(t *testing.T) {
 	})
 }
 
+func TestUploadMappings(t *testing.T) {
+	t.Run("Should upload shared mapping once per version code", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "arm.apk", 10)
+		createTestFile(t, fs, "x86.apk", 10)
+		mapping := createTestFile(t, fs, "mapping.txt", 20)
+		bins := []binary{BinaryWithMapping("arm.apk", "mapping.txt"), BinaryWithMapping("x86.apk", "mapping.txt")}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", bins, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockVersionedGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if !reflect.DeepEqual(gs.mappingVersionCodes, []int64{1, 2}) {
+			t.Errorf("want mapping uploaded for version codes [1 2], got %v", gs.mappingVersionCodes)
+		}
+		if !bytes.Equal(mapping, gs.mappingBytes) {
+			t.Errorf("want '%s' mapping content, got '%s'", mapping, gs.mappingBytes)
+		}
+	})
+
+	t.Run("Should upload SharedMapping for every binary without its own", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "arm.apk", 10)
+		createTestFile(t, fs, "x86.apk", 10)
+		createTestFile(t, fs, "x86.txt", 30)
+		createTestFile(t, fs, "mapping.txt", 20)
+		bins := []binary{Binary("arm.apk"), BinaryWithMapping("x86.apk", "x86.txt")}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", bins, true, false, SharedMapping("mapping.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockVersionedGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if !reflect.DeepEqual(gs.mappingVersionCodes, []int64{1, 2}) {
+			t.Errorf("want mappings uploaded for version codes [1 2], got %v", gs.mappingVersionCodes)
+		}
+		if publish.files[0].mappingPath != "mapping.txt" || publish.files[1].mappingPath != "x86.txt" {
+			t.Errorf("want [mapping.txt x86.txt] mappings, got [%s %s]", publish.files[0].mappingPath, publish.files[1].mappingPath)
+		}
+	})
+
+	t.Run("Should fail when SharedMapping does not exist", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "arm.apk", 10)
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("arm.apk")}, true, false, SharedMapping("mapping.txt"))
+
+		// Assert
+		if err == nil || err.Error() != "mappings file 'mapping.txt' does not exist" {
+			t.Errorf("want missing mapping error, got: %v", err)
+		}
+	})
+}
+
+func TestParallelUpload(t *testing.T) {
+
+	t.Run("should keep release in upload order when uploading in parallel", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bins := make([]binary, 0)
+		for _, name := range []string{"arm.apk", "arm64.apk", "x86.apk", "x86_64.apk"} {
+			createTestFile(t, fs, name, 10)
+			createTestFile(t, fs, name+".txt", 20)
+			bins = append(bins, BinaryWithMapping(name, name+".txt"))
+		}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", bins, true, false, WithConcurrency(Concurrency{Uploads: 4, Mappings: 2}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockVersionedGService{}
+
+		// Act
+		a, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(a) != 4 {
+			t.Fatalf("want 4 artifacts, got %d", len(a))
+		}
+		for i, b := range bins {
+			if a[i].Path != b.filePath || a[i].VersionCode != gs.releases[0].VersionCodes[i] {
+				t.Errorf("want '%s' at position %d of release, got '%s'", b.filePath, i, a[i].Path)
+			}
+		}
+		if len(gs.mappingVersionCodes) != 4 {
+			t.Errorf("want 4 mapping uploads, got %d", len(gs.mappingVersionCodes))
+		}
+	})
+
+	t.Run("should delete edit once a parallel upload fails", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bins := make([]binary, 0)
+		for _, name := range []string{"arm.apk", "x86.apk"} {
+			createTestFile(t, fs, name, 10)
+			bins = append(bins, Binary(name))
+		}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", bins, true, false, WithMaxParallelUploads(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := errors.New("upload failed")
+		gs := &mockGService{Error: want}
+
+		// Act
+		_, err = publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if !errors.Is(err, want) {
+			t.Errorf("want '%v', got '%v'", want, err)
+		}
+		if gs.deleteEditCount != 1 || gs.commitEditCount != 0 || len(gs.releases) != 0 {
+			t.Errorf("want edit deleted without release, got %d deletes %d commits", gs.deleteEditCount, gs.commitEditCount)
+		}
+	})
+}
+
+func TestRollout(t *testing.T) {
+
+	t.Run("Should create draft release by default", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false)
+		gs := &mockGService{AppVersionCode: 5}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if len(gs.releases) != 1 || gs.releaseTrack != TrackBeta {
+			t.Fatalf("want 1 release on '%s' track, got %d on '%s'", TrackBeta, len(gs.releases), gs.releaseTrack)
+		}
+		if r := gs.releases[0]; r.Status != StatusDraft || !reflect.DeepEqual([]int64(r.VersionCodes), []int64{5}) {
+			t.Errorf("want draft release with appVersion 5, got %+v", r)
+		}
+	})
+
+	t.Run("Should create staged rollout release with user fraction", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackProduction, "auth.json", []binary{bin}, false, false, AllowProduction(), WithRolloutFraction(0.05))
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if r := gs.releases[0]; r.Status != StatusInProgress || r.UserFraction != 0.05 {
+			t.Errorf("want inProgress release with 0.05 user fraction, got %+v", r)
+		}
+	})
+
+	t.Run("Should reject rollout fraction out of range", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+
+		for _, f := range []float64{-0.1, 1, 1.5} {
+			// Act
+			_, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, WithRolloutFraction(f))
+
+			// Assert
+			if err == nil {
+				t.Errorf("want error for %v fraction, got nil", f)
+			}
+		}
+	})
+
+	t.Run("Should create completed release with requested status", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, WithReleaseStatus(StatusCompleted))
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if r := gs.releases[0]; r.Status != StatusCompleted || r.UserFraction != 0 {
+			t.Errorf("want completed release without user fraction, got %+v", r)
+		}
+	})
+
+	t.Run("Should create release with in-app update priority", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, WithUpdatePriority(5))
+		gs := &mockGService{}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), gs); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if r := gs.releases[0]; r.InAppUpdatePriority != 5 {
+			t.Errorf("want release with update priority 5, got %+v", r)
+		}
+	})
+
+	t.Run("Should reject in-app update priority out of range", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+
+		for _, priority := range []int{-1, 6} {
+			// Act
+			_, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, WithUpdatePriority(priority))
+
+			// Assert
+			if err == nil {
+				t.Errorf("want error for %d priority, got nil", priority)
+			}
+		}
+	})
+
+	t.Run("Should reject release status not matching rollout fraction", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		cases := []struct {
+			status   string
+			fraction float64
+		}{
+			{StatusCompleted, 0.1},
+			{StatusDraft, 0.1},
+			{StatusInProgress, 0},
+			{"live", 0},
+		}
+
+		for _, c := range cases {
+			// Act
+			_, err := Publish(context.Background(), fs, "com.test.app", TrackBeta, "auth.json", []binary{bin}, false, false, WithReleaseStatus(c.status), WithRolloutFraction(c.fraction))
+
+			// Assert
+			if err == nil {
+				t.Errorf("want error for '%s' release with %v fraction, got nil", c.status, c.fraction)
+			}
+		}
+	})
+}
+
+func TestCommitReviewFallback(t *testing.T) {
+	notSentErr := &googleapi.Error{Code: 400, Message: "Changes cannot be sent for review automatically. Please set the query parameter changesNotSentForReview to true."}
+
+	t.Run("Should retry commit without review when allowed", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithNotSentForReviewFallback())
+		gs := &mockGService{commitErrors: []error{notSentErr}}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(gs.changesNotSentForReview, []bool{false, true}) {
+			t.Errorf("want commits [false true], got %v", gs.changesNotSentForReview)
+		}
+	})
+
+	t.Run("Should fail without retry when fallback not allowed", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		gs := &mockGService{commitErrors: []error{notSentErr}}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.commitEditCount != 1 {
+			t.Errorf("want 1 commitEdit call, got %d", gs.commitEditCount)
+		}
+		if gs.deleteEditCount != 1 {
+			t.Errorf("want 1 deleteEdit call, got %d", gs.deleteEditCount)
+		}
+	})
+
+	t.Run("Should commit without review right away when requested", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithChangesNotSentForReview())
+		gs := &mockGService{}
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(gs.changesNotSentForReview, []bool{true}) {
+			t.Errorf("want commits [true], got %v", gs.changesNotSentForReview)
+		}
+	})
+}
+
 // Helper mock service to seperate us from google libraries for testing
 type mockGService struct {
+	// guards upload recording for concurrent uploads
+	mu                    sync.Mutex
 	AppVersionCode        int64
 	Sha256                string
 	Error                 error
@@ -282,59 +852,263 @@ type mockGService struct {
 	commitEditCount       int64
 	validateEditCount     int64
 	deleteEditCount       int64
+	mappingBytes          []byte
+	mappingVersionCodes   []int64
+	symbolsVersionCodes   []int64
+	deleteError           error
+	deleteCtxErr          error
+	// content of every binary upload in order received
+	uploads [][]byte
+	// errors returned by consecutive commitEdit and validateEdit calls
+	commitErrors            []error
+	validateErrors          []error
+	changesNotSentForReview []bool
+	// bundles and apks already on playstore
+	hashes map[int64]Hashes
+	// tracks on playstore by name and tracks updated within edit
+	tracks        map[string]*androidpublisher.Track
+	updatedTracks []*androidpublisher.Track
+	// releases created and track they were created on
+	releaseTrack string
+	releases     []*androidpublisher.TrackRelease
+	// store listings on playstore and changes made to them
+	listings        []Listing
+	updatedListings []Listing
+	deletedListings []string
+	deletedImages   []string
+	uploadedImages  []string
+	// sha256 of images on playstore by <locale>/<imageType>
+	images map[string][]string
+	// expansion files uploaded as <appVersionCode>/<fileType>
+	expansionFiles []string
+	// user reviews on playstore and replies sent by review ID
+	reviews []Review
+	replies map[string]string
+	// app details on playstore, empty if not set
+	details        *AppDetails
+	updatedDetails *AppDetails
+	// error reported for edits opened earlier
+	getEditError error
+	// createEdit errors by package name
+	createEditErrors map[string]error
 }
 
-func (gs *mockGService) uploadBundle(r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
-	gs.setFuncInputs(r, packageName, editId)
+func (gs *mockGService) uploadBundle(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
+	sha := gs.setFuncInputs(r, packageName, editId)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
 	gs.uploadBundleCallCount += 1
-	return gs.AppVersionCode, gs.Sha256, gs.Error
+	return gs.AppVersionCode, sha, gs.Error
 }
 
-func (gs *mockGService) uploadApk(r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
-	gs.setFuncInputs(r, packageName, editId)
+func (gs *mockGService) uploadApk(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
+	sha := gs.setFuncInputs(r, packageName, editId)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
 	gs.uploadApkCallCount += 1
-	return gs.AppVersionCode, gs.Sha256, gs.Error
+	return gs.AppVersionCode, sha, gs.Error
 }
 
-func (gs *mockGService) uploadProguardMapping(r io.Reader, packageName, editId string, appVersionCode int64) error {
-	gs.setFuncInputs(r, packageName, editId)
-	gs.AppVersionCode = appVersionCode
+func (gs *mockGService) uploadExpansionFile(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string) error {
+	io.ReadAll(r)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.expansionFiles = append(gs.expansionFiles, fmt.Sprintf("%d/%s", appVersionCode, fileType))
 	return gs.Error
 }
 
-func (gs *mockGService) setFuncInputs(r io.Reader, packageName, editId string) {
+func (gs *mockGService) uploadProguardMapping(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error {
 	b, _ := io.ReadAll(r)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.mappingBytes = b
+	gs.mappingVersionCodes = append(gs.mappingVersionCodes, appVersionCode)
+	return gs.Error
+}
+
+func (gs *mockGService) uploadNativeSymbols(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error {
+	io.ReadAll(r)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.symbolsVersionCodes = append(gs.symbolsVersionCodes, appVersionCode)
+	return gs.Error
+}
+
+// setFuncInputs records upload and returns Sha256 if set, otherwise hash of uploaded content
+func (gs *mockGService) setFuncInputs(r io.Reader, packageName, editId string) string {
+	b, _ := io.ReadAll(r)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
 	gs.bytes = b
+	gs.uploads = append(gs.uploads, b)
 	gs.packageName = packageName
 	gs.editId = editId
-	if gs.Sha256 == "" {
-		rdr := r.(io.ReadSeeker)
-		rdr.Seek(0, io.SeekStart)
-		s, _ := fileSha256(rdr)
-		gs.Sha256 = s
+	if gs.Sha256 != "" {
+		return gs.Sha256
 	}
+	s, _ := fileSha256(bytes.NewReader(b))
+	return s
+}
+
+// mockVersionedGService hands out incrementing version codes and hashes every upload it gets
+type mockVersionedGService struct {
+	mockGService
+}
+
+func (gs *mockVersionedGService) uploadApk(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
+	b, _ := io.ReadAll(r)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.uploadApkCallCount += 1
+	s, _ := fileSha256(bytes.NewReader(b))
+	return gs.uploadApkCallCount, s, nil
+}
+
+// mockCorruptOnceGService reports wrong hash for the first bundle upload only
+type mockCorruptOnceGService struct {
+	mockGService
 }
 
-func (gs *mockGService) createEdit(packageName string) (string, error) {
+func (gs *mockCorruptOnceGService) uploadBundle(ctx context.Context, r io.Reader, packageName, editId string) (appVersionCode int64, sha256 string, err error) {
+	v, sha, err := gs.mockGService.uploadBundle(ctx, r, packageName, editId)
+	if gs.uploadBundleCallCount == 1 {
+		return v, "corrupted", err
+	}
+	return v, sha, err
+}
+
+func (gs *mockGService) createEdit(ctx context.Context, packageName string) (string, time.Time, error) {
 	gs.createEditCount += 1
-	return "1", nil
+	if err, ok := gs.createEditErrors[packageName]; ok {
+		return "", time.Time{}, err
+	}
+	return "1", time.Now().Add(time.Hour), nil
 }
 
-func (gs *mockGService) validateEdit(packageName, editId string) error {
+func (gs *mockGService) validateEdit(ctx context.Context, packageName, editId string) error {
 	gs.validateEditCount += 1
+	if len(gs.validateErrors) != 0 {
+		err := gs.validateErrors[0]
+		gs.validateErrors = gs.validateErrors[1:]
+		return err
+	}
 	return nil
 }
 
-func (gs *mockGService) deleteEdit(packageName, editId string) error {
+func (gs *mockGService) getEdit(ctx context.Context, packageName, editId string) (time.Time, error) {
+	return time.Time{}, gs.getEditError
+}
+
+func (gs *mockGService) deleteEdit(ctx context.Context, packageName, editId string) error {
 	gs.deleteEditCount += 1
-	return nil
+	gs.deleteCtxErr = ctx.Err()
+	return gs.deleteError
 }
-func (gs *mockGService) commitEdit(packageName, editId string) error {
+func (gs *mockGService) commitEdit(ctx context.Context, packageName, editId string, changesNotSentForReview bool) error {
 	gs.commitEditCount += 1
+	gs.changesNotSentForReview = append(gs.changesNotSentForReview, changesNotSentForReview)
+	if len(gs.commitErrors) != 0 {
+		err := gs.commitErrors[0]
+		gs.commitErrors = gs.commitErrors[1:]
+		return err
+	}
 	return nil
 }
 
-func (gs *mockGService) createDraft(packageName, editId, trackName string, appVersionCodes []int64) error {
+func (gs *mockGService) versionHashes(ctx context.Context, packageName, editId string) (map[int64]Hashes, error) {
+	return gs.hashes, gs.Error
+}
+
+func (gs *mockGService) listTracks(ctx context.Context, packageName, editId string) ([]*androidpublisher.Track, error) {
+	names := make([]string, 0, len(gs.tracks))
+	for n := range gs.tracks {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	tracks := make([]*androidpublisher.Track, 0, len(names))
+	for _, n := range names {
+		tracks = append(tracks, gs.tracks[n])
+	}
+	return tracks, gs.Error
+}
+
+func (gs *mockGService) getTrack(ctx context.Context, packageName, editId, trackName string) (*androidpublisher.Track, error) {
+	if gs.tracks == nil || gs.tracks[trackName] == nil {
+		return &androidpublisher.Track{Track: trackName}, gs.Error
+	}
+	return gs.tracks[trackName], gs.Error
+}
+
+func (gs *mockGService) updateTrack(ctx context.Context, packageName, editId string, track *androidpublisher.Track) error {
+	gs.updatedTracks = append(gs.updatedTracks, track)
+	return gs.Error
+}
+
+func (gs *mockGService) listListings(ctx context.Context, packageName, editId string) ([]Listing, error) {
+	return gs.listings, gs.Error
+}
+
+func (gs *mockGService) updateListing(ctx context.Context, packageName, editId string, listing Listing) error {
+	gs.updatedListings = append(gs.updatedListings, listing)
+	return gs.Error
+}
+
+func (gs *mockGService) deleteListing(ctx context.Context, packageName, editId, locale string) error {
+	gs.deletedListings = append(gs.deletedListings, locale)
+	return gs.Error
+}
+
+func (gs *mockGService) uploadImage(ctx context.Context, r io.Reader, packageName, editId, locale, imageType string) error {
+	io.ReadAll(r)
+	gs.uploadedImages = append(gs.uploadedImages, locale+"/"+imageType)
+	return gs.Error
+}
+
+func (gs *mockGService) listImages(ctx context.Context, packageName, editId, locale, imageType string) ([]string, error) {
+	return gs.images[locale+"/"+imageType], gs.Error
+}
+
+func (gs *mockGService) deleteAllImages(ctx context.Context, packageName, editId, locale, imageType string) (int, error) {
+	gs.deletedImages = append(gs.deletedImages, locale+"/"+imageType)
+	return 0, gs.Error
+}
+
+func (gs *mockGService) listReviews(ctx context.Context, packageName string) ([]Review, error) {
+	return gs.reviews, gs.Error
+}
+
+func (gs *mockGService) getReview(ctx context.Context, packageName, reviewId string) (*Review, error) {
+	for _, r := range gs.reviews {
+		if r.ID == reviewId {
+			return &r, gs.Error
+		}
+	}
+	return nil, &googleapi.Error{Code: http.StatusNotFound, Message: "review not found"}
+}
+
+func (gs *mockGService) replyReview(ctx context.Context, packageName, reviewId, text string) error {
+	if gs.replies == nil {
+		gs.replies = make(map[string]string)
+	}
+	gs.replies[reviewId] = text
+	return gs.Error
+}
+
+func (gs *mockGService) getDetails(ctx context.Context, packageName, editId string) (*AppDetails, error) {
+	if gs.details == nil {
+		return &AppDetails{}, gs.Error
+	}
+	return gs.details, gs.Error
+}
+
+func (gs *mockGService) updateDetails(ctx context.Context, packageName, editId string, details AppDetails) error {
+	gs.updatedDetails = &details
+	return gs.Error
+}
+
+func (gs *mockGService) createRelease(ctx context.Context, packageName, editId, trackName string, release *androidpublisher.TrackRelease) error {
+	gs.releaseTrack = trackName
+	gs.releases = append(gs.releases, release)
 	return nil
 }
 
@@ -0,0 +1,103 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// quotaBackoff least delay before retrying call refused for exceeded quota, Play quota is counted per minute
+const quotaBackoff = time.Minute
+
+// error reasons Google APIs refuse calls over quota with, daily limit isn't retried as it lasts for hours
+var quotaReasons = map[string]bool{"rateLimitExceeded": true, "userRateLimitExceeded": true, "quotaExceeded": true}
+
+// WithRateLimit spreads Google API requests, upload chunks included, to at most qps per second. Services
+// sharing client, e.g. ones of ServiceCache with the same credentials, share the limit too, so batch
+// publishing many apps stays within Play quota. 0 for no limit.
+func WithRateLimit(qps float64) ServiceOption {
+	return func(c *serviceConfig) {
+		c.qps = qps
+	}
+}
+
+// isQuotaErr checks if Google API refused call for exceeding quota
+func isQuotaErr(err error) bool {
+	var ge *googleapi.Error
+	if !errors.As(err, &ge) {
+		return false
+	}
+	if ge.Code != http.StatusTooManyRequests && ge.Code != http.StatusForbidden {
+		return false
+	}
+	for _, e := range ge.Errors {
+		if quotaReasons[e.Reason] || e.Reason == "dailyLimitExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryableQuotaErr checks if call refused for exceeding quota could succeed once quota refills
+func isRetryableQuotaErr(err error) bool {
+	var ge *googleapi.Error
+	if !isQuotaErr(err) || !errors.As(err, &ge) {
+		return false
+	}
+	for _, e := range ge.Errors {
+		if quotaReasons[e.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimiter hands out evenly spaced request slots
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	// waits for slot and tells time, replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+}
+
+func newRateLimiter(qps float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / qps), sleep: sleepContext, now: time.Now}
+}
+
+// wait blocks until the next free slot or context is done
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	rl.mu.Lock()
+	now := rl.now()
+	slot := rl.next
+	if slot.Before(now) {
+		slot = now
+	}
+	rl.next = slot.Add(rl.interval)
+	rl.mu.Unlock()
+	if d := slot.Sub(now); d > 0 {
+		return rl.sleep(ctx, d)
+	}
+	return nil
+}
+
+// rateLimitedTransport makes requests of base transport once limiter has a slot for them
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,82 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+
+	newTestLimiter := func(qps float64) (*rateLimiter, *[]time.Duration) {
+		delays := make([]time.Duration, 0)
+		rl := newRateLimiter(qps)
+		clock := time.Now()
+		rl.now = func() time.Time { return clock }
+		rl.sleep = func(ctx context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}
+		return rl, &delays
+	}
+
+	t.Run("should space requests evenly", func(t *testing.T) {
+		// Arrange
+		rl, delays := newTestLimiter(4)
+
+		// Act
+		for i := 0; i < 3; i++ {
+			rl.wait(context.Background())
+		}
+
+		// Assert
+		if len(*delays) != 2 || (*delays)[0] != 250*time.Millisecond || (*delays)[1] != 500*time.Millisecond {
+			t.Errorf("want [250ms 500ms] waits, got %v", *delays)
+		}
+	})
+
+	t.Run("should not wait once slot has passed", func(t *testing.T) {
+		// Arrange
+		rl, delays := newTestLimiter(1)
+		rl.wait(context.Background())
+		clock := rl.now().Add(2 * time.Second)
+		rl.now = func() time.Time { return clock }
+
+		// Act
+		rl.wait(context.Background())
+
+		// Assert
+		if len(*delays) != 0 {
+			t.Errorf("want no wait, got %v", *delays)
+		}
+	})
+
+	t.Run("should not make request once context is done", func(t *testing.T) {
+		// Arrange
+		called := false
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer srv.Close()
+		rl := newRateLimiter(0.001)
+		rl.wait(context.Background())
+		client := &http.Client{Transport: &rateLimitedTransport{base: http.DefaultTransport, limiter: rl}}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+		// Act
+		_, err := client.Do(req)
+
+		// Assert
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want context canceled, got: %v", err)
+		}
+		if called {
+			t.Error("want no request made")
+		}
+	})
+}
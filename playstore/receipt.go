@@ -0,0 +1,41 @@
+package playstore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/afero"
+)
+
+// Receipt uploaded binaries and their digests for other tools to consume, with draft release details
+// when one was left to complete in Play Console
+type Receipt struct {
+	RunID       string         `json:"runId"`
+	EditID      string         `json:"editId,omitempty"`
+	PackageName string         `json:"packageName"`
+	Track       string         `json:"track"`
+	Artifacts   []UploadResult `json:"artifacts"`
+	Draft       *DraftRelease  `json:"draft,omitempty"`
+	// non fatal events of the run, e.g. warnings and retries
+	SoftFailures []SoftFailure `json:"softFailures,omitempty"`
+	// optional features skipped as their Google API was unavailable
+	SkippedFeatures []string `json:"skippedFeatures,omitempty"`
+}
+
+// Receipt records binaries returned by UploadFiles with the run they were uploaded by
+func (p *publish) Receipt(results []UploadResult) *Receipt {
+	return &Receipt{RunID: p.runID, EditID: p.editID, PackageName: p.packageName, Track: p.track, Artifacts: results, Draft: p.Draft(), SoftFailures: p.SoftFailures(),
+		SkippedFeatures: p.SkippedFeatures()}
+}
+
+// WriteReceipt writes receipt as JSON file
+func WriteReceipt(fs afero.Fs, path string, receipt *Receipt) error {
+	b, err := json.MarshalIndent(receipt, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(fs, path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed writing receipt '%s': %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package playstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestReceipt(t *testing.T) {
+
+	t.Run("should write receipt of uploaded binaries", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		out := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createMockBinary(t, fs, "test.aab", "")
+		gs := &mockGService{AppVersionCode: 42}
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false, WithRunID("run-1"))
+		results, _ := publish.UploadFiles(context.Background(), gs)
+
+		// Act
+		err := WriteReceipt(out, "receipt.json", publish.Receipt(results))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		b, _ := afero.ReadFile(out, "receipt.json")
+		receipt := &Receipt{}
+		if err := json.Unmarshal(b, receipt); err != nil {
+			t.Fatalf("want receipt JSON, got: %s", b)
+		}
+		if receipt.RunID != "run-1" || receipt.Track != TrackInternal || len(receipt.Artifacts) != 1 || receipt.Artifacts[0].VersionCode != 42 {
+			t.Errorf("want run-1 appVersion 42 on internal, got %+v", receipt)
+		}
+		if receipt.Draft == nil || len(receipt.Draft.VersionCodes) != 1 {
+			t.Errorf("want draft release of appVersion 42, got %+v", receipt.Draft)
+		}
+	})
+}
@@ -0,0 +1,314 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// resumable upload chunk size when not configured, googleapi default
+	resumableChunkSize = 16 << 20
+	// chunk sizes have to be multiple of it, except for the last chunk
+	resumableChunkGranularity = 256 << 10
+	// Play answers with it while resumable upload is not complete
+	statusResumeIncomplete = 308
+	// times a chunk failing for whole chunk retry deadline is resumed from offset Play acknowledged
+	chunkResumes = 3
+	// first delay before failed chunk is resent, doubled with every failure
+	chunkRetryDelay = time.Second
+)
+
+// errResumableUnsupported service was not created with an authorized client, e.g. wrapped for tests
+var errResumableUnsupported = errors.New("resumable uploads need service created with credentials")
+
+// resumableResult binary Play created once resumable upload completed
+type resumableResult struct {
+	VersionCode int64
+	Sha256      string
+}
+
+/**
+ * Google API wrapper for resumable binary uploads, with session URI kept by the caller so
+ * uploads can be continued by another process
+ */
+type IResumableUploadService interface {
+	startResumable(ctx context.Context, packageName, editId string, isApk bool, size int64) (sessionURI string, err error)
+	// resumableOffset returns bytes Play received so far, or the result if it received everything
+	resumableOffset(ctx context.Context, sessionURI string, size int64) (offset int64, res *resumableResult, err error)
+	// resumeUpload sends r from offset in chunks, calling acked with every offset Play confirmed
+	resumeUpload(ctx context.Context, sessionURI string, r io.ReadSeeker, offset, size int64, acked func(offset int64)) (*resumableResult, error)
+}
+
+type resumableService struct {
+	client   *http.Client
+	basePath string
+	cfg      *serviceConfig
+}
+
+// startResumable opens resumable upload session for a bundle or apk of given size, negative if unknown
+func (rs *resumableService) startResumable(ctx context.Context, packageName, editId string, isApk bool, size int64) (string, error) {
+	if rs.client == nil {
+		return "", errResumableUnsupported
+	}
+	kind := "bundles"
+	if isApk {
+		kind = "apks"
+	}
+	u := strings.TrimSuffix(rs.basePath, "/") + fmt.Sprintf("/upload/androidpublisher/v3/applications/%s/edits/%s/%s?uploadType=resumable",
+		url.PathEscape(packageName), url.PathEscape(editId), kind)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Upload-Content-Type", mediaHeader)
+	if size >= 0 {
+		req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	}
+	res, err := rs.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer googleapi.CloseBody(res)
+	if err := googleapi.CheckResponse(res); err != nil {
+		return "", apiError("start upload", err)
+	}
+	loc := res.Header.Get("Location")
+	if loc == "" {
+		return "", fmt.Errorf("resumable upload session started without session URI")
+	}
+	return loc, nil
+}
+
+// resumableOffset asks Play how much of the upload it has
+func (rs *resumableService) resumableOffset(ctx context.Context, sessionURI string, size int64) (int64, *resumableResult, error) {
+	if rs.client == nil {
+		return 0, nil, errResumableUnsupported
+	}
+	return rs.put(ctx, sessionURI, http.NoBody, 0, fmt.Sprintf("bytes */%d", size))
+}
+
+// resumeUpload sends the rest of the file, chunk by chunk
+func (rs *resumableService) resumeUpload(ctx context.Context, sessionURI string, r io.ReadSeeker, offset, size int64, acked func(offset int64)) (*resumableResult, error) {
+	if rs.client == nil {
+		return nil, errResumableUnsupported
+	}
+	cfg := rs.config()
+	if cfg.uploadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.uploadTimeout)
+		defer cancel()
+	}
+	chunk := rs.chunkSize()
+
+	for {
+		// file is sent in one go with chunking off
+		n := size - offset
+		if cfg.chunkSize != 0 && n > chunk {
+			n = chunk
+		}
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		// streamed from file, chunks of unchunked uploads may be as big as the binary
+		next, res, err := rs.put(ctx, sessionURI, io.LimitReader(r, n), n, fmt.Sprintf("bytes %d-%d/%d", offset, offset+n-1, size))
+		if err != nil {
+			return nil, err
+		}
+		if res != nil {
+			acked(size)
+			return res, nil
+		}
+		if next <= offset {
+			return nil, fmt.Errorf("upload made no progress at %d of %d bytes", offset, size)
+		}
+		offset = next
+		acked(offset)
+	}
+}
+
+// uploadChunked uploads r over new resumable session, holding a single chunk of it in memory. Failed chunk
+// is resent until chunk retry deadline expires, then Play is asked how much of it arrived and upload goes on
+// from there, instead of starting over as googleapi uploads do.
+func (rs *resumableService) uploadChunked(ctx context.Context, r io.Reader, packageName, editId string, isApk bool) (*resumableResult, error) {
+	uri, err := rs.startResumable(ctx, packageName, editId, isApk, -1)
+	if err != nil {
+		return nil, err
+	}
+	chunk := make([]byte, rs.chunkSize())
+	var offset int64
+	for {
+		// size is only known once reader runs out, so it's sent with the last chunk
+		n, err := io.ReadFull(r, chunk)
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return nil, err
+		}
+		res, err := rs.sendChunk(ctx, uri, chunk[:n], offset, last)
+		if err != nil {
+			return nil, err
+		}
+		if res != nil {
+			return res, nil
+		}
+		if last {
+			return nil, fmt.Errorf("upload incomplete after all %d bytes were sent", offset+int64(n))
+		}
+		offset += int64(n)
+	}
+}
+
+// sendChunk sends chunk at offset until Play acknowledged all of it, returning the result once last chunk completes upload
+func (rs *resumableService) sendChunk(ctx context.Context, sessionURI string, chunk []byte, offset int64, last bool) (*resumableResult, error) {
+	total := "*"
+	if last {
+		total = strconv.FormatInt(offset+int64(len(chunk)), 10)
+	}
+	end := offset + int64(len(chunk))
+	sent, resumes, delay := offset, 0, chunkRetryDelay
+	var failingSince time.Time
+	for {
+		contentRange := fmt.Sprintf("bytes %d-%d/%s", sent, end-1, total)
+		if sent == end {
+			contentRange = "bytes */" + total
+		}
+		next, res, err := rs.put(ctx, sessionURI, bytes.NewReader(chunk[sent-offset:]), end-sent, contentRange)
+		if err == nil {
+			if res != nil {
+				return res, nil
+			}
+			if next >= end && !last {
+				return nil, nil
+			}
+			if next > sent {
+				sent, failingSince, delay = next, time.Time{}, chunkRetryDelay
+				continue
+			}
+			err = fmt.Errorf("upload made no progress at %d bytes", sent)
+		}
+		if ctx.Err() != nil || !isChunkRetryable(err) {
+			return nil, err
+		}
+		if failingSince.IsZero() {
+			failingSince = time.Now()
+		}
+		if time.Since(failingSince) < rs.config().chunkRetryDeadline {
+			if serr := sleepContext(ctx, delay); serr != nil {
+				return nil, err
+			}
+			delay *= 2
+			continue
+		}
+
+		// chunk retry deadline expired, Play may still have gotten part of chunk
+		if resumes == chunkResumes {
+			return nil, fmt.Errorf("chunk at %d bytes kept failing after %d resumes: %w", sent, resumes, err)
+		}
+		acked, res, qerr := rs.put(ctx, sessionURI, http.NoBody, 0, "bytes */"+total)
+		if qerr != nil {
+			return nil, fmt.Errorf("chunk at %d bytes kept failing and upload offset can't be queried: %v: %w", sent, qerr, err)
+		}
+		if res != nil {
+			return res, nil
+		}
+		if acked < offset || acked > end {
+			return nil, fmt.Errorf("offset %d acknowledged by Play is outside of chunk %d-%d: %w", acked, offset, end-1, err)
+		}
+		resumes++
+		rs.log().Warnf("upload chunk failed for %s, resuming at acknowledged %d bytes: %v", rs.config().chunkRetryDeadline, acked, err)
+		sent, failingSince, delay = acked, time.Time{}, chunkRetryDelay
+		if sent == end && !last {
+			return nil, nil
+		}
+	}
+}
+
+// isChunkRetryable reports if chunk failed with an error worth sending it again for, network errors included
+func isChunkRetryable(err error) bool {
+	var ge *googleapi.Error
+	return !errors.As(err, &ge) || isTransientErr(err)
+}
+
+// chunkSize returns configured chunk size rounded up to chunk granularity, googleapi default if not set
+func (rs *resumableService) chunkSize() int64 {
+	if size := rs.config().chunkSize; size > 0 {
+		return (int64(size) + resumableChunkGranularity - 1) / resumableChunkGranularity * resumableChunkGranularity
+	}
+	return resumableChunkSize
+}
+
+// config returns service configuration, defaults if none set
+func (rs *resumableService) config() *serviceConfig {
+	if rs.cfg == nil {
+		return &serviceConfig{chunkRetryDeadline: chunkRetryDeadline, chunkSize: -1}
+	}
+	return rs.cfg
+}
+
+func (rs *resumableService) log() Logger {
+	if rs.cfg == nil || rs.cfg.logger == nil {
+		return defaultLogger
+	}
+	return rs.cfg.logger
+}
+
+// put sends content range of upload of given length and returns offset Play confirmed, or result once upload is complete
+func (rs *resumableService) put(ctx context.Context, sessionURI string, body io.Reader, length int64, contentRange string) (int64, *resumableResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURI, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Range", contentRange)
+	res, err := rs.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer googleapi.CloseBody(res)
+
+	if res.StatusCode == statusResumeIncomplete {
+		return receivedOffset(res.Header.Get("Range")), nil, nil
+	}
+	if err := googleapi.CheckResponse(res); err != nil {
+		return 0, nil, apiError("resume upload", err)
+	}
+	var binary struct {
+		VersionCode int64  `json:"versionCode"`
+		Sha256      string `json:"sha256"`
+		Binary      *struct {
+			Sha256 string `json:"sha256"`
+		} `json:"binary"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&binary); err != nil {
+		return 0, nil, fmt.Errorf("failed reading uploaded binary details: %w", err)
+	}
+	result := &resumableResult{VersionCode: binary.VersionCode, Sha256: binary.Sha256}
+	// apks report digest under binary
+	if binary.Binary != nil {
+		result.Sha256 = binary.Binary.Sha256
+	}
+	return 0, result, nil
+}
+
+// receivedOffset parses 'bytes=0-N' Range header into next offset to send, 0 if nothing was received
+func receivedOffset(r string) int64 {
+	_, last, ok := strings.Cut(strings.TrimPrefix(r, "bytes="), "-")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n + 1
+}
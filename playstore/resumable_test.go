@@ -0,0 +1,209 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestResumableService(t *testing.T) {
+
+	// Play resumable upload endpoint keeping received bytes of a single session
+	newServer := func(t *testing.T) (*httptest.Server, *bytes.Buffer) {
+		t.Helper()
+		received := &bytes.Buffer{}
+		var srv *httptest.Server
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost:
+				if r.URL.Query().Get("uploadType") != "resumable" || !strings.HasSuffix(r.URL.Path, "/edits/1/bundles") {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Location", srv.URL+"/session")
+			case r.Method == http.MethodPut:
+				b, _ := io.ReadAll(r.Body)
+				received.Write(b)
+				total, _ := strconv.Atoi(r.Header.Get("Content-Range")[strings.LastIndex(r.Header.Get("Content-Range"), "/")+1:])
+				if received.Len() < total {
+					if received.Len() > 0 {
+						w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", received.Len()-1))
+					}
+					w.WriteHeader(statusResumeIncomplete)
+					return
+				}
+				fmt.Fprintf(w, `{"versionCode": 7, "sha256": "abc"}`)
+			}
+		}))
+		t.Cleanup(srv.Close)
+		return srv, received
+	}
+
+	t.Run("should upload in chunks over resumable session", func(t *testing.T) {
+		// Arrange
+		srv, received := newServer(t)
+		cfg := &serviceConfig{chunkSize: resumableChunkGranularity}
+		rs := &resumableService{client: srv.Client(), basePath: srv.URL + "/", cfg: cfg}
+		content := bytes.Repeat([]byte("a"), resumableChunkGranularity*2+100)
+		acked := make([]int64, 0)
+
+		// Act
+		uri, err := rs.startResumable(context.Background(), "com.test.app", "1", false, int64(len(content)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := rs.resumeUpload(context.Background(), uri, bytes.NewReader(content), 0, int64(len(content)), func(o int64) {
+			acked = append(acked, o)
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if res.VersionCode != 7 || res.Sha256 != "abc" {
+			t.Errorf("want version 7 with 'abc' hash, got %+v", res)
+		}
+		if len(acked) != 3 || acked[2] != int64(len(content)) || received.Len() != len(content) {
+			t.Errorf("want 3 chunks with whole content received, got %v acked and %d received", acked, received.Len())
+		}
+	})
+
+	t.Run("should stream rest of file in one request with chunking off", func(t *testing.T) {
+		// Arrange
+		srv, received := newServer(t)
+		received.WriteString("0123")
+		var lengths []int64
+		client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			lengths = append(lengths, req.ContentLength)
+			return srv.Client().Transport.RoundTrip(req)
+		})}
+		rs := &resumableService{client: client, basePath: srv.URL, cfg: &serviceConfig{chunkSize: 0}}
+		content := []byte("0123456789")
+
+		// Act
+		res, err := rs.resumeUpload(context.Background(), srv.URL+"/session", bytes.NewReader(content), 4, int64(len(content)), func(int64) {})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if res == nil || received.String() != string(content) {
+			t.Errorf("want rest of content received, got '%s'", received.String())
+		}
+		if len(lengths) != 1 || lengths[0] != 6 {
+			t.Errorf("want single request of 6 bytes, got %v", lengths)
+		}
+	})
+
+	// Play resumable upload endpoint of unknown size uploads, keeping only first half of chunks it fails
+	newFlakyServer := func(t *testing.T, failures int, status int) (*httptest.Server, *bytes.Buffer) {
+		t.Helper()
+		received := &bytes.Buffer{}
+		var srv *httptest.Server
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				w.Header().Set("Location", srv.URL+"/session")
+				return
+			}
+			rng := strings.TrimPrefix(r.Header.Get("Content-Range"), "bytes ")
+			span, total, _ := strings.Cut(rng, "/")
+			b, _ := io.ReadAll(r.Body)
+			if start, _, ok := strings.Cut(span, "-"); ok && start != strconv.Itoa(received.Len()) {
+				t.Errorf("want chunk sent from %d, got '%s'", received.Len(), rng)
+			}
+			if failures > 0 && len(b) > 0 {
+				failures--
+				received.Write(b[:len(b)/2])
+				w.WriteHeader(status)
+				return
+			}
+			received.Write(b)
+			if total != strconv.Itoa(received.Len()) {
+				w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", received.Len()-1))
+				w.WriteHeader(statusResumeIncomplete)
+				return
+			}
+			fmt.Fprintf(w, `{"versionCode": 7, "sha256": "abc"}`)
+		}))
+		t.Cleanup(srv.Close)
+		return srv, received
+	}
+
+	t.Run("should resume chunk from acknowledged offset once chunk retry deadline expires", func(t *testing.T) {
+		// Arrange
+		srv, received := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+		l := &recordingLogger{}
+		cfg := &serviceConfig{chunkSize: resumableChunkGranularity, chunkRetryDeadline: 0, logger: l}
+		rs := &resumableService{client: srv.Client(), basePath: srv.URL, cfg: cfg}
+		content := bytes.Repeat([]byte("abcd"), resumableChunkGranularity/2+25)
+
+		// Act
+		res, err := rs.uploadChunked(context.Background(), bytes.NewReader(content), "com.test.app", "1", false)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if res.VersionCode != 7 {
+			t.Errorf("want version 7, got %+v", res)
+		}
+		if !bytes.Equal(received.Bytes(), content) {
+			t.Errorf("want whole content received once, got %d of %d bytes", received.Len(), len(content))
+		}
+		if !l.has("WARN upload chunk failed for 0s, resuming at acknowledged") {
+			t.Errorf("want resume warned, got %v", l.lines)
+		}
+	})
+
+	t.Run("should fail chunk refused by Play without resuming", func(t *testing.T) {
+		// Arrange
+		srv, _ := newFlakyServer(t, 1, http.StatusBadRequest)
+		cfg := &serviceConfig{chunkSize: resumableChunkGranularity}
+		rs := &resumableService{client: srv.Client(), basePath: srv.URL, cfg: cfg}
+
+		// Act
+		_, err := rs.uploadChunked(context.Background(), strings.NewReader("binary"), "com.test.app", "1", false)
+
+		// Assert
+		if StatusCode(err) != http.StatusBadRequest {
+			t.Errorf("want 400 error, got: %v", err)
+		}
+	})
+
+	t.Run("should report offset Play confirmed", func(t *testing.T) {
+		// Arrange
+		srv, received := newServer(t)
+		received.WriteString("part")
+		rs := &resumableService{client: srv.Client(), basePath: srv.URL}
+
+		// Act
+		offset, res, err := rs.resumableOffset(context.Background(), srv.URL+"/session", 10)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if offset != 4 || res != nil {
+			t.Errorf("want upload incomplete at 4 bytes, got %d with %+v", offset, res)
+		}
+	})
+
+	t.Run("should refuse service without authorized client", func(t *testing.T) {
+		// Arrange
+		rs := &resumableService{}
+
+		// Act
+		_, err := rs.startResumable(context.Background(), "com.test.app", "1", false, 10)
+
+		// Assert
+		if err != errResumableUnsupported {
+			t.Errorf("want '%v', got '%v'", errResumableUnsupported, err)
+		}
+	})
+}
@@ -0,0 +1,369 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// RetryPolicy controls how transient Google API errors (5xx, 429 and exceeded per minute quota) are retried
+type RetryPolicy struct {
+	Retries    int           // retries after the first failed call, 0 disables retrying
+	Backoff    time.Duration // delay before the first retry, doubled for every next one
+	MaxBackoff time.Duration // upper limit of a single delay, 0 for 30s
+	Jitter     float64       // random share of delay added or removed e.g. 0.2 for +/-20%
+}
+
+// defaultMaxBackoff caps delays of policies without MaxBackoff, so doubling can't overflow
+const defaultMaxBackoff = 30 * time.Second
+
+// DefaultRetryPolicy retries up to 3 times waiting 1s, 2s and 4s
+var DefaultRetryPolicy = RetryPolicy{Retries: 3, Backoff: time.Second, MaxBackoff: defaultMaxBackoff, Jitter: 0.2}
+
+// WithRetryPolicy retries IGService calls failing with transient errors, respecting Retry-After and context
+// deadline: no retry is made once the time left can't fit its backoff and another attempt. Media uploads are not retried as their content can't be replayed, chunks are retried by googleapi instead.
+// Edit commits and image uploads are only retried when refused for rate or quota limits, as failed ones may have gone through.
+func WithRetryPolicy(rp RetryPolicy) ServiceOption {
+	return func(c *serviceConfig) {
+		c.retry = &rp
+	}
+}
+
+// delay returns how long to wait before given retry attempt, counting from 0
+func (rp *RetryPolicy) delay(attempt int, err error) time.Duration {
+	if d, ok := retryAfter(err); ok {
+		return d
+	}
+	maxBackoff := rp.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	d := rp.Backoff
+	for i := 0; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	if rp.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * rp.Jitter * float64(d))
+	}
+	if isRetryableQuotaErr(err) && d < quotaBackoff {
+		d = quotaBackoff
+	}
+	return d
+}
+
+// isTransientErr checks if Google API call could succeed when repeated
+func isTransientErr(err error) bool {
+	var ge *googleapi.Error
+	if !errors.As(err, &ge) {
+		return false
+	}
+	if isQuotaErr(err) {
+		return isRetryableQuotaErr(err)
+	}
+	return ge.Code == http.StatusTooManyRequests || ge.Code >= http.StatusInternalServerError
+}
+
+// retryAfter reads delay requested by server in Retry-After header, as seconds or http date
+func retryAfter(err error) (time.Duration, bool) {
+	var ge *googleapi.Error
+	if !errors.As(err, &ge) || ge.Header == nil {
+		return 0, false
+	}
+	v := ge.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// retryingService retries calls of wrapped service failing with transient errors
+type retryingService struct {
+	IGService
+	policy RetryPolicy
+	log    Logger
+	// retries made so far and time spent waiting for them, see countRetries
+	retries atomic.Int64
+	backoff atomic.Int64
+	// waits between retries and tells time, replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+}
+
+func newRetryingService(gs IGService, rp RetryPolicy, l Logger) *retryingService {
+	return &retryingService{IGService: gs, policy: rp, log: l, sleep: sleepContext, now: time.Now}
+}
+
+func (rs *retryingService) retried() int64 {
+	return rs.retries.Load()
+}
+
+func (rs *retryingService) backedOff() time.Duration {
+	return time.Duration(rs.backoff.Load())
+}
+
+// sleepContext waits for given duration or until context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// retry runs call until it succeeds, fails with non transient error, policy runs out of retries or
+// context deadline leaves no time for another attempt
+func retry[T any](ctx context.Context, rs *retryingService, call func() (T, error)) (T, error) {
+	return retryIf(ctx, rs, isTransientErr, call)
+}
+
+// retryIf retries call failing with errors retryable reports
+func retryIf[T any](ctx context.Context, rs *retryingService, retryable func(error) bool, call func() (T, error)) (T, error) {
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		started := rs.now()
+		v, err := call()
+		if err == nil || attempt >= rs.policy.Retries || !retryable(err) {
+			return v, err
+		}
+		d := rs.policy.delay(attempt, err)
+		// the failed attempt is the best guess of how long the next one takes
+		if deadline, ok := ctx.Deadline(); ok {
+			if left := deadline.Sub(rs.now()); left < d+rs.now().Sub(started) {
+				rs.log.Warnf("transient Google API error, not retrying as %s left until deadline can't fit %s backoff and another attempt, %s spent in backoff: %v",
+					left.Round(time.Millisecond), d.Round(time.Millisecond), waited.Round(time.Millisecond), err)
+				return v, err
+			}
+		}
+		rs.retries.Add(1)
+		if isQuotaErr(err) {
+			rs.log.Warnf("Google API quota exceeded, waiting %s for it to refill before retry %d of %d: %v", d.Round(time.Millisecond), attempt+1, rs.policy.Retries, err)
+		} else {
+			rs.log.Warnf("transient Google API error, retry %d of %d in %s: %v", attempt+1, rs.policy.Retries, d.Round(time.Millisecond), err)
+		}
+		if serr := rs.sleep(ctx, d); serr != nil {
+			return v, err
+		}
+		waited += d
+		rs.backoff.Add(int64(d))
+	}
+}
+
+// retryErr retry for calls returning only an error
+func retryErr(ctx context.Context, rs *retryingService, call func() error) error {
+	_, err := retry(ctx, rs, func() (struct{}, error) {
+		return struct{}{}, call()
+	})
+	return err
+}
+
+func (rs *retryingService) createEdit(ctx context.Context, packageName string) (string, time.Time, error) {
+	var expiresAt time.Time
+	edit, err := retry(ctx, rs, func() (string, error) {
+		e, exp, err := rs.IGService.createEdit(ctx, packageName)
+		expiresAt = exp
+		return e, err
+	})
+	return edit, expiresAt, err
+}
+
+func (rs *retryingService) getEdit(ctx context.Context, packageName, editId string) (time.Time, error) {
+	return retry(ctx, rs, func() (time.Time, error) {
+		return rs.IGService.getEdit(ctx, packageName, editId)
+	})
+}
+
+func (rs *retryingService) validateEdit(ctx context.Context, packageName, editId string) error {
+	return retryErr(ctx, rs, func() error {
+		return rs.IGService.validateEdit(ctx, packageName, editId)
+	})
+}
+
+func (rs *retryingService) deleteEdit(ctx context.Context, packageName, editId string) error {
+	return retryErr(ctx, rs, func() error {
+		return rs.IGService.deleteEdit(ctx, packageName, editId)
+	})
+}
+
+// commitEdit is only retried when Play refused it for rate or quota limits. With 5xx errors and timeouts
+// the commit may have gone through, and retrying it would fail for the committed edit being gone, turning
+// successful publish into a failed one.
+func (rs *retryingService) commitEdit(ctx context.Context, packageName, editId string, changesNotSentForReview bool) error {
+	_, err := retryIf(ctx, rs, isRateLimitedErr, func() (struct{}, error) {
+		return struct{}{}, rs.IGService.commitEdit(ctx, packageName, editId, changesNotSentForReview)
+	})
+	if isTransientErr(err) && !isRateLimitedErr(err) {
+		return fmt.Errorf("commit of edit '%s' failed and is not retried, check the track in Play Console as the commit may have gone through: %w", editId, err)
+	}
+	return err
+}
+
+// isRateLimitedErr checks if call was refused for rate or quota limits before Play acted on it
+func isRateLimitedErr(err error) bool {
+	if isQuotaErr(err) {
+		return isRetryableQuotaErr(err)
+	}
+	return StatusCode(err) == http.StatusTooManyRequests
+}
+
+func (rs *retryingService) createRelease(ctx context.Context, packageName, editId, trackName string, release *androidpublisher.TrackRelease) error {
+	return retryErr(ctx, rs, func() error {
+		return rs.IGService.createRelease(ctx, packageName, editId, trackName, release)
+	})
+}
+
+func (rs *retryingService) versionHashes(ctx context.Context, packageName, editId string) (map[int64]Hashes, error) {
+	return retry(ctx, rs, func() (map[int64]Hashes, error) {
+		return rs.IGService.versionHashes(ctx, packageName, editId)
+	})
+}
+
+func (rs *retryingService) listTracks(ctx context.Context, packageName, editId string) ([]*androidpublisher.Track, error) {
+	return retry(ctx, rs, func() ([]*androidpublisher.Track, error) {
+		return rs.IGService.listTracks(ctx, packageName, editId)
+	})
+}
+
+func (rs *retryingService) getTrack(ctx context.Context, packageName, editId, trackName string) (*androidpublisher.Track, error) {
+	return retry(ctx, rs, func() (*androidpublisher.Track, error) {
+		return rs.IGService.getTrack(ctx, packageName, editId, trackName)
+	})
+}
+
+func (rs *retryingService) updateTrack(ctx context.Context, packageName, editId string, track *androidpublisher.Track) error {
+	return retryErr(ctx, rs, func() error {
+		return rs.IGService.updateTrack(ctx, packageName, editId, track)
+	})
+}
+
+func (rs *retryingService) listListings(ctx context.Context, packageName, editId string) ([]Listing, error) {
+	return retry(ctx, rs, func() ([]Listing, error) {
+		return rs.IGService.listListings(ctx, packageName, editId)
+	})
+}
+
+func (rs *retryingService) updateListing(ctx context.Context, packageName, editId string, listing Listing) error {
+	return retryErr(ctx, rs, func() error {
+		return rs.IGService.updateListing(ctx, packageName, editId, listing)
+	})
+}
+
+func (rs *retryingService) deleteListing(ctx context.Context, packageName, editId, locale string) error {
+	return retryErr(ctx, rs, func() error {
+		return rs.IGService.deleteListing(ctx, packageName, editId, locale)
+	})
+}
+
+func (rs *retryingService) deleteAllImages(ctx context.Context, packageName, editId, locale, imageType string) (int, error) {
+	return retry(ctx, rs, func() (int, error) {
+		return rs.IGService.deleteAllImages(ctx, packageName, editId, locale, imageType)
+	})
+}
+
+func (rs *retryingService) listImages(ctx context.Context, packageName, editId, locale, imageType string) ([]string, error) {
+	return retry(ctx, rs, func() ([]string, error) {
+		return rs.IGService.listImages(ctx, packageName, editId, locale, imageType)
+	})
+}
+
+func (rs *retryingService) uploadImage(ctx context.Context, r io.Reader, packageName, editId, locale, imageType string) error {
+	// images are small and read into memory, so refused uploads can be replayed. Like commits they're only retried
+	// when refused for rate or quota limits, upload failing otherwise may have stored the image already.
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = retryIf(ctx, rs, isRateLimitedErr, func() (struct{}, error) {
+		return struct{}{}, rs.IGService.uploadImage(ctx, bytes.NewReader(b), packageName, editId, locale, imageType)
+	})
+	return err
+}
+
+func (rs *retryingService) listReviews(ctx context.Context, packageName string) ([]Review, error) {
+	return retry(ctx, rs, func() ([]Review, error) {
+		return rs.IGService.listReviews(ctx, packageName)
+	})
+}
+
+func (rs *retryingService) getReview(ctx context.Context, packageName, reviewId string) (*Review, error) {
+	return retry(ctx, rs, func() (*Review, error) {
+		return rs.IGService.getReview(ctx, packageName, reviewId)
+	})
+}
+
+// replies replace previous reply of a review, so repeating one is safe
+func (rs *retryingService) replyReview(ctx context.Context, packageName, reviewId, text string) error {
+	return retryErr(ctx, rs, func() error {
+		return rs.IGService.replyReview(ctx, packageName, reviewId, text)
+	})
+}
+
+func (rs *retryingService) getDetails(ctx context.Context, packageName, editId string) (*AppDetails, error) {
+	return retry(ctx, rs, func() (*AppDetails, error) {
+		return rs.IGService.getDetails(ctx, packageName, editId)
+	})
+}
+
+func (rs *retryingService) updateDetails(ctx context.Context, packageName, editId string, details AppDetails) error {
+	return retryErr(ctx, rs, func() error {
+		return rs.IGService.updateDetails(ctx, packageName, editId, details)
+	})
+}
+
+func (rs *retryingService) startResumable(ctx context.Context, packageName, editId string, isApk bool, size int64) (string, error) {
+	us, ok := rs.IGService.(IResumableUploadService)
+	if !ok {
+		return "", errResumableUnsupported
+	}
+	return retry(ctx, rs, func() (string, error) {
+		return us.startResumable(ctx, packageName, editId, isApk, size)
+	})
+}
+
+func (rs *retryingService) resumableOffset(ctx context.Context, sessionURI string, size int64) (int64, *resumableResult, error) {
+	us, ok := rs.IGService.(IResumableUploadService)
+	if !ok {
+		return 0, nil, errResumableUnsupported
+	}
+	var res *resumableResult
+	offset, err := retry(ctx, rs, func() (int64, error) {
+		o, r, err := us.resumableOffset(ctx, sessionURI, size)
+		res = r
+		return o, err
+	})
+	return offset, res, err
+}
+
+// resumeUpload passes through, interrupted upload continues from the last confirmed offset on the next run
+func (rs *retryingService) resumeUpload(ctx context.Context, sessionURI string, r io.ReadSeeker, offset, size int64, acked func(offset int64)) (*resumableResult, error) {
+	us, ok := rs.IGService.(IResumableUploadService)
+	if !ok {
+		return nil, errResumableUnsupported
+	}
+	return us.resumeUpload(ctx, sessionURI, r, offset, size, acked)
+}
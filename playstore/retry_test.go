@@ -0,0 +1,225 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryingService(t *testing.T) {
+
+	t.Run("should retry transient errors until call succeeds", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{validateErrors: []error{&googleapi.Error{Code: 503}, &googleapi.Error{Code: 429}}}
+		rs, delays := newTestRetryingService(gs, RetryPolicy{Retries: 3, Backoff: time.Second})
+
+		// Act
+		err := rs.validateEdit(context.Background(), "com.test.app", "1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.validateEditCount != 3 {
+			t.Errorf("want 3 validate calls, got %d", gs.validateEditCount)
+		}
+		if len(*delays) != 2 || (*delays)[0] != time.Second || (*delays)[1] != 2*time.Second {
+			t.Errorf("want [1s 2s] backoff, got %v", *delays)
+		}
+	})
+
+	t.Run("should not retry client errors", func(t *testing.T) {
+		// Arrange
+		apiErr := &googleapi.Error{Code: 400}
+		gs := &mockGService{commitErrors: []error{apiErr}}
+		rs, _ := newTestRetryingService(gs, RetryPolicy{Retries: 3, Backoff: time.Second})
+
+		// Act
+		err := rs.commitEdit(context.Background(), "com.test.app", "1", false)
+
+		// Assert
+		if !errors.Is(err, apiErr) {
+			t.Errorf("want '%v', got '%v'", apiErr, err)
+		}
+		if gs.commitEditCount != 1 {
+			t.Errorf("want 1 commit call, got %d", gs.commitEditCount)
+		}
+	})
+
+	t.Run("should give up once retries run out", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{validateErrors: []error{&googleapi.Error{Code: 500}, &googleapi.Error{Code: 500}, &googleapi.Error{Code: 500}}}
+		rs, _ := newTestRetryingService(gs, RetryPolicy{Retries: 1, Backoff: time.Second})
+
+		// Act
+		err := rs.validateEdit(context.Background(), "com.test.app", "1")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.validateEditCount != 2 {
+			t.Errorf("want 2 validate calls, got %d", gs.validateEditCount)
+		}
+	})
+
+	t.Run("should not retry commit failing with server errors", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{commitErrors: []error{&googleapi.Error{Code: 503}}}
+		rs, delays := newTestRetryingService(gs, RetryPolicy{Retries: 3, Backoff: time.Second})
+
+		// Act
+		err := rs.commitEdit(context.Background(), "com.test.app", "1", false)
+
+		// Assert
+		if StatusCode(err) != 503 || !strings.Contains(err.Error(), "may have gone through") {
+			t.Errorf("want 503 error telling commit may have gone through, got: %v", err)
+		}
+		if gs.commitEditCount != 1 || len(*delays) != 0 {
+			t.Errorf("want single commit call, got %d after %v", gs.commitEditCount, *delays)
+		}
+	})
+
+	t.Run("should wait as long as Retry-After asks", func(t *testing.T) {
+		// Arrange
+		h := http.Header{}
+		h.Set("Retry-After", "7")
+		gs := &mockGService{commitErrors: []error{&googleapi.Error{Code: 429, Header: h}}}
+		rs, delays := newTestRetryingService(gs, RetryPolicy{Retries: 1, Backoff: time.Second})
+
+		// Act
+		err := rs.commitEdit(context.Background(), "com.test.app", "1", false)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(*delays) != 1 || (*delays)[0] != 7*time.Second {
+			t.Errorf("want [7s] delay, got %v", *delays)
+		}
+	})
+
+	t.Run("should stop retrying once deadline can't fit another attempt", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{validateErrors: []error{&googleapi.Error{Code: 503}, &googleapi.Error{Code: 503}, &googleapi.Error{Code: 503}}}
+		rs, delays := newTestRetryingService(gs, RetryPolicy{Retries: 3, Backoff: time.Second})
+		ctx, cancel := context.WithDeadline(context.Background(), rs.now().Add(2500*time.Millisecond))
+		defer cancel()
+
+		// Act
+		err := rs.validateEdit(ctx, "com.test.app", "1")
+
+		// Assert
+		if StatusCode(err) != 503 {
+			t.Errorf("want 503 error, got: %v", err)
+		}
+		if gs.validateEditCount != 2 {
+			t.Errorf("want 2 validate calls, got %d", gs.validateEditCount)
+		}
+		if len(*delays) != 1 || rs.retried() != 1 || rs.backedOff() != time.Second {
+			t.Errorf("want 1 retry after 1s backoff, got %d retries after %v", rs.retried(), rs.backedOff())
+		}
+	})
+
+	t.Run("should not retry image upload failing with server errors", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{Error: &googleapi.Error{Code: 503}}
+		rs, delays := newTestRetryingService(gs, RetryPolicy{Retries: 3, Backoff: time.Second})
+
+		// Act
+		err := rs.uploadImage(context.Background(), strings.NewReader("png"), "com.test.app", "1", "en-US", "icon")
+
+		// Assert
+		if StatusCode(err) != 503 {
+			t.Errorf("want 503 error, got: %v", err)
+		}
+		if len(gs.uploadedImages) != 1 || len(*delays) != 0 {
+			t.Errorf("want single image upload, got %v after %v", gs.uploadedImages, *delays)
+		}
+	})
+
+	t.Run("should cap backoff at max backoff", func(t *testing.T) {
+		// Arrange
+		rp := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+		// Act
+		d := rp.delay(10, errors.New("failed"))
+
+		// Assert
+		if d != 5*time.Second {
+			t.Errorf("want 5s delay, got %v", d)
+		}
+	})
+
+	t.Run("should cap backoff at default when max backoff is unset", func(t *testing.T) {
+		// Arrange
+		rp := RetryPolicy{Backoff: time.Second}
+
+		// Act
+		d := rp.delay(70, errors.New("failed"))
+
+		// Assert
+		if d != defaultMaxBackoff {
+			t.Errorf("want %v delay, got %v", defaultMaxBackoff, d)
+		}
+	})
+
+	t.Run("should wait for quota to refill before retrying", func(t *testing.T) {
+		// Arrange
+		quotaErr := &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}
+		gs := &mockGService{commitErrors: []error{quotaErr}}
+		rs, delays := newTestRetryingService(gs, RetryPolicy{Retries: 3, Backoff: time.Second})
+
+		// Act
+		err := rs.commitEdit(context.Background(), "com.test.app", "1", false)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(*delays) != 1 || (*delays)[0] != quotaBackoff {
+			t.Errorf("want [%v] delay, got %v", quotaBackoff, *delays)
+		}
+		if !rs.log.(*recordingLogger).has("WARN Google API quota exceeded, waiting 1m0s") {
+			t.Error("want quota wait logged")
+		}
+	})
+
+	t.Run("should not retry exceeded daily quota", func(t *testing.T) {
+		// Arrange
+		quotaErr := &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "dailyLimitExceeded"}}}
+		gs := &mockGService{commitErrors: []error{quotaErr}}
+		rs, _ := newTestRetryingService(gs, RetryPolicy{Retries: 3, Backoff: time.Second})
+
+		// Act
+		err := rs.commitEdit(context.Background(), "com.test.app", "1", false)
+
+		// Assert
+		if !errors.Is(apiError("commit edit", err), ErrQuotaExceeded) {
+			t.Errorf("want quota exceeded error, got: %v", err)
+		}
+		if gs.commitEditCount != 1 {
+			t.Errorf("want 1 commit call, got %d", gs.commitEditCount)
+		}
+	})
+}
+
+// newTestRetryingService returns service recording delays instead of sleeping
+func newTestRetryingService(gs IGService, rp RetryPolicy) (*retryingService, *[]time.Duration) {
+	delays := make([]time.Duration, 0)
+	rs := newRetryingService(gs, rp, &recordingLogger{})
+	// time only moves on while waiting
+	clock := time.Now()
+	rs.now = func() time.Time { return clock }
+	rs.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		clock = clock.Add(d)
+		return nil
+	}
+	return rs, &delays
+}
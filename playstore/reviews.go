@@ -0,0 +1,140 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// reply text Play accepts at most, longer replies are rejected
+const reviewReplyMaxLength = 350
+
+// Review user comment of a review with developer reply to it, if any
+type Review struct {
+	ID             string     `json:"id"`
+	Author         string     `json:"author,omitempty"`
+	StarRating     int64      `json:"starRating"`
+	Text           string     `json:"text"`
+	Language       string     `json:"language,omitempty"`
+	AppVersionCode int64      `json:"appVersionCode,omitempty"`
+	LastModified   time.Time  `json:"lastModified"`
+	Reply          string     `json:"reply,omitempty"`
+	RepliedAt      *time.Time `json:"repliedAt,omitempty"`
+}
+
+// ReviewStats summary of reviews modified within a window
+type ReviewStats struct {
+	Since   time.Time     `json:"since"`
+	Total   int           `json:"total"`
+	Average float64       `json:"average"`
+	ByStars map[int64]int `json:"byStars"` // review count for every 1-5 star rating
+}
+
+/**
+ * ReviewStatsSince counts reviews modified since given time per star rating and their average rating
+ *
+ * Play only lists reviews with comments modified during the last week, so older since is the same as a week ago
+ */
+func ReviewStatsSince(ctx context.Context, gs IGService, packageName string, since time.Time) (*ReviewStats, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := gs.listReviews(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing reviews: %w", unavailableError("reviews", err))
+	}
+	return reviewStats(reviews, since), nil
+}
+
+// reviewStats aggregates reviews modified since given time, ratings outside 1-5 are skipped
+func reviewStats(reviews []Review, since time.Time) *ReviewStats {
+	stats := &ReviewStats{Since: since, ByStars: map[int64]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	var sum int64
+	for _, r := range reviews {
+		if r.LastModified.Before(since) || r.StarRating < 1 || r.StarRating > 5 {
+			continue
+		}
+		stats.ByStars[r.StarRating]++
+		stats.Total++
+		sum += r.StarRating
+	}
+	if stats.Total > 0 {
+		stats.Average = float64(sum) / float64(stats.Total)
+	}
+	return stats
+}
+
+/**
+ * ListReviews returns reviews modified since given time, most recently modified first
+ *
+ * Play only lists reviews with comments modified during the last week, so older since is the same as a week ago
+ */
+func ListReviews(ctx context.Context, gs IGService, packageName string, since time.Time) ([]Review, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := gs.listReviews(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing reviews: %w", unavailableError("reviews", err))
+	}
+	recent := make([]Review, 0, len(reviews))
+	for _, r := range reviews {
+		if !r.LastModified.Before(since) {
+			recent = append(recent, r)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].LastModified.After(recent[j].LastModified)
+	})
+	return recent, nil
+}
+
+// GetReview returns review by ID, including reviews older than ListReviews lists
+func GetReview(ctx context.Context, gs IGService, packageName, reviewID string) (*Review, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(reviewID) == "" {
+		return nil, fmt.Errorf("review ID must not be empty")
+	}
+	r, err := gs.getReview(ctx, name, reviewID)
+	return r, unavailableError("reviews", err)
+}
+
+// ReplyToReview replies to review, replacing previous reply if there was one
+func ReplyToReview(ctx context.Context, gs IGService, packageName, reviewID, text string) error {
+	if gs == nil {
+		return errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(reviewID) == "" {
+		return fmt.Errorf("review ID must not be empty")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("reply to review '%s' must not be empty", reviewID)
+	}
+	if n := utf8.RuneCountInString(text); n > reviewReplyMaxLength {
+		return fmt.Errorf("reply to review '%s' is %d characters, at most %d allowed", reviewID, n, reviewReplyMaxLength)
+	}
+	return gs.replyReview(ctx, name, reviewID, text)
+}
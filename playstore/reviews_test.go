@@ -0,0 +1,130 @@
+package playstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestReviewStatsSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should count reviews per star rating within window", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{reviews: []Review{
+			{ID: "1", StarRating: 5, LastModified: now.Add(-time.Hour)},
+			{ID: "2", StarRating: 4, LastModified: now.Add(-2 * time.Hour)},
+			{ID: "3", StarRating: 5, LastModified: now.Add(-24 * time.Hour)},
+			{ID: "4", StarRating: 1, LastModified: now.Add(-72 * time.Hour)},
+			{ID: "5", StarRating: 0, LastModified: now.Add(-time.Hour)},
+		}}
+
+		// Act
+		stats, err := ReviewStatsSince(context.Background(), gs, "com.test.app", now.Add(-48*time.Hour))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if stats.Total != 3 {
+			t.Errorf("want 3 reviews counted, got %d", stats.Total)
+		}
+		if stats.ByStars[5] != 2 || stats.ByStars[4] != 1 || stats.ByStars[1] != 0 {
+			t.Errorf("want two 5 star and one 4 star review, got %v", stats.ByStars)
+		}
+		if want := 14.0 / 3; stats.Average != want {
+			t.Errorf("want %f average, got %f", want, stats.Average)
+		}
+	})
+
+	t.Run("should report zero average without reviews", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		stats, err := ReviewStatsSince(context.Background(), gs, "com.test.app", now)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if stats.Total != 0 || stats.Average != 0 || len(stats.ByStars) != 5 {
+			t.Errorf("want empty stats with every rating, got %+v", stats)
+		}
+	})
+}
+
+func TestReviews(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should list reviews since given time newest first", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{reviews: []Review{
+			{ID: "1", Text: "old", LastModified: now.Add(-72 * time.Hour)},
+			{ID: "2", Text: "older", LastModified: now.Add(-2 * time.Hour)},
+			{ID: "3", Text: "newest", LastModified: now.Add(-time.Hour)},
+		}}
+
+		// Act
+		reviews, err := ListReviews(context.Background(), gs, "com.test.app", now.Add(-48*time.Hour))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(reviews) != 2 || reviews[0].ID != "3" || reviews[1].ID != "2" {
+			t.Errorf("want reviews 3 and 2, got %+v", reviews)
+		}
+	})
+
+	t.Run("should reply to review", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		err := ReplyToReview(context.Background(), gs, "com.test.app", "r1", " Thanks, fixed in 1.2 ")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.replies["r1"] != "Thanks, fixed in 1.2" {
+			t.Errorf("want trimmed reply sent, got %v", gs.replies)
+		}
+	})
+
+	t.Run("should refuse reply over length limit", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		err := ReplyToReview(context.Background(), gs, "com.test.app", "r1", strings.Repeat("a", reviewReplyMaxLength+1))
+
+		// Assert
+		if err == nil || len(gs.replies) != 0 {
+			t.Errorf("want error without reply sent, got %v: %v", gs.replies, err)
+		}
+	})
+
+	t.Run("should take user comment and developer reply of review", func(t *testing.T) {
+		// Arrange
+		r := &androidpublisher.Review{ReviewId: "r1", AuthorName: "Ann", Comments: []*androidpublisher.Comment{
+			{UserComment: &androidpublisher.UserComment{Text: "\tCrashes ", StarRating: 2, AppVersionCode: 12, LastModified: &androidpublisher.Timestamp{Seconds: now.Unix()}}},
+			{DeveloperComment: &androidpublisher.DeveloperComment{Text: "Fixed", LastModified: &androidpublisher.Timestamp{Seconds: now.Unix() + 60}}},
+		}}
+
+		// Act
+		review, ok := userReview(r)
+
+		// Assert
+		if !ok || review.Text != "Crashes" || review.StarRating != 2 || review.Author != "Ann" || !review.LastModified.Equal(now) {
+			t.Errorf("want user comment taken, got %+v", review)
+		}
+		if review.Reply != "Fixed" || review.RepliedAt == nil || !review.RepliedAt.Equal(now.Add(time.Minute)) {
+			t.Errorf("want developer reply taken, got %+v", review)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+// RolloutReport staged rollout change of a track release
+type RolloutReport struct {
+	Track        string  `json:"track"`
+	VersionCodes []int64 `json:"versionCodes"`
+	From         float64 `json:"from"`
+	To           float64 `json:"to"`
+}
+
+/**
+ * UpdateRolloutFraction changes user fraction of the in progress release on a track, without uploading anything
+ *
+ * fraction - new share of users e.g. 0.25, must be greater than current one and less than 1
+ * opts - AllowProduction() for production track, WithNotSentForReviewFallback() and WithManagedPublishing(...) apply to commit
+ */
+func UpdateRolloutFraction(ctx context.Context, gs IGService, packageName, trackName string, fraction float64, opts ...Option) (*RolloutReport, error) {
+	if fraction <= 0 || fraction >= 1 {
+		return nil, fmt.Errorf("rollout fraction must be greater than 0 and less than 1, got %v", fraction)
+	}
+	return updateRollout(ctx, gs, packageName, trackName, opts, func(t string, track *androidpublisher.Track, r *androidpublisher.TrackRelease) (*RolloutReport, error) {
+		if fraction <= r.UserFraction {
+			return nil, fmt.Errorf("rollout fraction can only be increased, '%s' track release is at %v already", t, r.UserFraction)
+		}
+		report := &RolloutReport{Track: t, VersionCodes: r.VersionCodes, From: r.UserFraction, To: fraction}
+		r.UserFraction = fraction
+		return report, nil
+	})
+}
+
+/**
+ * CompleteRollout releases the in progress release on a track to every user, replacing completed release
+ * it was rolled out next to, without uploading anything
+ *
+ * opts - AllowProduction() for production track, WithNotSentForReviewFallback() and WithManagedPublishing(...) apply to commit
+ */
+func CompleteRollout(ctx context.Context, gs IGService, packageName, trackName string, opts ...Option) (*RolloutReport, error) {
+	return updateRollout(ctx, gs, packageName, trackName, opts, func(t string, track *androidpublisher.Track, r *androidpublisher.TrackRelease) (*RolloutReport, error) {
+		report := &RolloutReport{Track: t, VersionCodes: r.VersionCodes, From: r.UserFraction, To: 1}
+		r.Status = StatusCompleted
+		r.UserFraction = 0
+		releases := make([]*androidpublisher.TrackRelease, 0, len(track.Releases))
+		for _, other := range track.Releases {
+			if other != r && other.Status == StatusCompleted {
+				continue
+			}
+			releases = append(releases, other)
+		}
+		track.Releases = releases
+		return report, nil
+	})
+}
+
+// updateRollout applies change to the in progress release of a track within a new edit and commits it
+func updateRollout(ctx context.Context, gs IGService, packageName, trackName string, opts []Option, change func(string, *androidpublisher.Track, *androidpublisher.TrackRelease) (*RolloutReport, error)) (*RolloutReport, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+
+	p := &publish{}
+	for _, o := range opts {
+		o(p)
+	}
+	pr := p.profile
+	if pr == nil {
+		pr = &ProfileDefault
+	}
+
+	name, err := validatePackageName(packageName, p.anyPackageName)
+	if err != nil {
+		return nil, err
+	}
+	t, err := p.targetTrack(pr, trackName)
+	if err != nil {
+		return nil, err
+	}
+	p.packageName = name
+	p.track = t
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkTrack(ctx, gs, edit, t); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+
+	track, err := gs.getTrack(ctx, name, edit, t)
+	if err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("failed reading '%s' track: %w", t, err)
+	}
+	var report *RolloutReport
+	for _, r := range track.Releases {
+		if r.Status != StatusInProgress {
+			continue
+		}
+		if report, err = change(t, track, r); err != nil {
+			gs.deleteEdit(ctx, name, edit)
+			return nil, err
+		}
+		break
+	}
+	if report == nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("'%s' track has no release in progress", t)
+	}
+
+	if err := gs.updateTrack(ctx, name, edit, track); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("failed updating track '%s': %w", t, err)
+	}
+	if err := gs.validateEdit(ctx, name, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if err := p.commit(ctx, gs, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	return report, nil
+}
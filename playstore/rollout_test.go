@@ -0,0 +1,137 @@
+package playstore
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestUpdateRolloutFraction(t *testing.T) {
+
+	beta := func() *androidpublisher.Track {
+		return &androidpublisher.Track{
+			Track: TrackBeta,
+			Releases: []*androidpublisher.TrackRelease{
+				{Status: StatusCompleted, VersionCodes: []int64{10}},
+				{Status: StatusInProgress, UserFraction: 0.1, VersionCodes: []int64{11}},
+			},
+		}
+	}
+
+	t.Run("should raise user fraction of in progress release", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: beta()}}
+
+		// Act
+		r, err := UpdateRolloutFraction(context.Background(), gs, "com.test.app", TrackBeta, 0.25)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if r.From != 0.1 || r.To != 0.25 {
+			t.Errorf("want rollout from 0.1 to 0.25, got %+v", r)
+		}
+		if len(gs.updatedTracks) != 1 || gs.updatedTracks[0].Releases[1].UserFraction != 0.25 || gs.commitEditCount != 1 {
+			t.Errorf("want committed track update with 0.25 fraction, got %d updates %d commits", len(gs.updatedTracks), gs.commitEditCount)
+		}
+	})
+
+	t.Run("should refuse lowering rollout fraction", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: beta()}}
+
+		// Act
+		_, err := UpdateRolloutFraction(context.Background(), gs, "com.test.app", TrackBeta, 0.05)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if len(gs.updatedTracks) != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted without changes, got %d updates", len(gs.updatedTracks))
+		}
+	})
+
+	t.Run("should fail without release in progress", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		_, err := UpdateRolloutFraction(context.Background(), gs, "com.test.app", TrackBeta, 0.5)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should require confirmation for production", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		_, err := UpdateRolloutFraction(context.Background(), gs, "com.test.app", TrackProduction, 0.5)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if gs.createEditCount != 0 {
+			t.Errorf("want no edit created, got %d", gs.createEditCount)
+		}
+	})
+}
+
+func TestCompleteRollout(t *testing.T) {
+
+	t.Run("should complete in progress release replacing completed one", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackProduction: {
+			Track: TrackProduction,
+			Releases: []*androidpublisher.TrackRelease{
+				{Status: StatusCompleted, VersionCodes: []int64{10}},
+				{Status: StatusInProgress, UserFraction: 0.5, VersionCodes: []int64{11}},
+				{Status: StatusDraft, VersionCodes: []int64{12}},
+			},
+		}}}
+
+		// Act
+		r, err := CompleteRollout(context.Background(), gs, "com.test.app", TrackProduction, AllowProduction())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if r.From != 0.5 || r.To != 1 {
+			t.Errorf("want rollout from 0.5 to 1, got %+v", r)
+		}
+		if len(gs.updatedTracks) != 1 || gs.commitEditCount != 1 {
+			t.Fatalf("want committed track update, got %d updates %d commits", len(gs.updatedTracks), gs.commitEditCount)
+		}
+		releases := gs.updatedTracks[0].Releases
+		if len(releases) != 2 || releases[0].Status != StatusCompleted || releases[0].UserFraction != 0 || releases[1].Status != StatusDraft {
+			t.Errorf("want completed appVersion 11 next to draft, got %+v", releases)
+		}
+	})
+
+	t.Run("should fail without release in progress", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: {
+			Track:    TrackBeta,
+			Releases: []*androidpublisher.TrackRelease{{Status: StatusCompleted, VersionCodes: []int64{10}}},
+		}}}
+
+		// Act
+		_, err := CompleteRollout(context.Background(), gs, "com.test.app", TrackBeta)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if len(gs.updatedTracks) != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted without changes, got %d updates", len(gs.updatedTracks))
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package playstore
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// WithRunID tags publish with given run ID instead of a generated one, e.g. CI job ID,
+// so retried jobs report the same ID in logs, receipts and abort reports
+func WithRunID(id string) Option {
+	return func(p *publish) {
+		p.runID = id
+	}
+}
+
+// RunID identifies UploadFiles run in logs, receipts and abort reports, empty before UploadFiles.
+// Generated as random UUID unless set with WithRunID.
+func (p *publish) RunID() string {
+	return p.runID
+}
+
+// newRunID returns random version 4 UUID
+func newRunID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed generating run id: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,52 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestRunID(t *testing.T) {
+
+	t.Run("should generate uuid run id for every upload", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		first, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+		second, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false)
+
+		// Act
+		first.UploadFiles(context.Background(), &mockGService{})
+		second.UploadFiles(context.Background(), &mockGService{})
+
+		// Assert
+		uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		if !uuid.MatchString(first.RunID()) {
+			t.Errorf("want uuid run id, got '%s'", first.RunID())
+		}
+		if first.RunID() == second.RunID() {
+			t.Errorf("want different run ids, got '%s' twice", first.RunID())
+		}
+	})
+
+	t.Run("should report given run id when upload aborts", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		bin, _, _ := createMockBinary(t, fs, "test.aab", "")
+		publish, _ := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{bin}, false, false, WithRunID("ci-1234"))
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), &mockGService{Error: errors.New("failed")})
+
+		// Assert
+		var ae *AbortError
+		if !errors.As(err, &ae) || ae.RunID != "ci-1234" {
+			t.Errorf("want abort error with 'ci-1234' run id, got: %v", err)
+		}
+	})
+}
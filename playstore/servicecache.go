@@ -0,0 +1,83 @@
+package playstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/option"
+)
+
+// ServiceCache reuses one authorized androidpublisher client, token cache included, per credentials,
+// so publishing many apps in one process doesn't authorize again for every one of them
+type ServiceCache struct {
+	mu       sync.Mutex
+	services map[string]cachedService
+	// creates androidpublisher client, replaced in tests
+	create func(ctx context.Context, credentials option.ClientOption, cfg *serviceConfig) (*androidpublisher.Service, *http.Client, error)
+}
+
+// cachedService androidpublisher service with authorized client it was created from, the latter shared
+// with resumable uploads
+type cachedService struct {
+	edits  *androidpublisher.Service
+	client *http.Client
+}
+
+// NewServiceCache returns empty service cache, safe for concurrent use
+func NewServiceCache() *ServiceCache {
+	return &ServiceCache{
+		services: make(map[string]cachedService),
+		create: func(ctx context.Context, credentials option.ClientOption, cfg *serviceConfig) (*androidpublisher.Service, *http.Client, error) {
+			client, err := newClient(ctx, credentials, cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			edits, err := androidpublisher.NewService(ctx, option.WithHTTPClient(client))
+			if err != nil {
+				return nil, nil, err
+			}
+			return edits, client, nil
+		},
+	}
+}
+
+// Service returns service authorized with auth file, creating client on first use.
+// Options apply to returned service only, cached clients are shared between them.
+func (sc *ServiceCache) Service(authFile string, opts ...ServiceOption) (IGService, error) {
+	return sc.service("file:"+authFile, option.WithCredentialsFile(authFile), opts...)
+}
+
+// ServiceFromJSON returns service authorized with service account JSON, creating client on first use
+func (sc *ServiceCache) ServiceFromJSON(credentials []byte, opts ...ServiceOption) (IGService, error) {
+	sum := sha256.Sum256(credentials)
+	return sc.service("json:"+hex.EncodeToString(sum[:]), option.WithCredentialsJSON(credentials), opts...)
+}
+
+// ServiceWithADC returns service authorized with Application Default Credentials, creating client on first use
+func (sc *ServiceCache) ServiceWithADC(opts ...ServiceOption) (IGService, error) {
+	return sc.service("adc", adcCredentials(), opts...)
+}
+
+func (sc *ServiceCache) service(key string, credentials option.ClientOption, opts ...ServiceOption) (IGService, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	// proxy and tracing are done by client transport, so such clients are cached apart
+	cfg := newServiceConfig(opts...)
+	key += cfg.clientKey()
+	cached, ok := sc.services[key]
+	if !ok {
+		// cached client outlives any single publish, so it refreshes tokens with a context of its own
+		edits, client, err := sc.create(context.Background(), credentials, cfg)
+		if err != nil {
+			return nil, err
+		}
+		cached = cachedService{edits: edits, client: client}
+		sc.services[key] = cached
+	}
+	return newGService(cached.edits, cached.client, opts...), nil
+}
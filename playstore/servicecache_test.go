@@ -0,0 +1,115 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/option"
+)
+
+func TestServiceCache(t *testing.T) {
+
+	t.Run("should create one client per credentials", func(t *testing.T) {
+		// Arrange
+		sc, created := newTestServiceCache(nil)
+
+		// Act
+		for i := 0; i < 3; i++ {
+			if _, err := sc.Service("auth.json"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := sc.ServiceFromJSON([]byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sc.ServiceFromJSON([]byte(`{}`), WithRetryPolicy(DefaultRetryPolicy)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sc.ServiceWithADC(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if *created != 3 {
+			t.Errorf("want 3 clients created, got %d", *created)
+		}
+	})
+
+	t.Run("should cache traced clients apart", func(t *testing.T) {
+		// Arrange
+		sc, created := newTestServiceCache(nil)
+
+		// Act
+		sc.Service("auth.json")
+		sc.Service("auth.json", WithHTTPTracing())
+		sc.Service("auth.json", WithHTTPTracing())
+
+		// Assert
+		if *created != 2 {
+			t.Errorf("want 2 clients created, got %d", *created)
+		}
+	})
+
+	t.Run("should not cache failed client", func(t *testing.T) {
+		// Arrange
+		sc, created := newTestServiceCache(errors.New("invalid credentials"))
+
+		// Act
+		sc.Service("auth.json")
+		_, err := sc.Service("auth.json")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+		if *created != 2 {
+			t.Errorf("want client creation retried, got %d attempts", *created)
+		}
+	})
+
+	t.Run("should start resumable upload with cached client", func(t *testing.T) {
+		// Arrange
+		var started *http.Request
+		base := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			started = req
+			header := http.Header{"Location": []string{"https://upload.example/session-1"}}
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: http.NoBody, Request: req}, nil
+		})}
+		gs, err := NewServiceCache().service("test", option.WithoutAuthentication(), WithHTTPClient(base))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		uri, err := gs.(IResumableUploadService).startResumable(context.Background(), "com.test.app", "1", false, 20)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want resumable session started, got: %v", err)
+		}
+		if uri != "https://upload.example/session-1" {
+			t.Errorf("want session URI of Play, got '%s'", uri)
+		}
+		if started == nil || !strings.Contains(started.URL.Path, "/upload/androidpublisher/v3/applications/com.test.app/edits/1/bundles") {
+			t.Errorf("want session started through given transport, got %v", started)
+		}
+	})
+}
+
+// newTestServiceCache returns cache counting clients it creates
+func newTestServiceCache(err error) (*ServiceCache, *int) {
+	created := 0
+	sc := NewServiceCache()
+	sc.create = func(ctx context.Context, credentials option.ClientOption, cfg *serviceConfig) (*androidpublisher.Service, *http.Client, error) {
+		created++
+		if err != nil {
+			return nil, nil, err
+		}
+		return &androidpublisher.Service{Edits: &androidpublisher.EditsService{}}, nil, nil
+	}
+	return sc, &created
+}
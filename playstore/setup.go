@@ -0,0 +1,133 @@
+package playstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+// kinds of setup problems Diagnose tells apart
+const (
+	SetupAPIDisabled        = "api-disabled"
+	SetupMissingScope       = "missing-scope"
+	SetupNotInvited         = "not-invited"
+	SetupInvalidCredentials = "invalid-credentials"
+)
+
+// SetupProblem common first-run setup failure with steps fixing it
+type SetupProblem struct {
+	Kind    string   `json:"kind"`
+	Summary string   `json:"summary"`
+	Steps   []string `json:"steps"`
+}
+
+func (sp *SetupProblem) String() string {
+	var b strings.Builder
+	b.WriteString(sp.Summary)
+	for i, s := range sp.Steps {
+		fmt.Fprintf(&b, "\n  %d. %s", i+1, s)
+	}
+	return b.String()
+}
+
+// Diagnose tells setup problem err of a Google API call points to, e.g. Play Developer API not enabled for
+// the project or service account not invited to Play Console. Nil for errors not caused by setup.
+func Diagnose(err error) *SetupProblem {
+	if err == nil {
+		return nil
+	}
+	var re *oauth2.RetrieveError
+	if errors.As(err, &re) {
+		return &SetupProblem{Kind: SetupInvalidCredentials, Summary: "Google refused credentials when requesting access token.", Steps: []string{
+			"Check the service account key wasn't deleted or disabled in Google Cloud Console > IAM & Admin > Service Accounts.",
+			"Check system clock is in sync, tokens of keys signed with skewed clock are refused.",
+			"Create a new JSON key for the service account and use it as auth file if the problem stays.",
+		}}
+	}
+	var ge *googleapi.Error
+	if !errors.As(err, &ge) {
+		ge = &googleapi.Error{}
+	}
+	switch {
+	case hasReason(ge, "accessNotConfigured", "SERVICE_DISABLED"):
+		return &SetupProblem{Kind: SetupAPIDisabled, Summary: "Google Play Android Developer API isn't enabled for the Google Cloud project of the credentials.", Steps: []string{
+			"Open https://console.cloud.google.com/apis/library/androidpublisher.googleapis.com and select the project the service account belongs to.",
+			"Click Enable.",
+			"Wait a few minutes for the change to propagate, then run again.",
+		}}
+	case hasReason(ge, "insufficientPermissions", "ACCESS_TOKEN_SCOPE_INSUFFICIENT"):
+		return &SetupProblem{Kind: SetupMissingScope, Summary: "Access token lacks https://www.googleapis.com/auth/androidpublisher scope.", Steps: []string{
+			"With gcloud user credentials, run: gcloud auth application-default login --scopes=https://www.googleapis.com/auth/androidpublisher,https://www.googleapis.com/auth/cloud-platform",
+			"With workload identity or VM metadata credentials, grant the instance or pod service account the androidpublisher scope.",
+			"With a service account key as auth file scopes are requested automatically, check credentials used are the ones intended.",
+		}}
+	case ge.Code == http.StatusUnauthorized, ge.Code == http.StatusForbidden && hasReason(ge, "permissionDenied", "forbidden"),
+		errors.Is(err, ErrNoAppAccess):
+		return notInvited()
+	}
+	return nil
+}
+
+// notInvited service account has no access to the app in Play Console
+func notInvited() *SetupProblem {
+	return &SetupProblem{Kind: SetupNotInvited, Summary: "Service account has no access to the app in Play Console.", Steps: []string{
+		"Open Play Console > Users and permissions and click Invite new users.",
+		"Enter the service account email, client_email of its JSON key.",
+		"Grant it access to the app with Release permissions for the tracks published to, then click Invite user.",
+		"Check the app exists with the package name given, changes can take up to a day to apply.",
+	}}
+}
+
+// hasReason checks if error has any of given reasons, in its errors or details
+func hasReason(ge *googleapi.Error, reasons ...string) bool {
+	for _, r := range reasons {
+		for _, e := range ge.Errors {
+			if e.Reason == r {
+				return true
+			}
+		}
+		for _, d := range ge.Details {
+			if m, ok := d.(map[string]any); ok && m["reason"] == r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ServiceAccountEmail returns client_email of service account JSON key
+func ServiceAccountEmail(credentials []byte) (string, error) {
+	var key struct {
+		Email string `json:"client_email"`
+	}
+	if err := json.Unmarshal(credentials, &key); err != nil {
+		return "", fmt.Errorf("failed parsing service account credentials: %w", err)
+	}
+	if key.Email == "" {
+		return "", fmt.Errorf("service account credentials have no client_email")
+	}
+	return key.Email, nil
+}
+
+// CheckSetup confirms credentials can manage the app by opening an edit and deleting it right away,
+// see Diagnose for setup problem of the error
+func CheckSetup(ctx context.Context, gs IGService, packageName string) error {
+	if gs == nil {
+		return errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return err
+	}
+	edit, err := createEdit(ctx, gs, name)
+	if err != nil {
+		return accessError(name, err)
+	}
+	return edit.Delete(ctx)
+}
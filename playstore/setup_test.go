@@ -0,0 +1,97 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+func TestDiagnose(t *testing.T) {
+
+	t.Run("should tell setup problems apart", func(t *testing.T) {
+		cases := map[string]error{
+			SetupAPIDisabled:        &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "accessNotConfigured"}}},
+			SetupMissingScope:       &googleapi.Error{Code: http.StatusForbidden, Details: []any{map[string]any{"reason": "ACCESS_TOKEN_SCOPE_INSUFFICIENT"}}},
+			SetupNotInvited:         &googleapi.Error{Code: http.StatusUnauthorized, Message: "The current user has insufficient permissions to perform the requested operation."},
+			SetupInvalidCredentials: fmt.Errorf("failed to create edit: %w", &oauth2.RetrieveError{ErrorCode: "invalid_grant"}),
+		}
+		for kind, err := range cases {
+			// Act
+			problem := Diagnose(apiError("create edit", err))
+
+			// Assert
+			if problem == nil || problem.Kind != kind || len(problem.Steps) == 0 {
+				t.Errorf("want '%s' problem with steps for '%v', got %+v", kind, err, problem)
+			}
+		}
+	})
+
+	t.Run("should not diagnose errors other than setup ones", func(t *testing.T) {
+		for _, err := range []error{errors.New("failed"), &googleapi.Error{Code: http.StatusConflict}, &googleapi.Error{Code: http.StatusForbidden}} {
+			// Act
+			problem := Diagnose(err)
+
+			// Assert
+			if problem != nil {
+				t.Errorf("want no problem for '%v', got %+v", err, problem)
+			}
+		}
+	})
+}
+
+func TestCheckSetup(t *testing.T) {
+
+	t.Run("should open and delete edit", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		err := CheckSetup(context.Background(), gs, "com.test.app")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.createEditCount != 1 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit opened and deleted, got %d creates %d deletes", gs.createEditCount, gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should diagnose app not found as service account not invited", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{createEditErrors: map[string]error{"com.test.app": &googleapi.Error{Code: http.StatusNotFound, Message: "Package not found"}}}
+
+		// Act
+		err := CheckSetup(context.Background(), gs, "com.test.app")
+
+		// Assert
+		if !errors.Is(err, ErrNoAppAccess) {
+			t.Fatalf("want ErrNoAppAccess, got: %v", err)
+		}
+		if problem := Diagnose(err); problem == nil || problem.Kind != SetupNotInvited {
+			t.Errorf("want not invited problem, got %+v", problem)
+		}
+	})
+}
+
+func TestServiceAccountEmail(t *testing.T) {
+
+	t.Run("should read client email of key", func(t *testing.T) {
+		// Act
+		email, err := ServiceAccountEmail([]byte(`{"type": "service_account", "client_email": "ci@project.iam.gserviceaccount.com"}`))
+		_, missingErr := ServiceAccountEmail([]byte(`{"type": "authorized_user"}`))
+
+		// Assert
+		if err != nil || email != "ci@project.iam.gserviceaccount.com" {
+			t.Errorf("want ci@project.iam.gserviceaccount.com, got '%s': %v", email, err)
+		}
+		if missingErr == nil {
+			t.Error("want error for credentials without client_email")
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package playstore
+
+import (
+	"archive/zip"
+	"bytes"
+	encbin "encoding/binary"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// https://developer.android.com/studio/publish/app-signing#debug-mode
+	debugCertCommonName = "Android Debug"
+
+	// https://source.android.com/docs/security/features/apksigning/v2#apk-signing-block
+	apkSigBlockMagic    = "APK Sig Block 42"
+	zipEocdSignature    = 0x06054b50
+	zipEocdMinSize      = 22
+	zipEocdMaxComment   = 0xffff
+	apkSigBlockMaxBytes = 16 << 20
+)
+
+// debugCertSubject DER encoded commonName attribute value of default debug keystore certificate,
+// matched after the commonName OID 2.5.4.3 and a string tag
+var debugCertSubject = append([]byte{byte(len(debugCertCommonName))}, debugCertCommonName...)
+
+// isDebugSigned checks if bundle or apk is signed with the default Android debug keystore certificate.
+// Both jar signature (META-INF) and apk signature scheme v2+ blocks are checked.
+// Files which can't be read as zip are reported as not debug signed, Play rejects these anyway.
+func isDebugSigned(fs afero.Fs, filePath string) (bool, error) {
+	f, err := fs.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	s, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	zr, err := zip.NewReader(f, s.Size())
+	if err != nil {
+		return false, nil
+	}
+
+	for _, zf := range zr.File {
+		if !isJarSignatureBlock(zf.Name) {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return false, fmt.Errorf("failed reading '%s' signature '%s': %w", filePath, zf.Name, err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return false, fmt.Errorf("failed reading '%s' signature '%s': %w", filePath, zf.Name, err)
+		}
+		if containsDebugCert(b) {
+			return true, nil
+		}
+	}
+
+	block, err := apkSigningBlock(f, s.Size())
+	if err != nil {
+		return false, fmt.Errorf("failed reading '%s' signing block: %w", filePath, err)
+	}
+	return containsDebugCert(block), nil
+}
+
+// isJarSignatureBlock checks if zip entry is a jar signature block e.g. META-INF/CERT.RSA
+func isJarSignatureBlock(name string) bool {
+	if path.Dir(name) != "META-INF" {
+		return false
+	}
+	switch strings.ToUpper(path.Ext(name)) {
+	case ".RSA", ".DSA", ".EC":
+		return true
+	}
+	return false
+}
+
+// containsDebugCert looks for debug certificate commonName in DER encoded data
+func containsDebugCert(b []byte) bool {
+	oid := []byte{0x55, 0x04, 0x03}
+	for off := 0; ; {
+		i := bytes.Index(b[off:], oid)
+		if i < 0 {
+			return false
+		}
+		off += i + len(oid)
+		rest := b[off:]
+		// PrintableString or UTF8String tag followed by length and value
+		if len(rest) > 0 && (rest[0] == 0x13 || rest[0] == 0x0c) && bytes.HasPrefix(rest[1:], debugCertSubject) {
+			return true
+		}
+	}
+}
+
+// apkSigningBlock returns apk signing block placed right before zip central directory, nil if there is none
+func apkSigningBlock(r io.ReaderAt, size int64) ([]byte, error) {
+	tail := int64(zipEocdMinSize + zipEocdMaxComment)
+	if tail > size {
+		tail = size
+	}
+	buf := make([]byte, tail)
+	if _, err := r.ReadAt(buf, size-tail); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	eocd := -1
+	for i := len(buf) - zipEocdMinSize; i >= 0; i-- {
+		if encbin.LittleEndian.Uint32(buf[i:]) == zipEocdSignature {
+			eocd = i
+			break
+		}
+	}
+	if eocd < 0 {
+		return nil, nil
+	}
+	cdOffset := int64(encbin.LittleEndian.Uint32(buf[eocd+16:]))
+
+	// block ends with uint64 size followed by 16 byte magic
+	if cdOffset < 24 {
+		return nil, nil
+	}
+	footer := make([]byte, 24)
+	if _, err := r.ReadAt(footer, cdOffset-24); err != nil {
+		return nil, err
+	}
+	if string(footer[8:]) != apkSigBlockMagic {
+		return nil, nil
+	}
+	blockSize := int64(encbin.LittleEndian.Uint64(footer))
+	if blockSize <= 0 || blockSize > apkSigBlockMaxBytes || blockSize+8 > cdOffset {
+		return nil, fmt.Errorf("invalid apk signing block size %d", blockSize)
+	}
+
+	block := make([]byte, blockSize)
+	if _, err := r.ReadAt(block, cdOffset-blockSize); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
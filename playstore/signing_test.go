@@ -0,0 +1,134 @@
+package playstore
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	encbin "encoding/binary"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestIsDebugSigned(t *testing.T) {
+
+	t.Run("should detect debug certificate in jar signature", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createSignedTestBinary(t, fs, "debug.aab", debugCertCommonName)
+
+		// Act
+		debug, err := isDebugSigned(fs, "debug.aab")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !debug {
+			t.Error("want debug signed, got release")
+		}
+	})
+
+	t.Run("should accept release certificate", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createSignedTestBinary(t, fs, "release.aab", "Sample Release")
+
+		// Act
+		debug, err := isDebugSigned(fs, "release.aab")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if debug {
+			t.Error("want release signed, got debug")
+		}
+	})
+
+	t.Run("should detect debug certificate in apk signing block", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createV2SignedTestBinary(t, fs, "debug.apk", debugCertCommonName)
+
+		// Act
+		debug, err := isDebugSigned(fs, "debug.apk")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !debug {
+			t.Error("want debug signed, got release")
+		}
+	})
+
+	t.Run("should refuse to publish debug signed binary", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createSignedTestBinary(t, fs, "debug.aab", debugCertCommonName)
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("debug.aab")}, false, false)
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
+
+// createV2SignedTestBinary creates zip with apk signing block holding DER encoded commonName of the certificate
+func createV2SignedTestBinary(t testing.TB, fs afero.Fs, file, commonName string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("classes.dex")
+	w.Write([]byte("dex"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	eocd := bytes.LastIndex(data, []byte{0x50, 0x4b, 0x05, 0x06})
+	cd := encbin.LittleEndian.Uint32(data[eocd+16:])
+
+	pairs := append([]byte{0x06, 0x03, 0x55, 0x04, 0x03, 0x13, byte(len(commonName))}, commonName...)
+	size := uint64(len(pairs) + 8 + 16)
+	block := encbin.LittleEndian.AppendUint64(nil, size)
+	block = append(block, pairs...)
+	block = encbin.LittleEndian.AppendUint64(block, size)
+	block = append(block, apkSigBlockMagic...)
+
+	apk := append([]byte{}, data[:cd]...)
+	apk = append(apk, block...)
+	apk = append(apk, data[cd:]...)
+	encbin.LittleEndian.PutUint32(apk[eocd+len(block)+16:], cd+uint32(len(block)))
+
+	if err := afero.WriteFile(fs, file, apk, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createSignedTestBinary creates zip with jar signature block holding DER encoded commonName of the certificate
+func createSignedTestBinary(t testing.TB, fs afero.Fs, file, commonName string) {
+	t.Helper()
+
+	f, err := fs.Create(file)
+	if err != nil {
+		t.Fatalf("failed creating '%s' test binary: %s", file, err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("META-INF/CERT.RSA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cn := append([]byte{0x30, 0x00, 0x06, 0x03, 0x55, 0x04, 0x03, 0x0c, byte(len(commonName))}, commonName...)
+	w.Write(append([]byte{0x30, 0x82, 0x01, 0x00}, cn...))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,46 @@
+package playstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// size unit multipliers, longest suffix first so 'MB' isn't read as 'B'
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"KB", 1 << 10},
+	{"MB", 1 << 20},
+	{"GB", 1 << 30},
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+	{"B", 1},
+}
+
+// ParseSize reads human readable size e.g. '200MB', '1.5G' or '1024' into bytes
+func ParseSize(s string) (int64, error) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(v, u.suffix) {
+			v = strings.TrimSpace(strings.TrimSuffix(v, u.suffix))
+			multiplier = u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("size '%s' is not valid, expected e.g. '200MB'", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// WithMaxSize fails Publish if any binary is larger than given bytes, regardless of Play or profile limits
+func WithMaxSize(bytes int64) Option {
+	return func(p *publish) {
+		p.maxSize = bytes
+	}
+}
@@ -0,0 +1,40 @@
+package playstore
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+
+	t.Run("should parse sizes with units", func(t *testing.T) {
+		cases := map[string]int64{
+			"1024":  1024,
+			"200MB": 200 << 20,
+			"1.5g":  3 << 29,
+			"10 K":  10 << 10,
+			"512B":  512,
+		}
+		for in, want := range cases {
+			// Act
+			got, err := ParseSize(in)
+
+			// Assert
+			if err != nil {
+				t.Errorf("want no error for '%s', got: %v", in, err)
+			}
+			if got != want {
+				t.Errorf("want %d bytes for '%s', got %d", want, in, got)
+			}
+		}
+	})
+
+	t.Run("should reject invalid size", func(t *testing.T) {
+		for _, in := range []string{"", "MB", "-1MB", "ten"} {
+			// Act
+			_, err := ParseSize(in)
+
+			// Assert
+			if err == nil {
+				t.Errorf("want error for '%s', got nil", in)
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package playstore
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// kinds of soft failures, non fatal events publish carried on after
+const (
+	SoftFailureWarning      = "warning"
+	SoftFailureRetry        = "retry"
+	SoftFailureSkippedCheck = "skipped-check"
+	SoftFailureFallback     = "fallback"
+	// optional feature skipped as its Google API isn't enabled or credentials lack its scope
+	SoftFailureSkippedFeature = "skipped-feature"
+)
+
+// SoftFailure non fatal event of a publish, e.g. retried API call or check skipped as it couldn't run.
+// A growing number of them points to degradation before it turns into a hard failure.
+type SoftFailure struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// softFailuresMu guards soft failures of every publish, recorded by parallel uploads
+var softFailuresMu sync.Mutex
+
+// noteSoftFailure records soft failure of kind
+func (p *publish) noteSoftFailure(kind, format string, v ...any) {
+	softFailuresMu.Lock()
+	defer softFailuresMu.Unlock()
+	p.softFailures = append(p.softFailures, SoftFailure{Kind: kind, Message: fmt.Sprintf(format, v...)})
+}
+
+// SoftFailures returns soft failures of publish so far, in order they happened
+func (p *publish) SoftFailures() []SoftFailure {
+	softFailuresMu.Lock()
+	defer softFailuresMu.Unlock()
+	return append([]SoftFailure(nil), p.softFailures...)
+}
+
+// fallbackf logs warning about publish falling back to another way of doing something
+func (p *publish) fallbackf(format string, v ...any) {
+	p.log().Warnf(format, v...)
+	p.noteSoftFailure(SoftFailureFallback, format, v...)
+}
+
+// skippedCheckf logs check that couldn't run, as warning or debug message when skipped check is harmless
+func (p *publish) skippedCheckf(warn bool, format string, v ...any) {
+	if warn {
+		p.log().Warnf(format, v...)
+	} else {
+		p.Debugf(format, v...)
+	}
+	p.noteSoftFailure(SoftFailureSkippedCheck, format, v...)
+}
+
+// retryCounter service counting calls it retried and time it waited for them, see retryingService
+type retryCounter interface {
+	retried() int64
+	backedOff() time.Duration
+}
+
+// countRetries records retries of gs made from now until returned func is called. Retries of other
+// publishes sharing gs at the same time are counted too.
+func (p *publish) countRetries(gs IGService) func() {
+	rc, ok := gs.(retryCounter)
+	if !ok {
+		return func() {}
+	}
+	before, backoffBefore := rc.retried(), rc.backedOff()
+	return func() {
+		if n := rc.retried() - before; n > 0 {
+			backoff := (rc.backedOff() - backoffBefore).Round(time.Millisecond)
+			p.noteSoftFailure(SoftFailureRetry, "Google API calls retried %d times after transient errors, %s spent in backoff", n, backoff)
+		}
+	}
+}
+
+// printSoftFailures prints all soft failures of publish as one block, nothing when there were none
+func (p *publish) printSoftFailures() {
+	failures := p.SoftFailures()
+	if len(failures) == 0 {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Publish finished with %d soft failures:", len(failures))
+	for _, f := range failures {
+		fmt.Fprintf(&b, "\n  %-13s %s", f.Kind, f.Message)
+	}
+	p.Infof("%s", b.String())
+}
@@ -0,0 +1,76 @@
+package playstore
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+func TestSoftFailures(t *testing.T) {
+
+	t.Run("should summarize retries and fallbacks once upload is done", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.aab", 10)
+		l := &recordingLogger{}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("app.aab")}, false, false,
+			WithLogger(l), WithNotSentForReviewFallback())
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{commitErrors: []error{
+			&googleapi.Error{Code: http.StatusTooManyRequests},
+			&googleapi.Error{Code: http.StatusBadRequest, Message: "Please set the query parameter changesNotSentForReview to true."},
+		}}
+		rs, _ := newTestRetryingService(gs, RetryPolicy{Retries: 1})
+
+		// Act
+		results, err := publish.UploadFiles(context.Background(), rs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		kinds := make([]string, 0)
+		for _, f := range publish.SoftFailures() {
+			kinds = append(kinds, f.Kind)
+		}
+		if strings.Join(kinds, ",") != SoftFailureFallback+","+SoftFailureRetry {
+			t.Errorf("want fallback and retry soft failures, got %v", publish.SoftFailures())
+		}
+		if !l.has("INFO Publish finished with 2 soft failures:") {
+			t.Errorf("want soft failures summary logged, got %v", l.lines)
+		}
+		if r := publish.Receipt(results); len(r.SoftFailures) != 2 {
+			t.Errorf("want soft failures in receipt, got %v", r.SoftFailures)
+		}
+	})
+
+	t.Run("should not print summary without soft failures", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "app.aab", 10)
+		l := &recordingLogger{}
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("app.aab")}, false, false, WithLogger(l))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		_, err = publish.UploadFiles(context.Background(), &mockGService{})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if l.has("INFO Publish finished with") || len(publish.SoftFailures()) != 0 {
+			t.Errorf("want no soft failures, got %v", l.lines)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package playstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// states of upload status file
+const (
+	StatusUploading = "uploading"
+	StatusFinished  = "finished"
+	StatusFailed    = "failed"
+)
+
+// UploadStatus live publish progress written by WithStatusFile for dashboards and CI plugins to display
+type UploadStatus struct {
+	RunID       string `json:"runId"`
+	PackageName string `json:"packageName"`
+	State       string `json:"state"`
+	// file being uploaded, the last reported one with parallel uploads
+	File    string  `json:"file,omitempty"`
+	Sent    int64   `json:"sent"`
+	Total   int64   `json:"total"`
+	Percent float64 `json:"percent"`
+	// estimated time until file upload finishes, from its average rate so far
+	ETASeconds int64     `json:"etaSeconds,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// WithStatusFile keeps upload progress of UploadFiles in given JSON file, see UploadStatus. File is replaced
+// as a whole on every progress report, so readers never see partial content. It's written to output fs and
+// kept once publish is done, with its final state.
+func WithStatusFile(path string) Option {
+	return func(p *publish) {
+		p.statusFile = &statusFile{path: path, started: make(map[string]time.Time)}
+	}
+}
+
+// statusFile upload status shared by parallel uploads
+type statusFile struct {
+	mu      sync.Mutex
+	path    string
+	status  UploadStatus
+	started map[string]time.Time
+}
+
+// reportStatus records upload progress of a file to status file
+func (p *publish) reportStatus(filePath string, sent, total int64) {
+	sf := p.statusFile
+	if sf == nil {
+		return
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	now := time.Now()
+	started, ok := sf.started[filePath]
+	if !ok {
+		started = now
+		sf.started[filePath] = now
+	}
+	s := UploadStatus{RunID: p.runID, PackageName: p.packageName, State: StatusUploading, File: filePath, Sent: sent, Total: total, UpdatedAt: now}
+	if total > 0 {
+		s.Percent = float64(sent*10000/total) / 100
+	}
+	if elapsed := now.Sub(started); sent > 0 && sent < total && elapsed > 0 {
+		s.ETASeconds = int64(elapsed.Seconds() * float64(total-sent) / float64(sent))
+	}
+	p.writeStatus(s)
+}
+
+// finishStatus records final state of upload to status file
+func (p *publish) finishStatus(err error) {
+	sf := p.statusFile
+	if sf == nil {
+		return
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	s := sf.status
+	s.RunID, s.PackageName, s.State, s.ETASeconds, s.UpdatedAt = p.runID, p.packageName, StatusFinished, 0, time.Now()
+	if err != nil {
+		s.State, s.Error = StatusFailed, err.Error()
+	}
+	p.writeStatus(s)
+}
+
+// writeStatus replaces status file content through a temporary file, failures are only logged as
+// status is informational. Callers hold statusFile lock.
+func (p *publish) writeStatus(s UploadStatus) {
+	sf := p.statusFile
+	sf.status = s
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err == nil {
+		err = writeFileReplacing(p.outFs(), sf.path, append(b, '\n'))
+	}
+	if err != nil {
+		p.Debugf("failed writing upload status '%s': %v", sf.path, err)
+	}
+}
+
+// writeFileReplacing writes file next to path and renames it over path
+func writeFileReplacing(fs afero.Fs, path string, b []byte) error {
+	tmp := path + ".tmp"
+	if err := afero.WriteFile(fs, tmp, b, 0644); err != nil {
+		return err
+	}
+	if err := fs.Rename(tmp, path); err != nil {
+		fs.Remove(tmp)
+		return fmt.Errorf("failed replacing '%s': %w", path, err)
+	}
+	return nil
+}
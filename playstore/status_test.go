@@ -0,0 +1,103 @@
+package playstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+func TestStatusFile(t *testing.T) {
+
+	readStatus := func(t *testing.T, fs afero.Fs) UploadStatus {
+		t.Helper()
+		b, err := afero.ReadFile(fs, "status.json")
+		if err != nil {
+			t.Fatalf("want status file, got: %v", err)
+		}
+		var s UploadStatus
+		if err := json.Unmarshal(b, &s); err != nil {
+			t.Fatalf("want valid status file, got: %v", err)
+		}
+		return s
+	}
+
+	t.Run("should report progress with percentage and ETA", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		p := &publish{fs: fs, packageName: "com.test.app", runID: "run-1"}
+		WithStatusFile("status.json")(p)
+		p.statusFile.started["app.aab"] = time.Now().Add(-10 * time.Second)
+
+		// Act
+		p.reportStatus("app.aab", 25, 100)
+
+		// Assert
+		s := readStatus(t, fs)
+		if s.State != StatusUploading || s.File != "app.aab" || s.Sent != 25 || s.Total != 100 || s.Percent != 25 {
+			t.Errorf("want app.aab uploading at 25%%, got %+v", s)
+		}
+		if s.ETASeconds < 29 || s.ETASeconds > 31 {
+			t.Errorf("want about 30s ETA, got %ds", s.ETASeconds)
+		}
+		if s.RunID != "run-1" || s.PackageName != "com.test.app" {
+			t.Errorf("want run-1 of com.test.app, got %+v", s)
+		}
+		if ok, _ := afero.Exists(fs, "status.json.tmp"); ok {
+			t.Error("want no temporary status file left")
+		}
+	})
+
+	t.Run("should keep finished state once publish is done", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 20)
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false,
+			WithStatusFile("status.json"), WithProgressFunc(func(string, int64, int64) {}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		if _, err := publish.UploadFiles(context.Background(), &mockGService{}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		s := readStatus(t, fs)
+		if s.State != StatusFinished || s.File != "test.aab" || s.Sent != 20 || s.Percent != 100 || s.Error != "" {
+			t.Errorf("want finished test.aab upload, got %+v", s)
+		}
+	})
+
+	t.Run("should record failure once publish fails", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createTestFile(t, fs, "test.aab", 20)
+		publish, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{Binary("test.aab")}, false, false,
+			WithStatusFile("status.json"), WithProgressFunc(func(string, int64, int64) {}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs := &mockGService{commitErrors: []error{&googleapi.Error{Code: 400, Message: "refused"}}}
+
+		// Act
+		_, err = publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		var ae *AbortError
+		if !errors.As(err, &ae) {
+			t.Fatalf("want AbortError, got: %v", err)
+		}
+		s := readStatus(t, fs)
+		if s.State != StatusFailed || s.Error != err.Error() {
+			t.Errorf("want failed state with '%v', got %+v", err, s)
+		}
+	})
+}
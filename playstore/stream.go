@@ -0,0 +1,115 @@
+package playstore
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// streamSource binary content uploaded straight from a reader or URL, without a local copy. Checks needing
+// the whole file before upload, e.g. manifest and signing ones, are skipped for it and its sha256 is
+// compared with the one Play reports once uploaded.
+type streamSource struct {
+	reader io.Reader
+	url    string
+	size   int64 // -1 if not known before upload
+	mu     sync.Mutex
+	read   bool
+}
+
+// BinaryFromReader uploads binary content of r, e.g. an object stream of artifact storage, named name in
+// logs and results. Size is the content length, -1 if not known. Content can be read only once, so it's
+// neither retried on integrity mismatch nor resumed.
+func BinaryFromReader(name string, r io.Reader, size int64) binary {
+	return binary{filePath: name, stream: &streamSource{reader: r, size: size}}
+}
+
+// BinaryFromURL streams binary from https, http or gs URL straight to Play, fetched with client of
+// WithDownloadClient. Expected content sha256 can be given as '#sha256=<hex>', same as remote artifacts
+// Publish downloads.
+func BinaryFromURL(url string) binary {
+	return binary{filePath: url, stream: &streamSource{url: url, size: -1}}
+}
+
+// Streamed streams remote binary straight to Play like BinaryFromURL, keeping its mapping and notes.
+// Local binaries are left as they are.
+func Streamed(b binary) binary {
+	if isRemote(b.filePath) {
+		b.stream = &streamSource{url: b.filePath, size: -1}
+	}
+	return b
+}
+
+// openStream opens content of streamed binary, returning its size or -1 along with sha256 it must have
+func (p *publish) openStream(ctx context.Context, f binary) (io.ReadCloser, int64, string, error) {
+	s := f.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reader != nil {
+		if s.read {
+			return nil, 0, "", fmt.Errorf("binary '%s' content was read already, it can't be uploaded again", f.filePath)
+		}
+		s.read = true
+		return io.NopCloser(s.reader), s.size, "", nil
+	}
+	a, err := parseRemote(s.url)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url, nil)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	res, err := p.httpClient().Do(req)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("failed fetching '%s': %w", f.filePath, err)
+	}
+	if res.StatusCode >= 300 {
+		res.Body.Close()
+		return nil, 0, "", fmt.Errorf("failed fetching '%s': %s", f.filePath, res.Status)
+	}
+	return res.Body, res.ContentLength, a.sha256, nil
+}
+
+// uploadStream uploads streamed binary, hashing content as it's sent
+func (p *publish) uploadStream(ctx context.Context, edit *Edit, f binary, isApk bool) (int64, Hashes, error) {
+	p.fileDebugf(f.filePath, "streaming %s", f.filePath)
+	r, size, want, err := p.openStream(ctx, f)
+	if err != nil {
+		return -1, Hashes{}, err
+	}
+	defer r.Close()
+
+	uplF := edit.UploadBundle
+	if isApk {
+		uplF = edit.UploadApk
+	}
+	h256, h1 := sha256.New(), sha1.New()
+	counter := &countingReader{r: io.TeeReader(r, io.MultiWriter(h256, h1))}
+	total := size
+	if total < 0 {
+		total = 0
+	}
+	v, remote, err := uplF(ctx, p.progressReader(f.filePath, counter, total))
+	if err != nil {
+		return -1, Hashes{}, err
+	}
+	local := Hashes{Sha256: hex.EncodeToString(h256.Sum(nil)), Sha1: hex.EncodeToString(h1.Sum(nil))}
+	sent := counter.count()
+	if size >= 0 && sent != size {
+		return -1, Hashes{}, fmt.Errorf("stream of '%s' incomplete, sent %d of %d bytes", f.filePath, sent, size)
+	}
+	if want != "" && local.Sha256 != want {
+		return -1, Hashes{}, fmt.Errorf("streamed '%s' sha256 '%s' doesn't match expected '%s'", f.filePath, local.Sha256, want)
+	}
+	if remote != local.Sha256 {
+		return -1, Hashes{}, &IntegrityError{Path: f.filePath, LocalSha256: local.Sha256, RemoteSha256: remote, Size: sent, BytesSent: sent, Attempts: 1}
+	}
+	p.fileDebugf(f.filePath, "File successfully streamed with appVersion: '%d' and sha256 '%s'", v, remote)
+	return v, local, nil
+}
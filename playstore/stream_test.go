@@ -0,0 +1,103 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestStreamedBinaries(t *testing.T) {
+
+	content := []byte("streamed bundle content")
+	digest := sha256.Sum256(content)
+
+	publishStreamed := func(t *testing.T, b binary) *publish {
+		t.Helper()
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		p, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{b}, false, false,
+			WithProgressFunc(func(string, int64, int64) {}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	t.Run("should upload binary from reader without local file", func(t *testing.T) {
+		// Arrange
+		p := publishStreamed(t, BinaryFromReader("app.aab", bytes.NewReader(content), int64(len(content))))
+		gs := &mockGService{AppVersionCode: 7}
+
+		// Act
+		results, err := p.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(results) != 1 || results[0].VersionCode != 7 || results[0].Sha256 != hex.EncodeToString(digest[:]) {
+			t.Errorf("want app.aab uploaded as 7 with content sha256, got %+v", results)
+		}
+		if gs.uploadBundleCallCount != 1 || gs.commitEditCount != 1 {
+			t.Errorf("want 1 upload committed, got %d uploads %d commits", gs.uploadBundleCallCount, gs.commitEditCount)
+		}
+	})
+
+	t.Run("should stream binary from URL verifying its sha256", func(t *testing.T) {
+		// Arrange
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(content)
+		}))
+		defer srv.Close()
+		ok := publishStreamed(t, BinaryFromURL(srv.URL+"/app.aab#sha256="+hex.EncodeToString(digest[:])))
+		mismatch := publishStreamed(t, BinaryFromURL(srv.URL+"/app.aab#sha256=00ff"))
+
+		// Act
+		_, err := ok.UploadFiles(context.Background(), &mockGService{AppVersionCode: 7})
+		_, mismatchErr := mismatch.UploadFiles(context.Background(), &mockGService{AppVersionCode: 7})
+
+		// Assert
+		if err != nil {
+			t.Errorf("want no error, got: %v", err)
+		}
+		if mismatchErr == nil || !strings.Contains(mismatchErr.Error(), "doesn't match expected '00ff'") {
+			t.Errorf("want sha256 mismatch, got: %v", mismatchErr)
+		}
+	})
+
+	t.Run("should refuse reading reader twice", func(t *testing.T) {
+		// Arrange
+		p := &publish{}
+		b := BinaryFromReader("app.aab", bytes.NewReader(content), -1)
+		p.openStream(context.Background(), b)
+
+		// Act
+		_, _, _, err := p.openStream(context.Background(), b)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "read already") {
+			t.Errorf("want error for second read, got: %v", err)
+		}
+	})
+
+	t.Run("should refuse streaming local path", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", TrackInternal, "auth.json", []binary{BinaryFromURL("app.aab")}, false, false)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "must be https, http or gs URL") {
+			t.Errorf("want error for local path, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package playstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/afero"
+)
+
+// zip local file header, native debug symbols are uploaded as zip of .so files
+var zipMagic = []byte{'P', 'K', 0x03, 0x04}
+
+// SymbolsReport deobfuscation files uploaded for an appVersion
+type SymbolsReport struct {
+	VersionCode   int64  `json:"versionCode"`
+	Mapping       string `json:"mapping,omitempty"`
+	NativeSymbols string `json:"nativeSymbols,omitempty"`
+	EditID        string `json:"editId"`
+	DryRun        bool   `json:"dryRun,omitempty"`
+}
+
+/**
+ * UploadSymbols uploads proguard mapping and/or native debug symbols of appVersion already on Play, and
+ * nothing else, e.g. to backfill symbols of a released version
+ *
+ * mappingPath - proguard mapping, plain text or gzipped, empty for none
+ * symbolsPath - zip of native debug symbols e.g. native-debug-symbols.zip, empty for none
+ * opts - WithNotSentForReviewFallback() and WithManagedPublishing(...) apply to commit,
+ *        DryRun() validates uploaded files and deletes the edit instead of committing
+ */
+func UploadSymbols(ctx context.Context, gs IGService, fs afero.Fs, packageName string, versionCode int64, mappingPath, symbolsPath string, opts ...Option) (*SymbolsReport, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+
+	p := &publish{fs: fs}
+	for _, o := range opts {
+		o(p)
+	}
+	name, err := validatePackageName(packageName, p.anyPackageName)
+	if err != nil {
+		return nil, err
+	}
+	p.packageName = name
+	if mappingPath == "" && symbolsPath == "" {
+		return nil, errors.New("mapping or native symbols file to upload is required")
+	}
+	if mappingPath != "" && !p.fileExits(mappingPath) {
+		return nil, fmt.Errorf("mappings file '%s' does not exist", mappingPath)
+	}
+	if symbolsPath != "" {
+		if err := p.checkNativeSymbols(symbolsPath); err != nil {
+			return nil, err
+		}
+	}
+	defer func() {
+		if p.ws != nil {
+			p.ws.cleanup()
+		}
+	}()
+
+	edit, err := createEdit(ctx, gs, name)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := gs.versionHashes(ctx, name, edit.ID())
+	if err != nil {
+		edit.Abort(ctx)
+		return nil, fmt.Errorf("failed reading uploaded binaries: %w", err)
+	}
+	if _, ok := hashes[versionCode]; !ok {
+		edit.Abort(ctx)
+		return nil, fmt.Errorf("appVersion %d is not on Play, upload its binary first", versionCode)
+	}
+
+	report := &SymbolsReport{VersionCode: versionCode, EditID: edit.ID(), DryRun: p.dryRun}
+	if mappingPath != "" {
+		if err := p.uploadSymbolsFile(mappingPath, p.openMapping, func(r io.Reader) error {
+			return edit.UploadMapping(ctx, r, versionCode)
+		}); err != nil {
+			edit.Abort(ctx)
+			return nil, fmt.Errorf("failed uploading mapping '%s': %w", mappingPath, err)
+		}
+		report.Mapping = mappingPath
+	}
+	if symbolsPath != "" {
+		if err := p.uploadSymbolsFile(symbolsPath, p.fs.Open, func(r io.Reader) error {
+			return edit.UploadNativeSymbols(ctx, r, versionCode)
+		}); err != nil {
+			edit.Abort(ctx)
+			return nil, fmt.Errorf("failed uploading native symbols '%s': %w", symbolsPath, err)
+		}
+		report.NativeSymbols = symbolsPath
+	}
+
+	if err := edit.Validate(ctx); err != nil {
+		edit.Abort(ctx)
+		return nil, err
+	}
+	if p.dryRun {
+		if err := edit.Delete(ctx); err != nil {
+			return nil, fmt.Errorf("dry run validated, but failed deleting edit '%s': %w", edit.ID(), err)
+		}
+		return report, nil
+	}
+	if err := p.commitWith(func(changesNotSentForReview bool) error { return edit.Commit(ctx, changesNotSentForReview) }); err != nil {
+		edit.Abort(ctx)
+		return nil, err
+	}
+	return report, nil
+}
+
+// checkNativeSymbols checks native symbols file exists and is a zip, as Play expects
+func (p *publish) checkNativeSymbols(filePath string) error {
+	f, err := p.fs.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("native symbols file '%s' does not exist", filePath)
+	}
+	defer f.Close()
+	head := make([]byte, len(zipMagic))
+	if _, err := io.ReadFull(f, head); err != nil || !bytes.Equal(head, zipMagic) {
+		return fmt.Errorf("native symbols file '%s' is not a zip of debug symbols", filePath)
+	}
+	return nil
+}
+
+// uploadSymbolsFile opens file and uploads it with its progress reported
+func (p *publish) uploadSymbolsFile(filePath string, open func(string) (afero.File, error), upload func(r io.Reader) error) error {
+	f, err := open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return upload(p.progressReader(filePath, f, info.Size()))
+}
@@ -0,0 +1,71 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestUploadSymbols(t *testing.T) {
+
+	t.Run("should upload mapping and native symbols of existing appVersion", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestFile(t, fs, "mapping.txt", 10)
+		afero.WriteFile(fs, "symbols.zip", append([]byte("PK\x03\x04"), make([]byte, 10)...), 0644)
+		gs := &mockGService{hashes: map[int64]Hashes{12: {Sha256: "a"}}}
+
+		// Act
+		report, err := UploadSymbols(context.Background(), gs, fs, "com.test.app", 12, "mapping.txt", "symbols.zip")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(gs.mappingVersionCodes, []int64{12}) || !reflect.DeepEqual(gs.symbolsVersionCodes, []int64{12}) {
+			t.Errorf("want mapping and symbols uploaded for 12, got %v and %v", gs.mappingVersionCodes, gs.symbolsVersionCodes)
+		}
+		if gs.commitEditCount != 1 || report.Mapping != "mapping.txt" || report.NativeSymbols != "symbols.zip" {
+			t.Errorf("want edit committed with both files reported, got %d commits, %+v", gs.commitEditCount, report)
+		}
+		if len(gs.releases) != 0 {
+			t.Errorf("want no release created, got %v", gs.releases)
+		}
+	})
+
+	t.Run("should refuse appVersion missing on Play", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestFile(t, fs, "mapping.txt", 10)
+		gs := &mockGService{hashes: map[int64]Hashes{11: {Sha256: "a"}}}
+
+		// Act
+		_, err := UploadSymbols(context.Background(), gs, fs, "com.test.app", 12, "mapping.txt", "")
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "appVersion 12") {
+			t.Errorf("want missing appVersion error, got: %v", err)
+		}
+		if gs.deleteEditCount != 1 || len(gs.mappingVersionCodes) != 0 {
+			t.Errorf("want edit deleted without uploads, got %d deletes and %v", gs.deleteEditCount, gs.mappingVersionCodes)
+		}
+	})
+
+	t.Run("should refuse native symbols that are not zip", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		createTestFile(t, fs, "symbols.zip", 10)
+		gs := &mockGService{}
+
+		// Act
+		_, err := UploadSymbols(context.Background(), gs, fs, "com.test.app", 12, "", "symbols.zip")
+
+		// Assert
+		if err == nil || gs.createEditCount != 0 {
+			t.Errorf("want error before edit is created, got %d edits: %v", gs.createEditCount, err)
+		}
+	})
+}
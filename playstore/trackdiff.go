@@ -0,0 +1,151 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+// TrackRelease latest release of a compared track, the one Promote would promote
+type TrackRelease struct {
+	Track string `json:"track"`
+	// nil for track without releases
+	Release      *ReleaseInfo      `json:"release,omitempty"`
+	ReleaseNotes map[string]string `json:"releaseNotes,omitempty"`
+}
+
+// ReleaseNotesDiff release notes of a locale differing between tracks, empty for locale without notes
+type ReleaseNotesDiff struct {
+	Locale string `json:"locale"`
+	A      string `json:"a"`
+	B      string `json:"b"`
+}
+
+// TrackDiff differences between latest releases of two tracks
+type TrackDiff struct {
+	A TrackRelease `json:"a"`
+	B TrackRelease `json:"b"`
+	// appVersions released on A but not on B, pending promotion from A to B
+	OnlyInA []int64 `json:"onlyInA"`
+	OnlyInB []int64 `json:"onlyInB"`
+	// release status or user fraction differ
+	RolloutDiffers bool               `json:"rolloutDiffers"`
+	ReleaseNotes   []ReleaseNotesDiff `json:"releaseNotes"`
+}
+
+// Same reports tracks have the same latest release
+func (d *TrackDiff) Same() bool {
+	return len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0 && !d.RolloutDiffers && len(d.ReleaseNotes) == 0
+}
+
+// DiffTracks compares latest releases of two tracks e.g. beta and production, using a throwaway edit
+func DiffTracks(ctx context.Context, gs IGService, packageName, trackA, trackB string) (*TrackDiff, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	a := strings.TrimSpace(strings.ToLower(trackA))
+	b := strings.TrimSpace(strings.ToLower(trackB))
+	if a == "" || b == "" {
+		return nil, fmt.Errorf("two tracks to compare are required")
+	}
+	if a == b {
+		return nil, fmt.Errorf("track '%s' can't be compared with itself", a)
+	}
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer gs.deleteEdit(ctx, name, edit)
+
+	diff := &TrackDiff{}
+	for _, side := range []struct {
+		track   string
+		release *TrackRelease
+	}{{a, &diff.A}, {b, &diff.B}} {
+		track, err := gs.getTrack(ctx, name, edit, side.track)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading '%s' track: %w", side.track, err)
+		}
+		*side.release = trackRelease(side.track, track)
+	}
+
+	versionsA, versionsB := diff.A.versionCodes(), diff.B.versionCodes()
+	diff.OnlyInA = missingVersionCodes(versionsA, versionsB)
+	diff.OnlyInB = missingVersionCodes(versionsB, versionsA)
+	if ra, rb := diff.A.Release, diff.B.Release; ra != nil && rb != nil {
+		diff.RolloutDiffers = ra.Status != rb.Status || ra.UserFraction != rb.UserFraction
+	} else {
+		diff.RolloutDiffers = ra != rb
+	}
+	diff.ReleaseNotes = releaseNotesDiff(diff.A.ReleaseNotes, diff.B.ReleaseNotes)
+	return diff, nil
+}
+
+// trackRelease summary of the latest release on track
+func trackRelease(name string, track *androidpublisher.Track) TrackRelease {
+	tr := TrackRelease{Track: name}
+	r := latestRelease(track)
+	if r == nil {
+		return tr
+	}
+	info := releaseInfo(r)
+	tr.Release = &info
+	if len(r.ReleaseNotes) > 0 {
+		tr.ReleaseNotes = make(map[string]string, len(r.ReleaseNotes))
+		for _, n := range r.ReleaseNotes {
+			tr.ReleaseNotes[n.Language] = n.Text
+		}
+	}
+	return tr
+}
+
+func (tr TrackRelease) versionCodes() []int64 {
+	if tr.Release == nil {
+		return nil
+	}
+	return tr.Release.VersionCodes
+}
+
+// missingVersionCodes returns appVersions of from not in other, sorted
+func missingVersionCodes(from, other []int64) []int64 {
+	in := make(map[int64]bool, len(other))
+	for _, v := range other {
+		in[v] = true
+	}
+	missing := []int64{}
+	for _, v := range from {
+		if !in[v] {
+			missing = append(missing, v)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	return missing
+}
+
+// releaseNotesDiff returns locales whose notes differ, sorted by locale
+func releaseNotesDiff(a, b map[string]string) []ReleaseNotesDiff {
+	locales := make(map[string]bool, len(a)+len(b))
+	for l := range a {
+		locales[l] = true
+	}
+	for l := range b {
+		locales[l] = true
+	}
+	diffs := []ReleaseNotesDiff{}
+	for l := range locales {
+		if a[l] != b[l] {
+			diffs = append(diffs, ReleaseNotesDiff{Locale: l, A: a[l], B: b[l]})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Locale < diffs[j].Locale })
+	return diffs
+}
@@ -0,0 +1,78 @@
+package playstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestDiffTracks(t *testing.T) {
+
+	t.Run("should report versions, rollout and notes pending promotion", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{
+			TrackBeta: {Track: TrackBeta, Releases: []*androidpublisher.TrackRelease{
+				{Status: StatusCompleted, VersionCodes: []int64{12, 13}, ReleaseNotes: []*androidpublisher.LocalizedText{{Language: "en-US", Text: "New"}, {Language: "de-DE", Text: "Neu"}}},
+			}},
+			TrackProduction: {Track: TrackProduction, Releases: []*androidpublisher.TrackRelease{
+				{Status: StatusCompleted, VersionCodes: []int64{10}, ReleaseNotes: []*androidpublisher.LocalizedText{{Language: "en-US", Text: "Old"}}},
+				{Status: StatusInProgress, UserFraction: 0.2, VersionCodes: []int64{11, 12}, ReleaseNotes: []*androidpublisher.LocalizedText{{Language: "de-DE", Text: "Neu"}}},
+			}},
+		}}
+
+		// Act
+		diff, err := DiffTracks(context.Background(), gs, "com.test.app", "Beta", TrackProduction)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(diff.OnlyInA, []int64{13}) || !reflect.DeepEqual(diff.OnlyInB, []int64{11}) {
+			t.Errorf("want 13 only on beta and 11 only on production, got %v and %v", diff.OnlyInA, diff.OnlyInB)
+		}
+		if !diff.RolloutDiffers || diff.Same() {
+			t.Error("want rollout difference reported")
+		}
+		expected := []ReleaseNotesDiff{{Locale: "en-US", A: "New"}}
+		if !reflect.DeepEqual(diff.ReleaseNotes, expected) {
+			t.Errorf("want %+v, got %+v", expected, diff.ReleaseNotes)
+		}
+		if gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted, got %d deleteEdit calls", gs.deleteEditCount)
+		}
+	})
+
+	t.Run("should report same latest releases", func(t *testing.T) {
+		// Arrange
+		release := func() *androidpublisher.TrackRelease {
+			return &androidpublisher.TrackRelease{Status: StatusCompleted, VersionCodes: []int64{12}}
+		}
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{
+			TrackBeta:       {Track: TrackBeta, Releases: []*androidpublisher.TrackRelease{release()}},
+			TrackProduction: {Track: TrackProduction, Releases: []*androidpublisher.TrackRelease{release()}},
+		}}
+
+		// Act
+		diff, err := DiffTracks(context.Background(), gs, "com.test.app", TrackBeta, TrackProduction)
+
+		// Assert
+		if err != nil || !diff.Same() {
+			t.Errorf("want same tracks, got %+v: %v", diff, err)
+		}
+	})
+
+	t.Run("should refuse comparing track with itself", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		_, err := DiffTracks(context.Background(), gs, "com.test.app", TrackBeta, " beta")
+
+		// Assert
+		if err == nil || gs.createEditCount != 0 {
+			t.Errorf("want error before edit is created, got %d edits: %v", gs.createEditCount, err)
+		}
+	})
+}
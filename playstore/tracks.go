@@ -0,0 +1,234 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+// TrackInfo track with its releases
+type TrackInfo struct {
+	Track    string        `json:"track"`
+	Releases []ReleaseInfo `json:"releases"`
+}
+
+// ReleaseInfo summary of a single track release
+type ReleaseInfo struct {
+	Name         string  `json:"name,omitempty"`
+	Status       string  `json:"status"`
+	VersionCodes []int64 `json:"versionCodes"`
+	UserFraction float64 `json:"userFraction,omitempty"`
+	// countries release is available in, every country if empty
+	Countries          []string `json:"countries,omitempty"`
+	IncludeRestOfWorld bool     `json:"includeRestOfWorld,omitempty"`
+}
+
+// ListTracks returns every track of the app with its releases, using a throwaway edit
+func ListTracks(ctx context.Context, gs IGService, packageName string) ([]TrackInfo, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer gs.deleteEdit(ctx, name, edit)
+
+	tracks, err := gs.listTracks(ctx, name, edit)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]TrackInfo, 0, len(tracks))
+	for _, t := range tracks {
+		info := TrackInfo{Track: t.Track, Releases: make([]ReleaseInfo, 0, len(t.Releases))}
+		for _, r := range t.Releases {
+			info.Releases = append(info.Releases, releaseInfo(r))
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// releaseInfo summary of track release
+func releaseInfo(r *androidpublisher.TrackRelease) ReleaseInfo {
+	ri := ReleaseInfo{
+		Name:         r.Name,
+		Status:       r.Status,
+		VersionCodes: []int64(r.VersionCodes),
+		UserFraction: r.UserFraction,
+	}
+	if r.CountryTargeting != nil {
+		ri.Countries = r.CountryTargeting.Countries
+		ri.IncludeRestOfWorld = r.CountryTargeting.IncludeRestOfWorld
+	}
+	return ri
+}
+
+// PruneReport describes what track prune changed and what it had to leave as is
+type PruneReport struct {
+	Track   string   `json:"track"`
+	Trimmed []string `json:"trimmed"` // releases which had name and notes removed
+	Kept    []string `json:"kept"`    // newest prunable releases left untouched
+	Skipped []string `json:"skipped"` // releases which can't be pruned with a reason
+}
+
+// PruneTracks removes name and release notes from draft and halted releases on a track, except for the newest
+// keep ones. Those are the releases Play allows changing which no users get. Completed and in progress releases
+// are serving, Play refuses changes to the former and changing the latter would change what users get, so they
+// are skipped with a warning logged to l, stderr if nil, before track is touched.
+func PruneTracks(ctx context.Context, gs IGService, packageName, trackName string, keep int, l Logger) (*PruneReport, error) {
+	if gs == nil {
+		return nil, errors.New("no Google Playstore service instance provided")
+	}
+	name, err := validatePackageName(packageName, false)
+	if err != nil {
+		return nil, err
+	}
+	t := strings.TrimSpace(strings.ToLower(trackName))
+	if t == "" {
+		return nil, fmt.Errorf("track name to prune is required")
+	}
+	if keep < 0 {
+		return nil, fmt.Errorf("number of releases to keep must not be negative, got %d", keep)
+	}
+
+	edit, _, err := gs.createEdit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	track, err := gs.getTrack(ctx, name, edit, t)
+	if err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+
+	if l == nil {
+		l = defaultLogger
+	}
+	report := pruneReleases(track, keep, l)
+	if len(report.Trimmed) == 0 {
+		gs.deleteEdit(ctx, name, edit)
+		return report, nil
+	}
+
+	if err := gs.updateTrack(ctx, name, edit, track); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, fmt.Errorf("failed updating track '%s': %w", t, err)
+	}
+	if err := gs.validateEdit(ctx, name, edit); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	if err := gs.commitEdit(ctx, name, edit, false); err != nil {
+		gs.deleteEdit(ctx, name, edit)
+		return nil, err
+	}
+	return report, nil
+}
+
+// pruneReleases trims track releases in place and reports what was done
+func pruneReleases(track *androidpublisher.Track, keep int, l Logger) *PruneReport {
+	report := &PruneReport{Track: track.Track}
+
+	prunable := make([]*androidpublisher.TrackRelease, 0)
+	for _, r := range track.Releases {
+		if r.Status != StatusDraft && r.Status != StatusHalted {
+			skipped := fmt.Sprintf("%s: release is '%s' and serving, only draft and halted releases are pruned", releaseLabel(r), r.Status)
+			l.Warnf("not pruning %s", skipped)
+			report.Skipped = append(report.Skipped, skipped)
+			continue
+		}
+		prunable = append(prunable, r)
+	}
+
+	// newest first
+	sort.SliceStable(prunable, func(i, j int) bool {
+		return maxVersionCode(prunable[i]) > maxVersionCode(prunable[j])
+	})
+
+	for i, r := range prunable {
+		if i < keep {
+			report.Kept = append(report.Kept, releaseLabel(r))
+			continue
+		}
+		if r.Name == "" && len(r.ReleaseNotes) == 0 {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s: nothing to remove", releaseLabel(r)))
+			continue
+		}
+		report.Trimmed = append(report.Trimmed, releaseLabel(r))
+		r.Name = ""
+		r.ReleaseNotes = nil
+	}
+	return report
+}
+
+// releaseLabel human readable release identifier
+func releaseLabel(r *androidpublisher.TrackRelease) string {
+	codes := make([]string, 0, len(r.VersionCodes))
+	for _, v := range r.VersionCodes {
+		codes = append(codes, fmt.Sprintf("%d", v))
+	}
+	label := "versionCodes [" + strings.Join(codes, ", ") + "]"
+	if r.Name != "" {
+		label = fmt.Sprintf("'%s' %s", r.Name, label)
+	}
+	return label
+}
+
+func maxVersionCode(r *androidpublisher.TrackRelease) int64 {
+	var m int64
+	for _, v := range r.VersionCodes {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
+
+// WithCustomTracks accepts closed testing tracks created in Play Console e.g. 'qa-team', besides the standard
+// ones. Track is checked against tracks app has on Play once edit is open.
+func WithCustomTracks() Option {
+	return func(p *publish) {
+		p.customTracks = true
+	}
+}
+
+// isStandardTrack checks track, without profile prefix, is one every app has
+func isStandardTrack(track string) bool {
+	return track == TrackInternal || track == TrackAlpha || track == TrackBeta || track == TrackProduction
+}
+
+// checkTrack fails with ErrUnsupportedTrack when custom track isn't one of the app tracks on Play
+func (p *publish) checkTrack(ctx context.Context, gs IGService, edit, track string) error {
+	pr := p.profile
+	if pr == nil {
+		pr = &ProfileDefault
+	}
+	if !p.customTracks || isStandardTrack(pr.baseTrack(track)) {
+		return nil
+	}
+	tracks, err := gs.listTracks(ctx, p.packageName, edit)
+	if err != nil {
+		return fmt.Errorf("failed listing tracks to find '%s': %w", track, err)
+	}
+	names := make([]string, 0, len(tracks))
+	for _, t := range tracks {
+		if t.Track == track {
+			return nil
+		}
+		names = append(names, t.Track)
+	}
+	sort.Strings(names)
+	return errorOf(ErrUnsupportedTrack, "track '%s' not found on Play, app has tracks '%s'", track, strings.Join(names, "' '"))
+}
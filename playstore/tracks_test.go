@@ -0,0 +1,185 @@
+package playstore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestListTracks(t *testing.T) {
+
+	t.Run("should return releases of every track", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{
+			TrackBeta: {Track: TrackBeta, Releases: []*androidpublisher.TrackRelease{{Name: "1.1", Status: StatusDraft, VersionCodes: []int64{2}}}},
+			TrackProduction: {Track: TrackProduction, Releases: []*androidpublisher.TrackRelease{{Status: StatusInProgress, VersionCodes: []int64{1}, UserFraction: 0.2,
+				CountryTargeting: &androidpublisher.CountryTargeting{Countries: []string{"US", "DE"}, IncludeRestOfWorld: true}}}},
+		}}
+
+		// Act
+		tracks, err := ListTracks(context.Background(), gs, "com.test.app")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		expected := []TrackInfo{
+			{Track: TrackBeta, Releases: []ReleaseInfo{{Name: "1.1", Status: StatusDraft, VersionCodes: []int64{2}}}},
+			{Track: TrackProduction, Releases: []ReleaseInfo{{Status: StatusInProgress, VersionCodes: []int64{1}, UserFraction: 0.2, Countries: []string{"US", "DE"}, IncludeRestOfWorld: true}}},
+		}
+		if !reflect.DeepEqual(tracks, expected) {
+			t.Errorf("want %+v, got %+v", expected, tracks)
+		}
+		if gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted, got %d deleteEdit calls", gs.deleteEditCount)
+		}
+	})
+}
+
+func TestPruneTracks(t *testing.T) {
+
+	t.Run("should trim all but newest draft and halted releases", func(t *testing.T) {
+		// Arrange
+		notes := []*androidpublisher.LocalizedText{{Language: "en-US", Text: "fixes"}}
+		track := &androidpublisher.Track{
+			Track: TrackBeta,
+			Releases: []*androidpublisher.TrackRelease{
+				{Name: "1.0", Status: StatusHalted, VersionCodes: []int64{1}, ReleaseNotes: notes},
+				{Name: "1.2", Status: StatusDraft, VersionCodes: []int64{3}, ReleaseNotes: notes},
+				{Name: "1.1", Status: StatusDraft, VersionCodes: []int64{2}, ReleaseNotes: notes},
+				{Name: "1.3", Status: StatusInProgress, VersionCodes: []int64{4}, UserFraction: 0.1},
+			},
+		}
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: track}}
+
+		// Act
+		report, err := PruneTracks(context.Background(), gs, "com.test.app", TrackBeta, 1, &recordingLogger{})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if !reflect.DeepEqual(report.Trimmed, []string{"'1.1' versionCodes [2]", "'1.0' versionCodes [1]"}) {
+			t.Errorf("want 1.1 and 1.0 trimmed, got %v", report.Trimmed)
+		}
+		if len(report.Skipped) != 1 {
+			t.Errorf("want in progress release skipped, got %v", report.Skipped)
+		}
+		if track.Releases[1].Name != "1.2" || track.Releases[0].Name != "" || track.Releases[0].ReleaseNotes != nil {
+			t.Errorf("want only newest draft release named, got %+v", track.Releases)
+		}
+		if track.Releases[3].Name != "1.3" {
+			t.Errorf("want in progress release untouched, got %+v", track.Releases[3])
+		}
+		if len(gs.updatedTracks) != 1 || gs.commitEditCount != 1 {
+			t.Errorf("want 1 track update committed, got %d updates %d commits", len(gs.updatedTracks), gs.commitEditCount)
+		}
+	})
+
+	t.Run("should skip serving completed release without touching track", func(t *testing.T) {
+		// Arrange
+		notes := []*androidpublisher.LocalizedText{{Language: "en-US", Text: "fixes"}}
+		track := &androidpublisher.Track{
+			Track:    TrackBeta,
+			Releases: []*androidpublisher.TrackRelease{{Name: "1.0", Status: StatusCompleted, VersionCodes: []int64{1}, ReleaseNotes: notes}},
+		}
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: track}}
+
+		l := &recordingLogger{}
+
+		// Act
+		report, err := PruneTracks(context.Background(), gs, "com.test.app", TrackBeta, 0, l)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(report.Trimmed) != 0 || len(report.Skipped) != 1 || !strings.Contains(report.Skipped[0], "'completed'") {
+			t.Errorf("want completed release skipped, got %+v", report)
+		}
+		if !l.has("WARN not pruning '1.0' versionCodes [1]") {
+			t.Errorf("want skip warned to given logger, got %v", l.lines)
+		}
+		if track.Releases[0].Name != "1.0" || len(gs.updatedTracks) != 0 || gs.commitEditCount != 0 {
+			t.Errorf("want track untouched, got %+v with %d updates", track.Releases[0], len(gs.updatedTracks))
+		}
+	})
+
+	t.Run("should not commit when nothing to prune", func(t *testing.T) {
+		// Arrange
+		gs := &mockGService{}
+
+		// Act
+		_, err := PruneTracks(context.Background(), gs, "com.test.app", TrackBeta, 5, nil)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.commitEditCount != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted without commit, got %d commits %d deletes", gs.commitEditCount, gs.deleteEditCount)
+		}
+	})
+}
+
+func TestCustomTracks(t *testing.T) {
+
+	t.Run("should refuse custom track without option", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+
+		// Act
+		_, err := Publish(context.Background(), fs, "com.test.app", "qa-team", "auth.json", []binary{Binary("test.aab")}, false, false)
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedTrack) {
+			t.Errorf("want '%v', got: %v", ErrUnsupportedTrack, err)
+		}
+	})
+
+	t.Run("should release to custom track app has on Play", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createMockBinary(t, fs, "test.aab", "")
+		gs := &mockGService{AppVersionCode: 42, tracks: map[string]*androidpublisher.Track{"qa-team": {Track: "qa-team"}}}
+		publish, _ := Publish(context.Background(), fs, "com.test.app", "QA-Team", "auth.json", []binary{Binary("test.aab")}, false, false, WithCustomTracks())
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if gs.releaseTrack != "qa-team" {
+			t.Errorf("want release on 'qa-team', got '%s'", gs.releaseTrack)
+		}
+	})
+
+	t.Run("should fail before upload when custom track isn't on Play", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		fs.Create("auth.json")
+		createMockBinary(t, fs, "test.aab", "")
+		gs := &mockGService{tracks: map[string]*androidpublisher.Track{TrackBeta: {Track: TrackBeta}}}
+		publish, _ := Publish(context.Background(), fs, "com.test.app", "qa-team", "auth.json", []binary{Binary("test.aab")}, false, false, WithCustomTracks())
+
+		// Act
+		_, err := publish.UploadFiles(context.Background(), gs)
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedTrack) {
+			t.Errorf("want '%v', got: %v", ErrUnsupportedTrack, err)
+		}
+		if gs.uploadBundleCallCount != 0 || gs.deleteEditCount != 1 {
+			t.Errorf("want edit deleted without uploads, got %d uploads %d deletes", gs.uploadBundleCallCount, gs.deleteEditCount)
+		}
+	})
+}
@@ -1,18 +1,27 @@
 package playstore
 
 import (
+	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/hex"
 	"io"
-	"log"
 )
 
 func (p *publish) Debugf(format string, v ...any) {
 	if p.verbose {
-		log.Printf(format, v...)
+		p.log().Debugf(format, v...)
 	}
 }
 
+func (p *publish) Infof(format string, v ...any) {
+	p.log().Infof(format, v...)
+}
+
+func (p *publish) Warnf(format string, v ...any) {
+	p.log().Warnf(format, v...)
+	p.noteSoftFailure(SoftFailureWarning, format, v...)
+}
+
 func (p *publish) fileExits(file string) bool {
 	if _, err := p.fs.Stat(file); err == nil {
 		return true
@@ -20,12 +29,12 @@ func (p *publish) fileExits(file string) bool {
 	return false
 }
 
-func (p *publish) fileSize(file string) int64 {
+func (p *publish) fileSize(file string) (int64, error) {
 	s, err := p.fs.Stat(file)
 	if err != nil {
-		log.Fatal(err)
+		return 0, err
 	}
-	return s.Size()
+	return s.Size(), nil
 }
 
 // TODO: must be better way to do this
@@ -39,3 +48,25 @@ func fileSha256(r io.ReadSeeker) (string, error) {
 	}
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
+
+// fileHashes calculates sha256 and sha1 of the content in a single read and rewinds it
+func fileHashes(r io.ReadSeeker) (Hashes, error) {
+	h256 := sha256.New()
+	h1 := sha1.New()
+	if _, err := io.Copy(io.MultiWriter(h256, h1), r); err != nil {
+		return Hashes{}, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return Hashes{}, err
+	}
+	return Hashes{Sha256: hex.EncodeToString(h256.Sum(nil)), Sha1: hex.EncodeToString(h1.Sum(nil))}, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
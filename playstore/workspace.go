@@ -0,0 +1,79 @@
+package playstore
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	workspacePrefix = "pstore-"
+)
+
+// workspace is a temporary directory private to a single publish, for downloads, extracted archives
+// and generated files. Directory is created on first use, so publishes not needing it leave no trace.
+type workspace struct {
+	mu      sync.Mutex
+	fs      afero.Fs
+	dir     string
+	removed bool
+}
+
+func newWorkspace(fs afero.Fs) *workspace {
+	return &workspace{fs: fs}
+}
+
+// path returns path for a file within workspace, creating workspace directory if needed
+func (w *workspace) path(name string) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.removed {
+		return "", fmt.Errorf("workspace already cleaned up, can't use it for '%s'", name)
+	}
+	if w.dir == "" {
+		dir, err := afero.TempDir(w.fs, "", workspacePrefix)
+		if err != nil {
+			return "", fmt.Errorf("failed creating temporary workspace: %w", err)
+		}
+		w.dir = dir
+	}
+	return filepath.Join(w.dir, filepath.Clean("/"+name)), nil
+}
+
+// create creates file in workspace, parent directories included
+func (w *workspace) create(name string) (afero.File, error) {
+	p, err := w.path(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.fs.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return nil, err
+	}
+	return w.fs.Create(p)
+}
+
+// createTemp creates uniquely named file in workspace, see os.CreateTemp for pattern
+func (w *workspace) createTemp(pattern string) (afero.File, error) {
+	dir, err := w.path("")
+	if err != nil {
+		return nil, err
+	}
+	return afero.TempFile(w.fs, dir, pattern)
+}
+
+// cleanup removes workspace with everything in it, safe to call multiple times
+func (w *workspace) cleanup() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.removed = true
+	if w.dir == "" {
+		return nil
+	}
+	dir := w.dir
+	w.dir = ""
+	return w.fs.RemoveAll(dir)
+}
@@ -0,0 +1,114 @@
+package playstore
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestWorkspace(t *testing.T) {
+
+	t.Run("should not create directory until used", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		ws := newWorkspace(fs)
+
+		// Act
+		err := ws.cleanup()
+
+		// Assert
+		if err != nil {
+			t.Errorf("want no error, got: %v", err)
+		}
+		if ws.dir != "" {
+			t.Errorf("want no workspace directory, got '%s'", ws.dir)
+		}
+	})
+
+	t.Run("should remove created files on cleanup", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		ws := newWorkspace(fs)
+		f, err := ws.create("downloads/app.aab")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		// Act
+		if err := ws.cleanup(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		if ok, _ := afero.Exists(fs, f.Name()); ok {
+			t.Errorf("want '%s' removed, but it exists", f.Name())
+		}
+		if err := ws.cleanup(); err != nil {
+			t.Errorf("want repeated cleanup to succeed, got: %v", err)
+		}
+	})
+
+	t.Run("should keep files within workspace directory", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		ws := newWorkspace(fs)
+
+		// Act
+		p, err := ws.path("../../etc/passwd")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(p, ws.dir) {
+			t.Errorf("want path within '%s', got '%s'", ws.dir, p)
+		}
+	})
+
+	t.Run("should fail when used after cleanup", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		ws := newWorkspace(fs)
+		ws.cleanup()
+
+		// Act
+		_, err := ws.create("app.aab")
+
+		// Assert
+		if err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	t.Run("should share one directory between concurrent users", func(t *testing.T) {
+		// Arrange
+		fs := afero.NewMemMapFs()
+		ws := newWorkspace(fs)
+		var wg sync.WaitGroup
+
+		// Act
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if f, err := ws.create(fmt.Sprintf("file%d", i)); err == nil {
+					f.Close()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		// Assert
+		entries, err := afero.ReadDir(fs, ws.dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 10 {
+			t.Errorf("want 10 files in workspace, got %d", len(entries))
+		}
+	})
+}
@@ -0,0 +1,449 @@
+// Package playstoretest provides in-memory fake of Google Play for testing release pipelines built with
+// playstore, e.g. together with afero.MemMapFs binaries are read from
+package playstoretest
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// Call API call Fake received, with content of uploads it read
+type Call struct {
+	Method      string
+	PackageName string
+	EditID      string
+	// version code, track, locale etc. call was made for, in argument order
+	Args    []string
+	Content []byte
+}
+
+// Fake in-memory Google Play of playstore.API, get IGService of it with Service. Uploads get version codes
+// counting from NextVersionCode, track changes show up in Tracks once their edit is committed, while
+// listings, images and details change right away. Calls within edits not open fail with 404, like on Play.
+// Fields set up Fake before use, once calls may run read it with Calls and Track, safe for parallel uploads.
+type Fake struct {
+	mu sync.Mutex
+	// version code of the next uploaded binary, 1 if not set
+	NextVersionCode int64
+	// bundles and apks on Play by version code, uploads are added
+	Hashes map[int64]playstore.Hashes
+	// committed tracks by name
+	Tracks   map[string]*androidpublisher.Track
+	Listings []playstore.Listing
+	// sha256 of images by <locale>/<imageType>
+	Images  map[string][]string
+	Reviews []playstore.Review
+	Details *playstore.AppDetails
+	// edits open and their track changes by edit ID
+	edits  map[string]map[string]*androidpublisher.Track
+	lastID int
+	calls  []Call
+	errors map[string][]error
+}
+
+// New returns Fake without apps content
+func New() *Fake {
+	return &Fake{}
+}
+
+// Service returns IGService making its calls to f
+func (f *Fake) Service() playstore.IGService {
+	return playstore.ServiceFromAPI(f)
+}
+
+// Fail makes next calls of method e.g. "CommitEdit" fail with errs, one call per error in order given.
+// nil lets a call succeed.
+func (f *Fake) Fail(method string, errs ...error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.errors == nil {
+		f.errors = make(map[string][]error)
+	}
+	f.errors[method] = append(f.errors[method], errs...)
+}
+
+// Calls returns calls made so far in order received, of given methods if any set
+func (f *Fake) Calls(methods ...string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]Call, 0, len(f.calls))
+	for _, c := range f.calls {
+		if len(methods) == 0 || contains(methods, c.Method) {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Track returns committed track, nil if it has no releases yet
+func (f *Fake) Track(name string) *androidpublisher.Track {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Tracks[name]
+}
+
+// call records call and returns failure injected for it. Callers hold f.mu.
+func (f *Fake) call(method, packageName, editID string, content []byte, args ...string) error {
+	f.calls = append(f.calls, Call{Method: method, PackageName: packageName, EditID: editID, Args: args, Content: content})
+	errs := f.errors[method]
+	if len(errs) == 0 {
+		return nil
+	}
+	f.errors[method] = errs[1:]
+	return errs[0]
+}
+
+// editCall records call within edit, failing when edit isn't open
+func (f *Fake) editCall(method, packageName, editID string, content []byte, args ...string) error {
+	if err := f.call(method, packageName, editID, content, args...); err != nil {
+		return err
+	}
+	if _, ok := f.edits[editID]; !ok {
+		return &googleapi.Error{Code: http.StatusNotFound, Message: fmt.Sprintf("edit '%s' not found", editID)}
+	}
+	return nil
+}
+
+func (f *Fake) CreateEdit(ctx context.Context, packageName string) (string, time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("CreateEdit", packageName, "", nil); err != nil {
+		return "", time.Time{}, err
+	}
+	if f.edits == nil {
+		f.edits = make(map[string]map[string]*androidpublisher.Track)
+	}
+	f.lastID++
+	id := strconv.Itoa(f.lastID)
+	f.edits[id] = make(map[string]*androidpublisher.Track)
+	return id, time.Now().Add(time.Hour), nil
+}
+
+func (f *Fake) ValidateEdit(ctx context.Context, packageName, editId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.editCall("ValidateEdit", packageName, editId, nil)
+}
+
+func (f *Fake) DeleteEdit(ctx context.Context, packageName, editId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("DeleteEdit", packageName, editId, nil); err != nil {
+		return err
+	}
+	delete(f.edits, editId)
+	return nil
+}
+
+func (f *Fake) CommitEdit(ctx context.Context, packageName, editId string, changesNotSentForReview bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("CommitEdit", packageName, editId, nil, strconv.FormatBool(changesNotSentForReview)); err != nil {
+		return err
+	}
+	if f.Tracks == nil {
+		f.Tracks = make(map[string]*androidpublisher.Track)
+	}
+	for name, t := range f.edits[editId] {
+		f.Tracks[name] = t
+	}
+	delete(f.edits, editId)
+	return nil
+}
+
+func (f *Fake) GetEdit(ctx context.Context, packageName, editId string) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("GetEdit", packageName, editId, nil); err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(time.Hour), nil
+}
+
+func (f *Fake) UploadBundle(ctx context.Context, r io.Reader, packageName, editId string) (int64, string, error) {
+	return f.upload("UploadBundle", r, packageName, editId)
+}
+
+func (f *Fake) UploadApk(ctx context.Context, r io.Reader, packageName, editId string) (int64, string, error) {
+	return f.upload("UploadApk", r, packageName, editId)
+}
+
+// upload adds binary to Hashes under the next version code
+func (f *Fake) upload(method string, r io.Reader, packageName, editId string) (int64, string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return 0, "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall(method, packageName, editId, b); err != nil {
+		return 0, "", err
+	}
+	if f.NextVersionCode == 0 {
+		f.NextVersionCode = 1
+	}
+	if f.Hashes == nil {
+		f.Hashes = make(map[int64]playstore.Hashes)
+	}
+	v := f.NextVersionCode
+	f.NextVersionCode++
+	s256, s1 := sha256.Sum256(b), sha1.Sum(b)
+	f.Hashes[v] = playstore.Hashes{Sha256: hex.EncodeToString(s256[:]), Sha1: hex.EncodeToString(s1[:])}
+	return v, f.Hashes[v].Sha256, nil
+}
+
+func (f *Fake) UploadProguardMapping(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error {
+	return f.uploadFile("UploadProguardMapping", r, packageName, editId, strconv.FormatInt(appVersionCode, 10))
+}
+
+func (f *Fake) UploadNativeSymbols(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64) error {
+	return f.uploadFile("UploadNativeSymbols", r, packageName, editId, strconv.FormatInt(appVersionCode, 10))
+}
+
+func (f *Fake) UploadExpansionFile(ctx context.Context, r io.Reader, packageName, editId string, appVersionCode int64, fileType string) error {
+	return f.uploadFile("UploadExpansionFile", r, packageName, editId, strconv.FormatInt(appVersionCode, 10), fileType)
+}
+
+// uploadFile records upload of a file Play only stores
+func (f *Fake) uploadFile(method string, r io.Reader, packageName, editId string, args ...string) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.editCall(method, packageName, editId, b, args...)
+}
+
+func (f *Fake) CreateRelease(ctx context.Context, packageName, editId, trackName string, release *androidpublisher.TrackRelease) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("CreateRelease", packageName, editId, nil, trackName); err != nil {
+		return err
+	}
+	f.edits[editId][trackName] = &androidpublisher.Track{Track: trackName, Releases: []*androidpublisher.TrackRelease{release}}
+	return nil
+}
+
+func (f *Fake) VersionHashes(ctx context.Context, packageName, editId string) (map[int64]playstore.Hashes, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("VersionHashes", packageName, editId, nil); err != nil {
+		return nil, err
+	}
+	hashes := make(map[int64]playstore.Hashes, len(f.Hashes))
+	for v, h := range f.Hashes {
+		hashes[v] = h
+	}
+	return hashes, nil
+}
+
+func (f *Fake) ListTracks(ctx context.Context, packageName, editId string) ([]*androidpublisher.Track, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("ListTracks", packageName, editId, nil); err != nil {
+		return nil, err
+	}
+	tracks := make([]*androidpublisher.Track, 0, len(f.Tracks))
+	for name := range f.Tracks {
+		tracks = append(tracks, f.track(editId, name))
+	}
+	for name, t := range f.edits[editId] {
+		if _, ok := f.Tracks[name]; !ok {
+			tracks = append(tracks, t)
+		}
+	}
+	return tracks, nil
+}
+
+func (f *Fake) GetTrack(ctx context.Context, packageName, editId, trackName string) (*androidpublisher.Track, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("GetTrack", packageName, editId, nil, trackName); err != nil {
+		return nil, err
+	}
+	return f.track(editId, trackName), nil
+}
+
+// track returns track as seen within edit, empty one for track without releases
+func (f *Fake) track(editId, name string) *androidpublisher.Track {
+	if t, ok := f.edits[editId][name]; ok {
+		return t
+	}
+	if t, ok := f.Tracks[name]; ok {
+		return t
+	}
+	return &androidpublisher.Track{Track: name}
+}
+
+func (f *Fake) UpdateTrack(ctx context.Context, packageName, editId string, track *androidpublisher.Track) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("UpdateTrack", packageName, editId, nil, track.Track); err != nil {
+		return err
+	}
+	f.edits[editId][track.Track] = track
+	return nil
+}
+
+func (f *Fake) ListListings(ctx context.Context, packageName, editId string) ([]playstore.Listing, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("ListListings", packageName, editId, nil); err != nil {
+		return nil, err
+	}
+	return append([]playstore.Listing(nil), f.Listings...), nil
+}
+
+func (f *Fake) UpdateListing(ctx context.Context, packageName, editId string, listing playstore.Listing) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("UpdateListing", packageName, editId, nil, listing.Locale); err != nil {
+		return err
+	}
+	for i, l := range f.Listings {
+		if l.Locale == listing.Locale {
+			f.Listings[i] = listing
+			return nil
+		}
+	}
+	f.Listings = append(f.Listings, listing)
+	return nil
+}
+
+func (f *Fake) DeleteListing(ctx context.Context, packageName, editId, locale string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("DeleteListing", packageName, editId, nil, locale); err != nil {
+		return err
+	}
+	for i, l := range f.Listings {
+		if l.Locale == locale {
+			f.Listings = append(f.Listings[:i], f.Listings[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *Fake) DeleteAllImages(ctx context.Context, packageName, editId, locale, imageType string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("DeleteAllImages", packageName, editId, nil, locale, imageType); err != nil {
+		return 0, err
+	}
+	key := locale + "/" + imageType
+	deleted := len(f.Images[key])
+	delete(f.Images, key)
+	return deleted, nil
+}
+
+func (f *Fake) UploadImage(ctx context.Context, r io.Reader, packageName, editId, locale, imageType string) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("UploadImage", packageName, editId, b, locale, imageType); err != nil {
+		return err
+	}
+	if f.Images == nil {
+		f.Images = make(map[string][]string)
+	}
+	sum := sha256.Sum256(b)
+	key := locale + "/" + imageType
+	f.Images[key] = append(f.Images[key], hex.EncodeToString(sum[:]))
+	return nil
+}
+
+func (f *Fake) ListImages(ctx context.Context, packageName, editId, locale, imageType string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("ListImages", packageName, editId, nil, locale, imageType); err != nil {
+		return nil, err
+	}
+	return append([]string(nil), f.Images[locale+"/"+imageType]...), nil
+}
+
+func (f *Fake) ListReviews(ctx context.Context, packageName string) ([]playstore.Review, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ListReviews", packageName, "", nil); err != nil {
+		return nil, err
+	}
+	return append([]playstore.Review(nil), f.Reviews...), nil
+}
+
+func (f *Fake) GetReview(ctx context.Context, packageName, reviewId string) (*playstore.Review, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetReview", packageName, "", nil, reviewId); err != nil {
+		return nil, err
+	}
+	for _, r := range f.Reviews {
+		if r.ID == reviewId {
+			return &r, nil
+		}
+	}
+	return nil, &googleapi.Error{Code: http.StatusNotFound, Message: fmt.Sprintf("review '%s' not found", reviewId)}
+}
+
+func (f *Fake) ReplyReview(ctx context.Context, packageName, reviewId, text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ReplyReview", packageName, "", []byte(text), reviewId); err != nil {
+		return err
+	}
+	for i, r := range f.Reviews {
+		if r.ID == reviewId {
+			f.Reviews[i].Reply = text
+			return nil
+		}
+	}
+	return &googleapi.Error{Code: http.StatusNotFound, Message: fmt.Sprintf("review '%s' not found", reviewId)}
+}
+
+func (f *Fake) GetDetails(ctx context.Context, packageName, editId string) (*playstore.AppDetails, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("GetDetails", packageName, editId, nil); err != nil {
+		return nil, err
+	}
+	if f.Details == nil {
+		return &playstore.AppDetails{}, nil
+	}
+	d := *f.Details
+	return &d, nil
+}
+
+func (f *Fake) UpdateDetails(ctx context.Context, packageName, editId string, details playstore.AppDetails) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.editCall("UpdateDetails", packageName, editId, nil); err != nil {
+		return err
+	}
+	f.Details = &details
+	return nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package playstoretest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/sigitas-plk/playstore/playstore"
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+)
+
+func TestFake(t *testing.T) {
+
+	publish := func(t *testing.T, fs afero.Fs) interface {
+		UploadFiles(context.Context, playstore.IGService) ([]playstore.UploadResult, error)
+	} {
+		t.Helper()
+		fs.Create("auth.json")
+		afero.WriteFile(fs, "app.aab", []byte("bundle"), 0o644)
+		afero.WriteFile(fs, "mapping.txt", []byte("com.sample.App -> a:\n"), 0o644)
+		p, err := playstore.Publish(context.Background(), fs, "com.test.app", playstore.TrackInternal, "auth.json",
+			playstore.Binaries(map[string]string{"app.aab": "mapping.txt"}), false, false,
+			playstore.WithProgressFunc(func(string, int64, int64) {}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	t.Run("should release uploaded binary once edit is committed", func(t *testing.T) {
+		// Arrange
+		fake := New()
+		fake.NextVersionCode = 42
+
+		// Act
+		results, err := publish(t, afero.NewMemMapFs()).UploadFiles(context.Background(), fake.Service())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("want no error, got: %v", err)
+		}
+		if len(results) != 1 || results[0].VersionCode != 42 {
+			t.Errorf("want app.aab uploaded as 42, got %+v", results)
+		}
+		track := fake.Track(playstore.TrackInternal)
+		if track == nil || len(track.Releases) != 1 || !reflect.DeepEqual([]int64(track.Releases[0].VersionCodes), []int64{42}) {
+			t.Errorf("want release of 42 on internal track, got %+v", track)
+		}
+		mappings := fake.Calls("UploadProguardMapping")
+		if len(mappings) != 1 || !reflect.DeepEqual(mappings[0].Args, []string{"42"}) || !bytes.Equal(mappings[0].Content, []byte("com.sample.App -> a:\n")) {
+			t.Errorf("want mapping uploaded for 42, got %+v", mappings)
+		}
+	})
+
+	t.Run("should fail calls with injected errors in order", func(t *testing.T) {
+		// Arrange
+		fake := New()
+		fake.Fail("CommitEdit", &googleapi.Error{Code: http.StatusBadRequest, Message: "refused"})
+
+		// Act
+		_, err := publish(t, afero.NewMemMapFs()).UploadFiles(context.Background(), fake.Service())
+
+		// Assert
+		var ae *playstore.APIError
+		if !errors.As(err, &ae) || ae.StatusCode != http.StatusBadRequest {
+			t.Fatalf("want 400 APIError, got: %v", err)
+		}
+		if fake.Track(playstore.TrackInternal) != nil {
+			t.Error("want nothing released")
+		}
+		if n := len(fake.Calls("DeleteEdit")); n != 1 {
+			t.Errorf("want failed edit deleted, got %d deletes", n)
+		}
+	})
+
+	t.Run("should refuse calls within edit not open", func(t *testing.T) {
+		// Arrange
+		fake := New()
+
+		// Act
+		err := playstore.DeleteEditByID(context.Background(), fake.Service(), "com.test.app", "7")
+
+		// Assert
+		if playstore.StatusCode(err) != http.StatusNotFound {
+			t.Errorf("want 404 error, got: %v", err)
+		}
+		calls := fake.Calls()
+		if len(calls) != 1 || calls[0].Method != "DeleteEdit" || calls[0].EditID != "7" {
+			t.Errorf("want DeleteEdit of edit 7 recorded, got %+v", calls)
+		}
+	})
+}